@@ -0,0 +1,138 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/michohl/osrs-clan-leaderboard/hiscores"
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+	"github.com/michohl/osrs-clan-leaderboard/utils"
+)
+
+// adminNoticeInterval is how long we hold back repeats of the same
+// notice so a problem that happens every run doesn't flood the channel
+const adminNoticeInterval = 6 * time.Hour
+
+// adminNotice is a problem we tell a server's admins about
+type adminNotice struct {
+	// key identifies the problem so repeats of it can be held back
+	key     string
+	title   string
+	problem string
+	hint    string
+}
+
+// sentNotice is when we last sent a notice and how often it
+// happened again since without us sending it
+type sentNotice struct {
+	at         time.Time
+	suppressed int
+}
+
+// adminNotices remembers the notices we've sent to each server
+var adminNotices = struct {
+	sync.Mutex
+	sent map[string]*sentNotice
+}{sent: map[string]*sentNotice{}}
+
+// notifyAdmins tells the server's admin channel about a problem and how to fix
+// it. It returns false if the server has no admin channel or the notice couldn't
+// be sent there. A notice that wasn't sent doesn't hold back its repeats
+func notifyAdmins(s *discordgo.Session, server model.Servers, notice adminNotice) bool {
+	if server.AdminChannelID == "" {
+		return false
+	}
+
+	key := server.ID + "/" + notice.key
+	now := time.Now()
+
+	adminNotices.Lock()
+	sent, ok := adminNotices.sent[key]
+	if ok && now.Sub(sent.at) < adminNoticeInterval {
+		sent.suppressed++
+		adminNotices.Unlock()
+		log.Printf("Not repeating notice %q to admins of server %s: %s", notice.title, server.ServerName, notice.problem)
+		return true
+	}
+	suppressed := 0
+	if ok {
+		suppressed = sent.suppressed
+	}
+	adminNotices.Unlock()
+
+	embed := &discordgo.MessageEmbed{
+		Title:       notice.title,
+		Description: truncate(notice.problem, 2000),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "How to fix it", Value: truncate(notice.hint, hiscores.MaxFieldLength)},
+		},
+		Timestamp: now.Format(time.RFC3339),
+	}
+	switch {
+	case suppressed == 1:
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: "This happened 1 more time since the last notice"}
+	case suppressed > 1:
+		embed.Footer = &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("This happened %d more times since the last notice", suppressed),
+		}
+	}
+
+	_, err := s.ChannelMessageSendComplex(server.AdminChannelID, &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{embed},
+		// Notices mention players and roles to point at the problem, nobody should be pinged
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	})
+	if err != nil {
+		log.Printf("Unable to send notice to admin channel of server %s: %s", server.ServerName, err)
+		return false
+	}
+
+	adminNotices.Lock()
+	adminNotices.sent[key] = &sentNotice{at: now}
+	adminNotices.Unlock()
+
+	return true
+}
+
+// boardNoticeKey groups the notices about posting a board
+func boardNoticeKey(boardName string) string {
+	return "board/" + boardName
+}
+
+// forgetAdminNotice lets a notice be sent straight away the next
+// time its problem happens, once we know the problem was fixed
+func forgetAdminNotice(serverID string, key string) {
+	adminNotices.Lock()
+	delete(adminNotices.sent, serverID+"/"+key)
+	adminNotices.Unlock()
+}
+
+// remediationHint suggests how admins can fix an error. permissionsHint is
+// what to check when Discord says the bot isn't allowed to do something
+func remediationHint(err error, permissionsHint string) string {
+	switch {
+	case utils.IsDiscordErrorCode(err, discordgo.ErrCodeMissingPermissions),
+		utils.IsDiscordErrorCode(err, discordgo.ErrCodeMissingAccess):
+		return permissionsHint
+	case errors.Is(err, hiscores.ErrCircuitOpen):
+		return "The OSRS hiscores are down. Nothing needs to be done, the bot tries again once they're back."
+	case errors.Is(err, hiscores.ErrUnknownActivity):
+		return "Check the activity names with `/configure`. Jagex sometimes renames activities on the hiscores."
+	default:
+		return "Check `/schedule status` for details. If this keeps happening let the bot's host know."
+	}
+}
+
+// truncate shortens text to at most limit characters
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+
+	return string(runes[:limit-3]) + "..."
+}
@@ -0,0 +1,93 @@
+package discord
+
+import (
+	"testing"
+	"time"
+
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+)
+
+func TestNotifyAdminsCountsSuppressedNotices(t *testing.T) {
+	s, fake := newFakeDiscord(t)
+
+	server := model.Servers{ID: testGuildID, ServerName: "test", AdminChannelID: "500"}
+	notice := adminNotice{key: "suppressed-test", title: "Problem", problem: "Something broke.", hint: "Fix it."}
+	key := server.ID + "/" + notice.key
+	t.Cleanup(func() { forgetAdminNotice(server.ID, notice.key) })
+
+	for range 3 {
+		if !notifyAdmins(s, server, notice) {
+			t.Fatal("notice wasn't sent")
+		}
+	}
+
+	sent := fake.sentTo(server.AdminChannelID)
+	if len(sent) != 1 {
+		t.Fatalf("sent %d notices, want the repeats held back", len(sent))
+	}
+	if sent[0].Embeds[0].Footer != nil {
+		t.Errorf("first notice has footer %q", sent[0].Embeds[0].Footer.Text)
+	}
+
+	// Pretend the interval has passed
+	adminNotices.Lock()
+	adminNotices.sent[key].at = time.Now().Add(-adminNoticeInterval)
+	adminNotices.Unlock()
+
+	if !notifyAdmins(s, server, notice) {
+		t.Fatal("notice wasn't sent")
+	}
+
+	sent = fake.sentTo(server.AdminChannelID)
+	if len(sent) != 2 {
+		t.Fatalf("sent %d notices, want 2", len(sent))
+	}
+	footer := sent[1].Embeds[0].Footer
+	if footer == nil || footer.Text != "This happened 2 more times since the last notice" {
+		t.Errorf("repeated notice has footer %+v", footer)
+	}
+
+	adminNotices.Lock()
+	suppressed := adminNotices.sent[key].suppressed
+	adminNotices.Unlock()
+	if suppressed != 0 {
+		t.Errorf("suppressed count is %d after sending, want 0", suppressed)
+	}
+}
+
+func TestNotifyAdminsFailedSendIsNotHeldBack(t *testing.T) {
+	s, fake := newFakeDiscord(t)
+
+	server := model.Servers{ID: testGuildID, ServerName: "test", AdminChannelID: "501"}
+	notice := adminNotice{key: "failure-test", title: "Problem", problem: "Something broke.", hint: "Fix it."}
+	t.Cleanup(func() { forgetAdminNotice(server.ID, notice.key) })
+
+	fake.forbid(server.AdminChannelID, true)
+	if notifyAdmins(s, server, notice) {
+		t.Fatal("notice reported as sent to a channel we can't post in")
+	}
+
+	adminNotices.Lock()
+	_, recorded := adminNotices.sent[server.ID+"/"+notice.key]
+	adminNotices.Unlock()
+	if recorded {
+		t.Error("failed notice was recorded as sent")
+	}
+
+	// Once the channel works again the notice goes out straight away
+	fake.forbid(server.AdminChannelID, false)
+	if !notifyAdmins(s, server, notice) {
+		t.Fatal("notice wasn't sent")
+	}
+	if sent := fake.sentTo(server.AdminChannelID); len(sent) != 1 {
+		t.Errorf("sent %d notices, want 1", len(sent))
+	}
+}
+
+func TestNotifyAdminsWithoutAdminChannel(t *testing.T) {
+	s, _ := newFakeDiscord(t)
+
+	if notifyAdmins(s, model.Servers{ID: testGuildID}, adminNotice{key: "no-channel"}) {
+		t.Error("notice reported as sent without an admin channel")
+	}
+}
@@ -1,11 +1,13 @@
 package discord
 
 import (
+	"errors"
 	"log"
 	"os"
 	"os/signal"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/gorilla/websocket"
 	"github.com/michohl/osrs-clan-leaderboard/storage"
 	"github.com/michohl/osrs-clan-leaderboard/types"
 )
@@ -13,21 +15,32 @@ import (
 var (
 	// BotToken is the token used for creating our bot
 	BotToken = os.Getenv("DISCORD_BOT_TOKEN")
+
+	// store is where every handler reads and writes its data.
+	// It is set once when the bot starts
+	store storage.Store
 )
 
+// disallowedIntentsCloseCode is what Discord closes the gateway with when
+// we ask for a privileged intent that isn't enabled for the application
+const disallowedIntentsCloseCode = 4014
+
 func init() {
 	types.BootstrapEmojis(BotToken)
 }
 
 // StartBotListener is the function that starts the Discord
-// bot and makes it available to accept commands from users
-func StartBotListener() {
+// bot and makes it available to accept commands from users.
+// Every handler reads and writes its data through dataStore
+func StartBotListener(dataStore storage.Store) {
+	store = dataStore
+
 	discord, err := discordgo.New("Bot " + BotToken)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	allServers, err := storage.FetchAllServers()
+	allServers, err := store.FetchAllServers()
 	if err != nil {
 		panic(err)
 	}
@@ -39,15 +52,27 @@ func StartBotListener() {
 			continue
 		}
 		EnableServerMessageCronjob(server, discord)
+		EnableRankReportCronjob(server, discord)
 	}
 
+	EnableUserSyncCronjob(discord)
+
+	// Member join/leave events are a privileged intent and
+	// need to be enabled in the Discord developer portal
+	discord.Identify.Intents = discordgo.IntentsAllWithoutPrivileged | discordgo.IntentsGuildMembers
+
 	//discord.AddHandler(routeMessage)
-	discord.AddHandler(func(_ *discordgo.Session, _ *discordgo.Ready) { log.Println("Bot is up!") })
+	discord.AddHandler(ReadyHandler)
+	discord.AddHandler(GuildCreateHandler)
+	discord.AddHandler(GuildDeleteHandler)
+	discord.AddHandler(GuildMemberAddHandler)
+	discord.AddHandler(GuildMemberRemoveHandler)
+	discord.AddHandler(GuildMemberUpdateHandler)
 	discord.AddHandler(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
 
 		// The first time a user calls /configure this will
 		// not find any results so we shouldn't worry about errors here
-		server, _ := storage.FetchServer(i.GuildID)
+		server, _ := store.FetchServer(i.GuildID)
 
 		if server.ServerName != "" && !server.IsEnabled {
 			log.Printf("Server '%s' is currently disabled. Skipping requested action", server.ServerName)
@@ -65,6 +90,11 @@ func StartBotListener() {
 			if modalSubmitFunction != nil {
 				modalSubmitFunction(s, i)
 			}
+		case discordgo.InteractionMessageComponent:
+			componentFunction := GetComponentHandler(i.MessageComponentData().CustomID)
+			if componentFunction != nil {
+				componentFunction(s, i)
+			}
 		case discordgo.InteractionApplicationCommandAutocomplete:
 			autocompleteFunction := GetAutocompleteHandler(i.ApplicationCommandData().Name)
 			if autocompleteFunction != nil {
@@ -76,9 +106,26 @@ func StartBotListener() {
 	})
 
 	// open session
-	discord.Open()
+	err = discord.Open()
+	var closeErr *websocket.CloseError
+	if errors.As(err, &closeErr) && closeErr.Code == disallowedIntentsCloseCode {
+		log.Fatalf("Discord refused to connect: %s. Enable the Server Members Intent under \"Bot\" in the Discord developer portal and restart the bot", err)
+	}
+	if err != nil {
+		log.Fatalf("Unable to connect to Discord: %s", err)
+	}
 	defer discord.Close() // close session, after function termination
 
+	// Make up for anything we missed while we were offline. This can take
+	// a while so it shouldn't hold up registering our commands
+	go func() {
+		for _, server := range allServers {
+			if server.IsEnabled {
+				CatchUpMissedPosts(server, discord)
+			}
+		}
+	}()
+
 	// Register commands for auto completion
 	var GuildID string
 	_, err = discord.ApplicationCommandBulkOverwrite(
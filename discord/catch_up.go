@@ -0,0 +1,75 @@
+package discord
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+	"github.com/michohl/osrs-clan-leaderboard/schedule"
+	"github.com/michohl/osrs-clan-leaderboard/storage"
+	"github.com/michohl/osrs-clan-leaderboard/types"
+)
+
+// CatchUpMissedPosts posts every board of a server whose scheduled post was
+// missed while the bot was offline. However many posts were missed each board
+// is only posted once and only if the missed post isn't too late
+func CatchUpMissedPosts(server model.Servers, s *discordgo.Session) {
+	if !server.CatchUpEnabled {
+		return
+	}
+
+	boards, err := store.FetchBoards(server.ID)
+	if err != nil {
+		log.Printf("Unable to fetch boards for server %s because %s\n", server.ServerName, err)
+		return
+	}
+
+	now := time.Now()
+
+	for _, board := range boards {
+		missedAt, tooLate, err := missedScheduledPost(server, board, now)
+		if err != nil {
+			log.Printf("Unable to check for missed posts of board %s in server %s because %s\n", board.BoardName, server.ServerName, err)
+			continue
+		}
+
+		if missedAt.IsZero() {
+			continue
+		}
+
+		if tooLate {
+			log.Printf("Not catching up board %s in server %s. The post missed at %s is too late\n", board.BoardName, server.ServerName, missedAt)
+			continue
+		}
+
+		log.Printf("Catching up board %s in server %s. It missed its post at %s\n", board.BoardName, server.ServerName, missedAt)
+		err = postBoard(server.ID, board.BoardName, types.RunTriggerCatchUp, s)
+		if err != nil {
+			log.Printf("Unable to catch up board %s for server %s because %s\n", board.BoardName, server.ServerName, err)
+		}
+	}
+}
+
+// missedScheduledPost returns the most recent time a board should have been
+// posted since it was last posted successfully and whether that was too long
+// ago to catch up on. It returns the zero time if nothing was missed
+func missedScheduledPost(server model.Servers, board model.Boards, now time.Time) (time.Time, bool, error) {
+	lastRun, err := store.FetchLastSuccessfulScheduleRun(board.ServerID, board.BoardName)
+	if errors.Is(err, storage.ErrNotFound) {
+		// A board that has never been posted has nothing to catch up on
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	missedAt, err := schedule.LastRunBetween(board.Schedule, server.Timezone, lastRun.StartedAt, now)
+	if err != nil || missedAt.IsZero() {
+		return missedAt, false, err
+	}
+
+	maxLateness := time.Duration(server.CatchUpMaxLatenessHours) * time.Hour
+	return missedAt, now.Sub(missedAt) > maxLateness, nil
+}
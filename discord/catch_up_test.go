@@ -0,0 +1,129 @@
+package discord
+
+import (
+	"testing"
+	"time"
+
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+	"github.com/michohl/osrs-clan-leaderboard/types"
+)
+
+func TestMissedScheduledPost(t *testing.T) {
+	at := func(value string) time.Time {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		name     string
+		schedule string
+		timezone string
+		// lastRun is when the board was last posted successfully, if ever
+		lastRun     string
+		failedRun   string
+		now         string
+		wantMissed  string
+		wantTooLate bool
+	}{
+		{
+			name:     "never posted",
+			schedule: "0 19 * * SUN",
+			timezone: "UTC",
+			now:      "2026-01-12T12:00:00Z",
+		},
+		{
+			name:     "nothing missed",
+			schedule: "0 19 * * SUN",
+			timezone: "UTC",
+			lastRun:  "2026-01-11T19:00:00Z",
+			now:      "2026-01-12T12:00:00Z",
+		},
+		{
+			name:       "missed inside the lateness window",
+			schedule:   "0 19 * * SUN",
+			timezone:   "UTC",
+			lastRun:    "2026-01-04T19:00:00Z",
+			now:        "2026-01-12T12:00:00Z",
+			wantMissed: "2026-01-11T19:00:00Z",
+		},
+		{
+			name:        "missed outside the lateness window",
+			schedule:    "0 19 * * SUN",
+			timezone:    "UTC",
+			lastRun:     "2026-01-04T19:00:00Z",
+			now:         "2026-01-13T12:00:00Z",
+			wantMissed:  "2026-01-11T19:00:00Z",
+			wantTooLate: true,
+		},
+		{
+			name:       "only the latest of several missed posts",
+			schedule:   "0 19 * * *",
+			timezone:   "UTC",
+			lastRun:    "2026-01-04T19:00:00Z",
+			now:        "2026-01-08T12:00:00Z",
+			wantMissed: "2026-01-07T19:00:00Z",
+		},
+		{
+			name:       "failed runs don't count as posted",
+			schedule:   "0 19 * * SUN",
+			timezone:   "UTC",
+			lastRun:    "2026-01-04T19:00:00Z",
+			failedRun:  "2026-01-11T19:00:00Z",
+			now:        "2026-01-12T12:00:00Z",
+			wantMissed: "2026-01-11T19:00:00Z",
+		},
+		{
+			name:       "missed in the server timezone across the clocks going forward",
+			schedule:   "0 19 * * SUN",
+			timezone:   "Europe/London",
+			lastRun:    "2026-03-22T19:00:00Z",
+			now:        "2026-03-30T12:00:00Z",
+			wantMissed: "2026-03-29T18:00:00Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newTestStore(t)
+
+			server := model.Servers{ID: testGuildID, Timezone: tt.timezone, CatchUpMaxLatenessHours: 24}
+			board := model.Boards{ServerID: testGuildID, BoardName: "default", Schedule: tt.schedule}
+
+			runs := map[string]string{tt.lastRun: types.RunOutcomeSuccess, tt.failedRun: types.RunOutcomeFailure}
+			for startedAt, outcome := range runs {
+				if startedAt == "" {
+					continue
+				}
+				err := store.RecordScheduleRun(model.ScheduleRuns{
+					ServerID:    board.ServerID,
+					BoardName:   board.BoardName,
+					StartedAt:   at(startedAt),
+					TriggeredBy: types.RunTriggerSchedule,
+					Outcome:     outcome,
+				}, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			missedAt, tooLate, err := missedScheduledPost(server, board, at(tt.now))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.wantMissed == "" {
+				if !missedAt.IsZero() {
+					t.Errorf("missed a post at %s, want nothing missed", missedAt.UTC())
+				}
+			} else if !missedAt.Equal(at(tt.wantMissed)) {
+				t.Errorf("missed a post at %s, want %s", missedAt.UTC(), tt.wantMissed)
+			}
+			if tooLate != tt.wantTooLate {
+				t.Errorf("too late = %t, want %t", tooLate, tt.wantTooLate)
+			}
+		})
+	}
+}
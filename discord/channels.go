@@ -0,0 +1,162 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+	"github.com/michohl/osrs-clan-leaderboard/schedule"
+	"github.com/michohl/osrs-clan-leaderboard/utils"
+)
+
+// channelWarnings remembers which boards we've already warned the admins
+// about so a scheduled post doesn't send the same warning every time it runs
+var channelWarnings = struct {
+	sync.Mutex
+	sent map[string]bool
+}{sent: map[string]bool{}}
+
+// boardChannel returns the channel a board posts to. If the channel is
+// gone the server's admins are told so they can pick a new one
+func boardChannel(s *discordgo.Session, board model.Boards) (*discordgo.Channel, error) {
+	var channel *discordgo.Channel
+	var err error
+
+	// Boards configured before we stored channel IDs only know the name
+	if board.ChannelID == "" {
+		var channels []*discordgo.Channel
+		channels, err = s.GuildChannels(board.ServerID)
+		if err != nil {
+			return nil, err
+		}
+		channel, err = resolveBoardChannel(board, channels)
+	} else {
+		channel, err = utils.GetChannel(s, board.ServerID, board.ChannelID)
+	}
+
+	if err != nil {
+		if errors.Is(err, utils.ErrChannelNotFound) || errors.Is(err, utils.ErrChannelAmbiguous) {
+			warnBoardChannel(s, board, err)
+		}
+		return nil, err
+	}
+
+	// Keep the name we show to admins in line with what the channel is called now
+	if channel.Name != board.ChannelName {
+		err = store.SetBoardChannel(board.ServerID, board.BoardName, channel.ID, channel.Name)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+
+	channelWarnings.Lock()
+	delete(channelWarnings.sent, schedule.BoardJobKey(board.ServerID, board.BoardName))
+	channelWarnings.Unlock()
+
+	return channel, nil
+}
+
+// resolveBoardChannelIDs looks up the channel ID of every board in a guild
+// that was configured before we stored channel IDs
+func resolveBoardChannelIDs(guild *discordgo.Guild) {
+	boards, err := store.FetchBoards(guild.ID)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	for _, board := range boards {
+		if board.ChannelID != "" || board.ChannelName == "" {
+			continue
+		}
+
+		_, err = resolveBoardChannel(board, guild.Channels)
+		if err != nil {
+			// We'll warn the admins if it still can't be resolved when we next post
+			log.Printf("Unable to resolve channel of board %s in server %s: %s", board.BoardName, guild.Name, err)
+		}
+	}
+}
+
+// resolveBoardChannel finds a board's channel by name and stores its ID
+func resolveBoardChannel(board model.Boards, channels []*discordgo.Channel) (*discordgo.Channel, error) {
+	channel, err := utils.FindChannelByName(channels, board.ChannelName)
+	if err != nil {
+		return nil, err
+	}
+
+	err = store.SetBoardChannel(board.ServerID, board.BoardName, channel.ID, channel.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return channel, nil
+}
+
+// warnBoardChannel tells the server's admin channel (or the server owner if there
+// isn't one) that a board can't be posted because of a problem with its channel
+func warnBoardChannel(s *discordgo.Session, board model.Boards, problem error) {
+	key := schedule.BoardJobKey(board.ServerID, board.BoardName)
+
+	channelWarnings.Lock()
+	alreadySent := channelWarnings.sent[key]
+	channelWarnings.sent[key] = true
+	channelWarnings.Unlock()
+
+	if alreadySent {
+		return
+	}
+
+	server, err := store.FetchServer(board.ServerID)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	notified := notifyAdmins(s, server, adminNotice{
+		key:     boardNoticeKey(board.BoardName),
+		title:   fmt.Sprintf("Unable to post the %s leaderboard", board.BoardName),
+		problem: fmt.Sprintf("The leaderboard can't be posted to #%s (%s).", board.ChannelName, problem),
+		hint:    fmt.Sprintf("Use `%s` to choose a new channel.", boardEditCommand(board)),
+	})
+	if notified {
+		return
+	}
+
+	guild, err := s.Guild(board.ServerID)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	dm, err := s.UserChannelCreate(guild.OwnerID)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	_, err = s.ChannelMessageSend(dm.ID, fmt.Sprintf(
+		"The **%s** leaderboard in **%s** can't be posted to #%s (%s). Use `%s` to choose a new channel.",
+		board.BoardName,
+		guild.Name,
+		board.ChannelName,
+		problem,
+		boardEditCommand(board),
+	))
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+// channelMention links to a board's channel so the link keeps
+// working when the channel is renamed
+func channelMention(board model.Boards) string {
+	if board.ChannelID == "" {
+		return "#" + board.ChannelName
+	}
+
+	return "<#" + board.ChannelID + ">"
+}
@@ -0,0 +1,103 @@
+package discord
+
+import (
+	"log"
+	"slices"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+	"github.com/michohl/osrs-clan-leaderboard/types"
+	"github.com/michohl/osrs-clan-leaderboard/utils"
+)
+
+// archivedMessageNotice is added to leaderboard messages that are left
+// in place when the server archives messages instead of deleting them
+const archivedMessageNotice = "-# This leaderboard is no longer updated."
+
+// cleanUpBoardMessages deals with the messages a board stopped updating when
+// its configuration changed. previous and previousMessages are the board and
+// its message rows from before the change was saved.
+//
+// Messages that only held removed activities are no longer tracked so they'd
+// stay in the channel forever, and if the board moved to another channel none
+// of its old messages can be edited any more
+func cleanUpBoardMessages(s *discordgo.Session, server model.Servers, previous model.Boards, previousMessages []model.Messages, board model.Boards) {
+	channelChanged := previous.ChannelID != board.ChannelID &&
+		(previous.ChannelID != "" || previous.ChannelName != board.ChannelName)
+
+	messages, err := store.FetchAllMessages(board.ServerID, board.BoardName)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	tracked := map[string]bool{}
+	for _, m := range messages {
+		if m.MessageID == "" {
+			continue
+		}
+		tracked[m.MessageID] = true
+
+		// The board will post new messages in its new channel
+		if channelChanged {
+			err = store.RemoveMessage(m)
+			if err != nil {
+				log.Printf("Error removing message of old channel from db: %s", err)
+			}
+		}
+	}
+
+	orphaned := []string{}
+	for _, m := range previousMessages {
+		if m.MessageID == "" || slices.Contains(orphaned, m.MessageID) {
+			continue
+		}
+		if channelChanged || !tracked[m.MessageID] {
+			orphaned = append(orphaned, m.MessageID)
+		}
+	}
+
+	if len(orphaned) == 0 {
+		return
+	}
+
+	// Boards configured before we stored channel IDs don't know where their messages are
+	if previous.ChannelID == "" {
+		log.Printf("Leaving %d orphaned messages of board %s in place since its old channel is unknown\n", len(orphaned), board.BoardName)
+		return
+	}
+
+	for _, messageID := range orphaned {
+		err = retireMessage(s, server, previous.ChannelID, messageID)
+		if err != nil {
+			log.Printf("Unable to clean up message %s of board %s: %s", messageID, board.BoardName, err)
+		}
+	}
+}
+
+// retireMessage deletes or archives a leaderboard message the bot no
+// longer updates depending on what the server asked for in /settings
+func retireMessage(s *discordgo.Session, server model.Servers, channelID string, messageID string) error {
+	var err error
+
+	switch server.OrphanedMessageAction {
+	case types.OrphanedMessageActionArchive:
+		log.Printf("Archiving orphaned hiscores message %s\n", messageID)
+		content := archivedMessageNotice
+		_, err = s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+			ID:      messageID,
+			Channel: channelID,
+			Content: &content,
+		})
+	default:
+		log.Printf("Removing orphaned hiscores message %s\n", messageID)
+		err = s.ChannelMessageDelete(channelID, messageID)
+	}
+
+	// Someone already deleted it for us
+	if utils.IsDiscordErrorCode(err, discordgo.ErrCodeUnknownMessage) {
+		return nil
+	}
+
+	return err
+}
@@ -6,7 +6,6 @@ import (
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/michohl/osrs-clan-leaderboard/hiscores"
-	"github.com/michohl/osrs-clan-leaderboard/storage"
 
 	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
 )
@@ -104,23 +103,42 @@ func assignCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		return
 	}
 
-	err = storage.EnrollUser(model.Users{
+	user := model.Users{
 		OsrsUsernameKey: hiscores.EncodeRSN(osrsUsername),
 		OsrsUsername:    osrsUsername,
 		OsrsAccountType: osrsAccountType,
 		ServerID:        i.GuildID,
 		DiscordUsername: discordUser.Username,
 		DiscordUserID:   discordUser.ID,
-	})
+	}
+
+	err = store.EnrollUser(user)
 	if err != nil {
 		log.Println(err)
 		return
 	}
 
+	// The most recently assigned account is the one we use for the member's nickname
+	err = store.SetPrimaryUser(user)
+	if err != nil {
+		log.Println(err)
+	}
+
+	content := fmt.Sprintf("OSRS User %s assigned to <@%s>", osrsUsername, discordUser.ID)
+
+	server, err := store.FetchServer(i.GuildID)
+	if err == nil {
+		err = ApplyNickname(s, server, discordUser.ID)
+		if err != nil {
+			log.Println(err)
+			content += fmt.Sprintf("\nUnable to update their nickname because %s", describeNicknameError(err))
+		}
+	}
+
 	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
-			Content: fmt.Sprintf("OSRS User %s assigned to <@%s>", osrsUsername, discordUser.ID),
+			Content: content,
 			Flags:   discordgo.MessageFlagsEphemeral,
 		},
 	})
@@ -0,0 +1,288 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+	"github.com/michohl/osrs-clan-leaderboard/types"
+)
+
+// boardNamePattern keeps board names short enough to fit in
+// modal titles and custom IDs and easy to type in commands
+var boardNamePattern = regexp.MustCompile(`^[a-z0-9-]{1,24}$`)
+
+// boardDisplayOptions are the options shared by /board create and /board edit
+var boardDisplayOptions = []*discordgo.ApplicationCommandOption{
+	{
+		Name:        "hide_unranked",
+		Description: "Leave out players with no score or level 1. Defaults to True",
+		Type:        discordgo.ApplicationCommandOptionBoolean,
+		Required:    false,
+	},
+	{
+		Name:        "show_rank",
+		Description: "Show each player's official hiscores rank. Defaults to False",
+		Type:        discordgo.ApplicationCommandOptionBoolean,
+		Required:    false,
+	},
+}
+
+// BoardCommandInfo is the information we'll use to
+// "register" this command with Discord so it appears
+// as an option to end users
+var BoardCommandInfo = discordgo.ApplicationCommand{
+	Name:                     "board",
+	Description:              "Manage the leaderboards posted in this server",
+	Type:                     discordgo.ChatApplicationCommand,
+	DefaultMemberPermissions: &manageServerPermission,
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Name:        "create",
+			Description: "Create a new leaderboard with its own channel, schedule and activities",
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Options: append([]*discordgo.ApplicationCommandOption{
+				{
+					Name:        "name",
+					Description: "A short name for the board e.g. pvm or skilling",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    true,
+				},
+			}, boardDisplayOptions...),
+		},
+		{
+			Name:        "edit",
+			Description: "Change an existing leaderboard",
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Options: append([]*discordgo.ApplicationCommandOption{
+				{
+					Name:         "name",
+					Description:  "The board to edit",
+					Type:         discordgo.ApplicationCommandOptionString,
+					Required:     true,
+					Autocomplete: true,
+				},
+			}, boardDisplayOptions...),
+		},
+		{
+			Name:        "delete",
+			Description: "Stop posting a leaderboard and forget its configuration",
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Name:         "name",
+					Description:  "The board to delete",
+					Type:         discordgo.ApplicationCommandOptionString,
+					Required:     true,
+					Autocomplete: true,
+				},
+			},
+		},
+		{
+			Name:        "list",
+			Description: "Show every leaderboard configured in this server",
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+		},
+	},
+}
+
+// BoardAutocompleteHandler suggests the names of the boards configured
+// in the server. It's shared by every command with a board option
+func BoardAutocompleteHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	choices := []*discordgo.ApplicationCommandOptionChoice{}
+
+	boards, err := store.FetchBoards(i.GuildID)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	for _, b := range boards {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  b.BoardName,
+			Value: b.BoardName,
+		})
+	}
+
+	// Discord will reject the response if we send more than 25 choices
+	if len(choices) > 25 {
+		choices = choices[:25]
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{
+			Choices: choices,
+		},
+	})
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+}
+
+// BoardHandler will take a command request from Discord and translate
+// that into an action. This is where we decide if we're taking action
+// or if Discord is just asking what autocomplete options are available
+func BoardHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+		boardCommand(s, i)
+	}
+}
+
+// Actually do the command the user is requesting
+func boardCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	subcommand := i.ApplicationCommandData().Options[0]
+
+	var content string
+	var err error
+
+	switch subcommand.Name {
+	case "create", "edit":
+		// Both of these respond with the configure modal when successful
+		content, err = openBoardEditor(s, i, subcommand.Name == "create", subcommand.Options)
+		if content == "" {
+			if err != nil {
+				log.Println(err)
+			}
+			return
+		}
+	case "delete":
+		content, err = deleteBoard(i, subcommand.Options)
+	case "list":
+		content, err = listBoards(i)
+	}
+
+	if err != nil {
+		log.Println(err)
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Println(err)
+		return
+	}
+}
+
+// openBoardEditor shows the configure modal for a new or existing board.
+// If the board can't be edited it returns a message explaining why instead
+func openBoardEditor(s *discordgo.Session, i *discordgo.InteractionCreate, create bool, options []*discordgo.ApplicationCommandInteractionDataOption) (string, error) {
+	boardName := strings.ToLower(strings.Trim(options[0].StringValue(), " "))
+
+	if !boardNamePattern.MatchString(boardName) {
+		return "Board names can only contain lowercase letters, numbers and dashes and must be at most 24 characters long", nil
+	}
+
+	board, err := store.FetchBoard(i.GuildID, boardName)
+	exists := err == nil
+
+	switch {
+	case create && exists:
+		return fmt.Sprintf("Board %s already exists. Use `/board edit` to change it.", boardName), nil
+	case !create && !exists:
+		return fmt.Sprintf("Board %s doesn't exist. Use `/board create` to create it.", boardName), nil
+	case create:
+		board = newBoard(i.GuildID, boardName)
+	}
+
+	for _, option := range options[1:] {
+		switch option.Name {
+		case "hide_unranked":
+			board.HideUnranked = option.BoolValue()
+		case "show_rank":
+			board.ShowRank = option.BoolValue()
+		}
+	}
+
+	return "", openBoardModal(s, i, boardModalCustomID(board), board)
+}
+
+func deleteBoard(i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) (string, error) {
+	boardName := options[0].StringValue()
+
+	_, err := store.FetchBoard(i.GuildID, boardName)
+	if err != nil {
+		return fmt.Sprintf("Board %s doesn't exist", boardName), err
+	}
+
+	err = store.RemoveBoard(i.GuildID, boardName)
+	if err != nil {
+		return "Failed to delete board...", err
+	}
+
+	DisableBoardMessageCronjob(i.GuildID, boardName)
+	forgetPostponedBoard(i.GuildID, boardName)
+
+	return fmt.Sprintf("Board %s deleted. Messages it already posted have been left in place.", boardName), nil
+}
+
+func listBoards(i *discordgo.InteractionCreate) (string, error) {
+	boards, err := store.FetchBoards(i.GuildID)
+	if err != nil {
+		return "Failed to fetch boards...", err
+	}
+
+	if len(boards) == 0 {
+		return "No boards are configured. Use `/configure` or `/board create` to create one.", nil
+	}
+
+	content := "Leaderboards in this server:"
+	for _, board := range boards {
+		line, err := formatBoard(board)
+		if err != nil {
+			return "Failed to fetch boards...", err
+		}
+
+		// Discord messages are limited to 2000 characters
+		if len(content)+len(line) > 1900 {
+			content += "\n..."
+			break
+		}
+		content += line
+	}
+
+	return content, nil
+}
+
+// boardEditCommand is the command admins use to change a board
+func boardEditCommand(board model.Boards) string {
+	if board.BoardName == types.DefaultBoardName {
+		return "/configure"
+	}
+
+	return "/board edit name:" + board.BoardName
+}
+
+// formatBoard describes a board on a single line for /board list
+func formatBoard(board model.Boards) (string, error) {
+	activities, err := store.FetchAllActivitiesAndSkills(board.ServerID, board.BoardName)
+	if err != nil {
+		return "", err
+	}
+
+	name := board.BoardName
+	if name == types.DefaultBoardName {
+		name += " (managed by /configure)"
+	}
+
+	return fmt.Sprintf(
+		"\n* **%s**: %s on schedule `%s` with %d activities (hide_unranked: %t, show_rank: %t)",
+		name,
+		channelMention(board),
+		board.Schedule,
+		len(activities),
+		board.HideUnranked,
+		board.ShowRank,
+	), nil
+}
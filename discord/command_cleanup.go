@@ -0,0 +1,227 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/michohl/osrs-clan-leaderboard/hiscores"
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+	"github.com/michohl/osrs-clan-leaderboard/types"
+)
+
+// maxCleanupScan is how many of the most recent messages
+// in a channel /cleanup looks through
+const maxCleanupScan = 1000
+
+// CleanupCommandInfo is the information we'll use to
+// "register" this command with Discord so it appears
+// as an option to end users
+var CleanupCommandInfo = discordgo.ApplicationCommand{
+	Name:                     "cleanup",
+	Description:              "Remove leaderboard messages the bot posted but no longer updates",
+	Type:                     discordgo.ChatApplicationCommand,
+	DefaultMemberPermissions: &manageServerPermission,
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Name:         "board",
+			Description:  "Only clean up the channel of this board",
+			Type:         discordgo.ApplicationCommandOptionString,
+			Required:     false,
+			Autocomplete: true,
+		},
+		{
+			Name:        "dry_run",
+			Description: "Only count the stray messages without removing them. Defaults to False",
+			Type:        discordgo.ApplicationCommandOptionBoolean,
+			Required:    false,
+		},
+	},
+}
+
+// CleanupHandler will take a command request from Discord and translate
+// that into an action. This is where we decide if we're taking action
+// or if Discord is just asking what autocomplete options are available
+func CleanupHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+		cleanupCommand(s, i)
+	}
+}
+
+// Actually do the command the user is requesting
+func cleanupCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	// Scanning a busy channel can take a while
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	content, err := cleanupChannels(s, i)
+	if err != nil {
+		log.Println(err)
+	}
+
+	_, err = s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+		Flags:   discordgo.MessageFlagsEphemeral,
+		Content: content,
+	})
+	if err != nil {
+		log.Println(err)
+		return
+	}
+}
+
+func cleanupChannels(s *discordgo.Session, i *discordgo.InteractionCreate) (string, error) {
+	server, err := store.FetchServer(i.GuildID)
+	if err != nil {
+		return "This server hasn't been configured yet. Please run `/configure` first.", err
+	}
+
+	boards, err := store.FetchBoards(i.GuildID)
+	if err != nil {
+		return "Failed to fetch boards...", err
+	}
+
+	selected := boards
+	dryRun := false
+	for _, option := range i.ApplicationCommandData().Options {
+		switch option.Name {
+		case "board":
+			boardName := option.StringValue()
+			board, err := store.FetchBoard(i.GuildID, boardName)
+			if err != nil {
+				return fmt.Sprintf("Board %s doesn't exist", boardName), err
+			}
+			selected = []model.Boards{board}
+		case "dry_run":
+			dryRun = option.BoolValue()
+		}
+	}
+
+	if len(selected) == 0 {
+		return "No boards are configured. Use `/configure` or `/board create` to create one.", nil
+	}
+
+	// A board being posted right now may have posted a message it hasn't
+	// recorded yet so we hold every board still while we look
+	for _, board := range boards {
+		release, err := lockBoardRun(board.ServerID, board.BoardName)
+		if errors.Is(err, ErrRunInProgress) {
+			return fmt.Sprintf("Board %s is being posted right now. Try again once it finishes.", board.BoardName), nil
+		}
+		if err != nil {
+			return "Failed to clean up messages...", err
+		}
+		defer release()
+	}
+
+	// Several boards can share a channel
+	tracked := map[string]bool{}
+	for _, board := range boards {
+		messages, err := store.FetchAllMessages(board.ServerID, board.BoardName)
+		if err != nil {
+			return "Failed to clean up messages...", err
+		}
+		for _, m := range messages {
+			tracked[m.MessageID] = true
+		}
+	}
+
+	action := "Removed"
+	switch {
+	case dryRun:
+		action = "Found"
+	case server.OrphanedMessageAction == types.OrphanedMessageActionArchive:
+		action = "Archived"
+	}
+
+	scanned := []string{}
+	content := ""
+	for _, board := range selected {
+		channel, err := boardChannel(s, board)
+		if err != nil {
+			return fmt.Sprintf("Unable to find the channel of board %s...", board.BoardName), err
+		}
+		if slices.Contains(scanned, channel.ID) {
+			continue
+		}
+		scanned = append(scanned, channel.ID)
+
+		stray, err := findStrayMessages(s, channel.ID, tracked)
+		if err != nil {
+			return "Failed to read the channel's messages...", err
+		}
+
+		if !dryRun {
+			for _, messageID := range stray {
+				err = retireMessage(s, server, channel.ID, messageID)
+				if err != nil {
+					return "Failed to clean up messages...", err
+				}
+			}
+		}
+
+		content += fmt.Sprintf("\n%s %d stray leaderboard messages in <#%s>", action, len(stray), channel.ID)
+	}
+
+	return strings.TrimPrefix(content, "\n"), nil
+}
+
+// findStrayMessages looks through the most recent messages of a channel for
+// leaderboard messages we posted but don't keep track of any more
+func findStrayMessages(s *discordgo.Session, channelID string, tracked map[string]bool) ([]string, error) {
+	stray := []string{}
+	beforeID := ""
+
+	for scanned := 0; scanned < maxCleanupScan; {
+		messages, err := s.ChannelMessages(channelID, 100, beforeID, "", "")
+		if err != nil {
+			return nil, err
+		}
+		if len(messages) == 0 {
+			break
+		}
+
+		for _, m := range messages {
+			if isStrayLeaderboardMessage(s, m, tracked) {
+				stray = append(stray, m.ID)
+			}
+		}
+
+		scanned += len(messages)
+		beforeID = messages[len(messages)-1].ID
+	}
+
+	return stray, nil
+}
+
+// isStrayLeaderboardMessage checks if a message is a leaderboard we posted but
+// no longer update. Only messages made up of leaderboard embeds count so rank
+// reports, admin notices and archived leaderboards are left alone
+func isStrayLeaderboardMessage(s *discordgo.Session, m *discordgo.Message, tracked map[string]bool) bool {
+	if m.Author == nil || m.Author.ID != s.State.User.ID || len(m.Embeds) == 0 {
+		return false
+	}
+
+	if tracked[m.ID] || m.Content == archivedMessageNotice {
+		return false
+	}
+
+	for _, embed := range m.Embeds {
+		if !hiscores.IsLeaderboardEmbed(embed) {
+			return false
+		}
+	}
+
+	return true
+}
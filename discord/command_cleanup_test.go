@@ -0,0 +1,46 @@
+package discord
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestFindStrayMessages(t *testing.T) {
+	s, fake := newFakeDiscord(t)
+	bot := &discordgo.User{ID: "bot"}
+
+	leaderboard := func() []*discordgo.MessageEmbed {
+		return []*discordgo.MessageEmbed{{
+			Title: "Zulrah",
+			Fields: []*discordgo.MessageEmbedField{
+				{Name: "Username", Value: "player0", Inline: true},
+				{Name: "Score", Value: "10", Inline: true},
+			},
+		}}
+	}
+
+	fake.history["11"] = []*discordgo.Message{
+		{ID: "1", Author: bot, Embeds: leaderboard()},
+		{ID: "2", Author: bot, Embeds: leaderboard()},
+		{ID: "3", Author: bot, Embeds: leaderboard(), Content: archivedMessageNotice},
+		{ID: "4", Author: &discordgo.User{ID: "someone"}, Embeds: leaderboard()},
+		{ID: "5", Author: bot, Embeds: []*discordgo.MessageEmbed{{Title: rankReportTitle, Description: "Nobody is due a promotion"}}},
+		{ID: "6", Author: bot, Embeds: []*discordgo.MessageEmbed{{
+			Title:       "Board default couldn't be posted",
+			Description: "Missing Permissions",
+			Fields:      []*discordgo.MessageEmbedField{{Name: "How to fix it", Value: "Give the bot its permissions back"}},
+		}}},
+		{ID: "7", Author: bot, Content: "Hello"},
+	}
+
+	stray, err := findStrayMessages(s, "11", map[string]bool{"1": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(stray, []string{"2"}) {
+		t.Errorf("expected only the untracked leaderboard to be stray, got %v", stray)
+	}
+}
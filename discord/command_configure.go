@@ -10,7 +10,6 @@ import (
 	"github.com/michohl/osrs-clan-leaderboard/hiscores"
 	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
 	"github.com/michohl/osrs-clan-leaderboard/schedule"
-	"github.com/michohl/osrs-clan-leaderboard/storage"
 	"github.com/michohl/osrs-clan-leaderboard/types"
 	"github.com/michohl/osrs-clan-leaderboard/utils"
 )
@@ -38,50 +37,82 @@ func ConfigureHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	}
 }
 
-// Actually do the command the user is requesting
+// Actually do the command the user is requesting. /configure
+// always edits the server's default board
 func configureCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
 
-	allSkills, err := hiscores.GetAllSkills()
+	board, err := store.FetchBoard(i.GuildID, types.DefaultBoardName)
+	if err != nil {
+		log.Printf("Unable to fetch an existing config for guild %s. Error: %s", i.GuildID, err)
+		board = newBoard(i.GuildID, types.DefaultBoardName)
+	}
+
+	err = openBoardModal(s, i, "modals_survey_configure_"+i.Interaction.Member.User.ID, board)
 	if err != nil {
 		log.Println(err)
 		return
 	}
+}
+
+// newBoard returns a board that hasn't been configured yet
+// with the same display options as the boards table defaults
+func newBoard(serverID string, boardName string) model.Boards {
+	return model.Boards{
+		ServerID:     serverID,
+		BoardName:    boardName,
+		HideUnranked: true,
+	}
+}
+
+// openBoardModal shows the modal survey used to configure a board
+// prefilled with the board's existing settings (if there are any)
+func openBoardModal(s *discordgo.Session, i *discordgo.InteractionCreate, customID string, board model.Boards) error {
 
-	var existingConfig model.Servers
-	existingConfig, err = storage.FetchServer(i.GuildID)
+	allSkills, err := hiscores.GetAllSkills()
 	if err != nil {
-		log.Printf("Unable to fetch an existing config for guild %s. Error: %s", i.GuildID, err)
+		return err
 	}
 
-	existingActivities, err := storage.FetchAllActivitiesAndSkills(i.GuildID)
+	existingActivities, err := store.FetchAllActivitiesAndSkills(board.ServerID, board.BoardName)
 	if err != nil {
-		log.Printf("Unable to fetch existing tracked activities for guild %s. Error: %s", i.GuildID, err)
+		log.Printf("Unable to fetch existing tracked activities for board %s in guild %s. Error: %s", board.BoardName, board.ServerID, err)
+	}
+
+	// The timezone belongs to the server so every board shares it
+	timezone := types.DefaultTimezone
+	server, err := store.FetchServer(board.ServerID)
+	if err == nil {
+		timezone = server.Timezone
 	}
 
 	defaultChannel := []discordgo.SelectMenuDefaultValue{}
-	channel, err := utils.GetChannel(s, i.GuildID, existingConfig.ChannelName)
-	if err != nil {
-		log.Printf(
-			"Unable to get channel details for %s in guild %s. Error: %s",
-			existingConfig.ChannelName,
-			i.GuildID,
-			err,
-		)
-	} else {
-		defaultChannel = append(
-			defaultChannel,
-			discordgo.SelectMenuDefaultValue{
-				ID:   channel.ID,
-				Type: discordgo.SelectMenuDefaultValueChannel,
-			},
-		)
+	// New boards don't have a channel to preselect yet
+	if board.ChannelID != "" {
+		channel, err := utils.GetChannel(s, board.ServerID, board.ChannelID)
+		if err != nil {
+			log.Printf(
+				"Unable to get channel details for %s (ID: %s) in guild %s. Error: %s",
+				board.ChannelName,
+				board.ChannelID,
+				board.ServerID,
+				err,
+			)
+		} else {
+			defaultChannel = append(
+				defaultChannel,
+				discordgo.SelectMenuDefaultValue{
+					ID:   channel.ID,
+					Type: discordgo.SelectMenuDefaultValueChannel,
+				},
+			)
+		}
 	}
 
 	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseModal,
 		Data: &discordgo.InteractionResponseData{
-			CustomID: "modals_survey_configure_" + i.Interaction.Member.User.ID,
-			Title:    "Modals survey",
+			CustomID: customID,
+			Title:    fmt.Sprintf("Configure the %s board", board.BoardName),
 			Components: []discordgo.MessageComponent{
 				discordgo.Label{
 					Label: "Which channel do you want hiscores posted to?",
@@ -97,11 +128,11 @@ func configureCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
 					Components: []discordgo.MessageComponent{
 						discordgo.TextInput{
 							CustomID:    "schedule",
-							Label:       "Cron Schedule to Update Hiscores (CST)",
+							Label:       "Cron Schedule to Update Hiscores",
 							Placeholder: "0 19 * * SUN",
 							Style:       discordgo.TextInputShort,
 							Required:    true,
-							Value:       existingConfig.Schedule,
+							Value:       board.Schedule,
 						},
 					},
 				},
@@ -125,18 +156,28 @@ func configureCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
 						CustomID:    "edit",
 						Placeholder: "Edit message instead of posting new?",
 						Options: []discordgo.SelectMenuOption{
-							{Label: "Yes", Value: "true", Default: existingConfig.ShouldEditMessage == true},
-							{Label: "No", Value: "false", Default: existingConfig.ShouldEditMessage == false},
+							{Label: "Yes", Value: "true", Default: board.ShouldEditMessage == true},
+							{Label: "No", Value: "false", Default: board.ShouldEditMessage == false},
+						},
+					},
+				},
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:    "timezone",
+							Label:       "Timezone the schedule runs in",
+							Placeholder: "America/Chicago",
+							Style:       discordgo.TextInputShort,
+							Required:    true,
+							Value:       timezone,
 						},
 					},
 				},
 			},
 		},
 	})
-	if err != nil {
-		log.Println(err)
-		return
-	}
+
+	return err
 }
 
 // ConfigureModalSubmit takes action when the users presses submit on the modal survey
@@ -159,6 +200,12 @@ func ConfigureModalSubmit(s *discordgo.Session, i *discordgo.InteractionCreate)
 
 	data := i.ModalSubmitData()
 
+	board, err := boardFromModalCustomID(i.GuildID, data.CustomID)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
 	channelID := data.Components[0].(*discordgo.Label).Component.(*discordgo.SelectMenu).Values[0]
 	cronSchedule := data.Components[1].(*discordgo.ActionsRow).Components[0].(*discordgo.TextInput).Value
 	activities := data.Components[2].(*discordgo.ActionsRow).Components[0].(*discordgo.TextInput).Value
@@ -169,6 +216,7 @@ func ConfigureModalSubmit(s *discordgo.Session, i *discordgo.InteractionCreate)
 		// anyways...
 		shouldEditMessage = false
 	}
+	timezone := strings.Trim(data.Components[4].(*discordgo.ActionsRow).Components[0].(*discordgo.TextInput).Value, " ")
 
 	channel, err := s.State.Channel(channelID)
 
@@ -196,21 +244,24 @@ func ConfigureModalSubmit(s *discordgo.Session, i *discordgo.InteractionCreate)
 	}
 
 	server := model.Servers{
-		ID:                guild.ID,
-		ServerName:        guild.Name,
-		ChannelName:       channel.Name,
-		Schedule:          cronSchedule,
-		ShouldEditMessage: shouldEditMessage,
-		IsEnabled:         true,
+		ID:         guild.ID,
+		ServerName: guild.Name,
+		IsEnabled:  true,
+		Timezone:   timezone,
 	}
 
-	serverErrs := utils.ValidateServerConfig(s, server)
+	board.ChannelID = channel.ID
+	board.ChannelName = channel.Name
+	board.Schedule = cronSchedule
+	board.ShouldEditMessage = shouldEditMessage
+
+	boardErrs := utils.ValidateBoardConfig(s, server, board)
 	activityErrs := utils.ValidateActivities(activities)
 
-	if serverErrs != nil || activityErrs != nil {
+	if boardErrs != nil || activityErrs != nil {
 		errMessage := fmt.Sprintf(
 			"\n%s\n%s",
-			serverErrs,
+			boardErrs,
 			activityErrs,
 		)
 		cronEmoji := types.ApplicationEmojis["crontab"]
@@ -254,29 +305,72 @@ func ConfigureModalSubmit(s *discordgo.Session, i *discordgo.InteractionCreate)
 		return
 	}
 
-	// Once we know what server the user selected we can store that choice
-	err = storage.EnrollServer(server, activities)
+	// Nothing is saved until the admin has seen what the board will look like
+	showConfigurePreview(s, i, pendingConfiguration{
+		server:     server,
+		board:      board,
+		activities: activities,
+	})
+}
+
+// boardModalCustomID builds the custom ID of the modal survey for a
+// board created or edited with /board. Display options aren't part of
+// the modal so they are carried through in the custom ID instead
+func boardModalCustomID(board model.Boards) string {
+	return fmt.Sprintf(
+		"modals_survey_board_%t_%t_%s",
+		board.HideUnranked,
+		board.ShowRank,
+		board.BoardName,
+	)
+}
+
+// boardFromModalCustomID works out which board a submitted modal survey
+// is configuring. /configure always configures the default board
+func boardFromModalCustomID(serverID string, customID string) (model.Boards, error) {
+	options, found := strings.CutPrefix(customID, "modals_survey_board_")
+	if !found {
+		board, err := store.FetchBoard(serverID, types.DefaultBoardName)
+		if err != nil {
+			return newBoard(serverID, types.DefaultBoardName), nil
+		}
+		return board, nil
+	}
+
+	parts := strings.SplitN(options, "_", 3)
+	if len(parts) != 3 {
+		return model.Boards{}, fmt.Errorf("Malformed board modal custom ID %s", customID)
+	}
+
+	hideUnranked, err := strconv.ParseBool(parts[0])
 	if err != nil {
-		log.Println(err)
-		return
+		return model.Boards{}, err
 	}
 
-	// Update the cron job for this server in case the user changed the schedule
-	schedule.Cron.Remove(schedule.ScheduledJobs[server.ServerName].JobID)
-	EnableServerMessageCronjob(server, s)
+	showRank, err := strconv.ParseBool(parts[1])
+	if err != nil {
+		return model.Boards{}, err
+	}
 
-	_, err = s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
-		Flags: discordgo.MessageFlagsEphemeral,
+	board := newBoard(serverID, parts[2])
+	board.HideUnranked = hideUnranked
+	board.ShowRank = showRank
 
-		Content: fmt.Sprintf("Channel %s is now successfully configured!", channel.Name),
-	})
+	return board, nil
+}
 
-	if err != nil {
-		log.Println(err)
-		return
+// formatNextRuns lists the next few times a schedule will run. Discord shows
+// timestamps in the timezone of whoever is reading them
+func formatNextRuns(expression string, timezone string) string {
+	runs, err := schedule.NextRuns(expression, timezone, 3)
+	if err != nil || len(runs) == 0 {
+		return ""
 	}
 
-	if !strings.HasPrefix(data.CustomID, "modals_survey") {
-		return
+	content := fmt.Sprintf("\nThe schedule `%s` runs in %s. Its next runs are:", expression, timezone)
+	for _, run := range runs {
+		content += fmt.Sprintf("\n* <t:%d:F>", run.Unix())
 	}
+
+	return content
 }
@@ -0,0 +1,292 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/michohl/osrs-clan-leaderboard/hiscores"
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+	"github.com/michohl/osrs-clan-leaderboard/schedule"
+	"github.com/michohl/osrs-clan-leaderboard/types"
+	"github.com/michohl/osrs-clan-leaderboard/utils"
+)
+
+// DiagnoseCommandInfo is the information we'll use to
+// "register" this command with Discord so it appears
+// as an option to end users
+var DiagnoseCommandInfo = discordgo.ApplicationCommand{
+	Name:                     "diagnose",
+	Description:              "Check everything the bot needs to post the leaderboards",
+	Type:                     discordgo.ChatApplicationCommand,
+	DefaultMemberPermissions: &manageServerPermission,
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Name:         "board",
+			Description:  "Only check this board",
+			Type:         discordgo.ApplicationCommandOptionString,
+			Required:     false,
+			Autocomplete: true,
+		},
+	},
+}
+
+// diagnosis is the outcome of a single check
+type diagnosis struct {
+	check   string
+	problem bool
+	detail  string
+}
+
+// DiagnoseHandler will take a command request from Discord and translate
+// that into an action. This is where we decide if we're taking action
+// or if Discord is just asking what autocomplete options are available
+func DiagnoseHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+		diagnoseCommand(s, i)
+	}
+}
+
+// Actually do the command the user is requesting
+func diagnoseCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	// The checks call Discord and the hiscores which can take a while
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	content, err := diagnoseServer(s, i)
+	if err != nil {
+		log.Println(err)
+	}
+
+	_, err = s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+		Flags:   discordgo.MessageFlagsEphemeral,
+		Content: content,
+	})
+	if err != nil {
+		log.Println(err)
+		return
+	}
+}
+
+func diagnoseServer(s *discordgo.Session, i *discordgo.InteractionCreate) (string, error) {
+	server, err := store.FetchServer(i.GuildID)
+	if err != nil {
+		return "This server hasn't been configured yet. Please run `/configure` first.", err
+	}
+
+	boards, err := store.FetchBoards(i.GuildID)
+	if err != nil {
+		return "Failed to fetch boards...", err
+	}
+
+	for _, option := range i.ApplicationCommandData().Options {
+		switch option.Name {
+		case "board":
+			boardName := option.StringValue()
+			board, err := store.FetchBoard(i.GuildID, boardName)
+			if err != nil {
+				return fmt.Sprintf("Board %s doesn't exist", boardName), err
+			}
+			boards = []model.Boards{board}
+		}
+	}
+
+	diagnoses := diagnoseServerSettings(s, server)
+	problems := countProblems(diagnoses)
+	sections := []string{formatDiagnoses("Server", diagnoses)}
+	for _, board := range boards {
+		diagnoses := diagnoseBoard(s, server, board)
+		problems += countProblems(diagnoses)
+		sections = append(sections, formatDiagnoses("Board "+board.BoardName, diagnoses))
+	}
+
+	content := "Everything looks good!\n"
+	if problems > 0 {
+		content = fmt.Sprintf("Found %d problem(s):\n", problems)
+	}
+
+	for _, section := range sections {
+		// Discord messages are limited to 2000 characters
+		if len(content)+len(section) > 1900 {
+			content += "\n..."
+			break
+		}
+		content += section
+	}
+
+	return content, nil
+}
+
+// diagnoseServerSettings checks what every board of a server relies on
+func diagnoseServerSettings(s *discordgo.Session, server model.Servers) []diagnosis {
+	diagnoses := []diagnosis{}
+
+	took, err := hiscores.Ping()
+	if err != nil {
+		diagnoses = append(diagnoses, diagnosis{"Hiscores", true, fmt.Sprintf("unreachable (%s)", err)})
+	} else {
+		diagnoses = append(diagnoses, diagnosis{"Hiscores", false, fmt.Sprintf("answered in %dms", took.Milliseconds())})
+	}
+
+	if len(types.ApplicationEmojis) == 0 {
+		diagnoses = append(diagnoses, diagnosis{"Emojis", true, "none of the bot's emojis are loaded. Let the bot's host know"})
+	}
+
+	if !server.IsEnabled {
+		diagnoses = append(diagnoses, diagnosis{"Posting", true, "disabled for this server so no schedules will run"})
+	}
+
+	if server.AdminChannelID == "" {
+		diagnoses = append(diagnoses, diagnosis{"Admin channel", false, "not set, problems are only logged by the bot"})
+	} else {
+		d := diagnoseChannel(s, server.ID, server.AdminChannelID, "Choose a new one with `/settings`")
+		d.check = "Admin channel"
+		diagnoses = append(diagnoses, d)
+	}
+
+	return diagnoses
+}
+
+// diagnoseBoard checks everything a board needs to be posted
+func diagnoseBoard(s *discordgo.Session, server model.Servers, board model.Boards) []diagnosis {
+	diagnoses := []diagnosis{}
+
+	// Look the channel up without boardChannel so we don't warn the admins again
+	fix := fmt.Sprintf("Use `%s` to choose a new channel", boardEditCommand(board))
+	channelID := board.ChannelID
+	if channelID == "" {
+		channels, err := s.GuildChannels(board.ServerID)
+		if err == nil {
+			var channel *discordgo.Channel
+			channel, err = utils.FindChannelByName(channels, board.ChannelName)
+			if err == nil {
+				channelID = channel.ID
+			}
+		}
+		if err != nil {
+			diagnoses = append(diagnoses, diagnosis{"Channel", true, fmt.Sprintf("%s. %s", err, fix)})
+		}
+	}
+	if channelID != "" {
+		diagnoses = append(diagnoses, diagnoseChannel(s, board.ServerID, channelID, fix))
+	}
+
+	diagnoses = append(diagnoses, diagnoseSchedule(server, board))
+
+	activities, err := store.FetchAllActivitiesAndSkills(board.ServerID, board.BoardName)
+	if err != nil {
+		diagnoses = append(diagnoses, diagnosis{"Activities", true, fmt.Sprintf("unable to fetch them (%s)", err)})
+		return diagnoses
+	}
+
+	return append(diagnoses, diagnoseActivities(activities)...)
+}
+
+// diagnoseChannel checks a channel still exists and the bot can post in it.
+// fix tells the admin how to pick another channel if it's gone
+func diagnoseChannel(s *discordgo.Session, guildID string, channelID string, fix string) diagnosis {
+	missing, err := utils.MissingChannelPermissions(s, guildID, channelID)
+	switch {
+	case errors.Is(err, utils.ErrChannelNotFound):
+		return diagnosis{"Channel", true, fmt.Sprintf("<#%s> no longer exists. %s", channelID, fix)}
+	case err != nil:
+		return diagnosis{"Channel", true, fmt.Sprintf("<#%s> can't be checked (%s)", channelID, err)}
+	case len(missing) > 0:
+		return diagnosis{"Channel", true, fmt.Sprintf("the bot is missing %s in <#%s>", strings.Join(missing, ", "), channelID)}
+	default:
+		return diagnosis{"Channel", false, fmt.Sprintf("<#%s>", channelID)}
+	}
+}
+
+// diagnoseSchedule checks a board's schedule is valid and actually scheduled
+func diagnoseSchedule(server model.Servers, board model.Boards) diagnosis {
+	if _, err := time.LoadLocation(server.Timezone); server.Timezone == "" || err != nil {
+		return diagnosis{"Schedule", true, fmt.Sprintf("unknown timezone %s. Fix it with `/configure`", server.Timezone)}
+	}
+
+	runs, err := schedule.NextRuns(board.Schedule, server.Timezone, 1)
+	if err != nil || len(runs) == 0 {
+		return diagnosis{"Schedule", true, fmt.Sprintf("invalid cron expression `%s`. Fix it with `%s`", board.Schedule, boardEditCommand(board))}
+	}
+
+	if _, ok := schedule.BoardJob(board.ServerID, board.BoardName); !ok && server.IsEnabled {
+		return diagnosis{"Schedule", true, fmt.Sprintf("`%s` isn't scheduled. Save the board again with `%s`", board.Schedule, boardEditCommand(board))}
+	}
+
+	return diagnosis{"Schedule", false, fmt.Sprintf("`%s`, next run <t:%d:R>", board.Schedule, runs[0].Unix())}
+}
+
+// diagnoseActivities checks a board's activities exist on the hiscores and have an emoji
+func diagnoseActivities(activities []string) []diagnosis {
+	unknown := []string{}
+	noEmoji := []string{}
+	for _, activity := range activities {
+		if utils.ValidateActivities(activity) != nil {
+			unknown = append(unknown, activity)
+			continue
+		}
+
+		name := activity
+		if hiscores.IsSeasonal(name) && strings.LastIndex(name, "(") > -1 {
+			name = name[:strings.LastIndex(name, "(")]
+		}
+		if _, ok := types.ApplicationEmojis[types.NormalizeEmojiName(name)]; !ok {
+			noEmoji = append(noEmoji, activity)
+		}
+	}
+
+	diagnoses := []diagnosis{}
+	switch {
+	case len(activities) == 0:
+		diagnoses = append(diagnoses, diagnosis{"Activities", true, "none are tracked. Add some with `/configure`"})
+	case len(unknown) > 0:
+		diagnoses = append(diagnoses, diagnosis{"Activities", true, fmt.Sprintf("not on the hiscores: %s. Fix them with `/configure`", strings.Join(unknown, ", "))})
+	default:
+		diagnoses = append(diagnoses, diagnosis{"Activities", false, fmt.Sprintf("%d tracked", len(activities))})
+	}
+
+	// Activities without an emoji still work, they just show a trophy instead
+	if len(noEmoji) > 0 && len(types.ApplicationEmojis) > 0 {
+		diagnoses = append(diagnoses, diagnosis{"Emojis", false, fmt.Sprintf("shown with a trophy: %s", strings.Join(noEmoji, ", "))})
+	}
+
+	return diagnoses
+}
+
+// countProblems counts the checks that failed
+func countProblems(diagnoses []diagnosis) int {
+	count := 0
+	for _, d := range diagnoses {
+		if d.problem {
+			count++
+		}
+	}
+
+	return count
+}
+
+// formatDiagnoses renders the checks of a server or board as a list
+func formatDiagnoses(title string, diagnoses []diagnosis) string {
+	content := fmt.Sprintf("\n**%s**\n", title)
+	for _, d := range diagnoses {
+		status := "OK"
+		if d.problem {
+			status = "**Problem**"
+		}
+		content += fmt.Sprintf("* %s: %s - %s\n", d.check, status, d.detail)
+	}
+
+	return content
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/bwmarrin/discordgo"
 	"github.com/michohl/osrs-clan-leaderboard/hiscores"
 	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
-	"github.com/michohl/osrs-clan-leaderboard/storage"
 	"github.com/michohl/osrs-clan-leaderboard/types"
 )
 
@@ -72,7 +71,7 @@ var HiscoreCommandInfo = discordgo.ApplicationCommand{
 func HiscoreAutocompleteHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	choices := []*discordgo.ApplicationCommandOptionChoice{}
 
-	allUsers, err := storage.FetchAllUsers(i.GuildID)
+	allUsers, err := store.FetchAllUsers(i.GuildID)
 	if err != nil {
 		log.Println(err)
 		return
@@ -141,7 +140,7 @@ func hiscoreCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
 
 	discoveredErrors := ""
 
-	osrsUser, err := storage.FetchUser(i.GuildID, hiscores.EncodeRSN(osrsUsername))
+	osrsUser, err := store.FetchUser(i.GuildID, hiscores.EncodeRSN(osrsUsername))
 	if err != nil {
 		log.Println("Failed to find user details. Attempting to make ad-hoc details")
 		osrsUser = model.Users{
@@ -158,7 +157,10 @@ func hiscoreCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		osrsUser.OsrsAccountType = accountType
 	}
 
-	userHiscores, err := hiscores.GetUserHiscores([]model.Users{osrsUser}, accountType)
+	userHiscores, failures, err := hiscores.GetUserHiscores([]model.Users{osrsUser}, accountType)
+	if err == nil && len(failures) > 0 {
+		err = failures[0].Err
+	}
 	if err != nil {
 		log.Println(err)
 
@@ -0,0 +1,97 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// NicknamesCommandInfo is the information we'll use to
+// "register" this command with Discord so it appears
+// as an option to end users
+var NicknamesCommandInfo = discordgo.ApplicationCommand{
+	Name:                     "nicknames",
+	Description:              "Set every linked member's nickname to their RSN and report any failures",
+	Type:                     discordgo.ChatApplicationCommand,
+	DefaultMemberPermissions: &manageServerPermission,
+	Options:                  []*discordgo.ApplicationCommandOption{},
+}
+
+// NicknamesHandler will take a command request from Discord and translate
+// that into an action. This is where we decide if we're taking action
+// or if Discord is just asking what autocomplete options are available
+func NicknamesHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+		nicknamesCommand(s, i)
+	}
+}
+
+// Actually do the command the user is requesting
+func nicknamesCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	// Defer our message so we have time to do processing
+	// before discord times us out (we get 15 minutes now)
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	content, err := formatNicknameReport(s, i.GuildID)
+	if err != nil {
+		log.Println(err)
+	}
+
+	_, err = s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+		Flags:   discordgo.MessageFlagsEphemeral,
+		Content: content,
+		// Only show the mentions, don't actually ping anybody
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	})
+	if err != nil {
+		log.Println(err)
+		return
+	}
+}
+
+func formatNicknameReport(s *discordgo.Session, guildID string) (string, error) {
+	server, err := store.FetchServer(guildID)
+	if err != nil {
+		return "This server hasn't been configured yet. Please run `/configure` first.", err
+	}
+
+	if !server.NicknameSyncEnabled {
+		return "Nickname sync is currently disabled. Use `/settings nickname_sync:True` to enable it.", nil
+	}
+
+	failures, err := SyncNicknames(s, server)
+	if err != nil {
+		return "Failed to update nicknames...", err
+	}
+
+	if len(failures) == 0 {
+		return "Every linked member's nickname is up to date!", nil
+	}
+
+	content := fmt.Sprintf("Unable to update the nickname of %d member(s):", len(failures))
+	for _, discordUserID := range slices.Sorted(maps.Keys(failures)) {
+		line := fmt.Sprintf("\n* <@%s>: %s", discordUserID, describeNicknameError(failures[discordUserID]))
+
+		// Stay under Discord's 2000 character message limit
+		if len(content)+len(line) > 1900 {
+			content += "\n..."
+			break
+		}
+		content += line
+	}
+
+	return content, nil
+}
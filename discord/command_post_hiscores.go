@@ -1,9 +1,13 @@
 package discord
 
 import (
+	"errors"
+	"fmt"
 	"log"
+	"strings"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/michohl/osrs-clan-leaderboard/types"
 )
 
 // PostHiscoresCommandInfo is the information we'll use to
@@ -13,7 +17,15 @@ var PostHiscoresCommandInfo = discordgo.ApplicationCommand{
 	Name:        "post",
 	Description: "Manually invoke posting a hiscores message",
 	Type:        discordgo.ChatApplicationCommand,
-	Options:     []*discordgo.ApplicationCommandOption{},
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Name:         "board",
+			Description:  "The board to post. Posts every board if left empty",
+			Type:         discordgo.ApplicationCommandOptionString,
+			Required:     false,
+			Autocomplete: true,
+		},
+	},
 }
 
 // PostHiscoresHandler will take a command request from Discord and translate
@@ -42,13 +54,51 @@ func postHiscoresCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		return
 	}
 
-	err = PostHiscoresMessages(i.GuildID, s)
+	boardNames := []string{}
+	if options := i.ApplicationCommandData().Options; len(options) > 0 {
+		boardNames = append(boardNames, options[0].StringValue())
+	} else {
+		boards, err := store.FetchBoards(i.GuildID)
+		if err != nil {
+			log.Println(err)
+		}
+		for _, board := range boards {
+			boardNames = append(boardNames, board.BoardName)
+		}
+	}
+
+	// Keep posting the remaining boards even if one of them fails
+	var errs []error
+	var busyBoards []string
+	for _, boardName := range boardNames {
+		err = postBoard(i.GuildID, boardName, types.RunTriggerManual, s)
+		switch {
+		case errors.Is(err, ErrRunInProgress):
+			busyBoards = append(busyBoards, boardName)
+		case err != nil:
+			errs = append(errs, fmt.Errorf("board %s: %w", boardName, err))
+		}
+	}
+
+	// Posting a board twice at once would duplicate its messages so we skip it instead
+	busyNote := ""
+	if len(busyBoards) > 0 {
+		busyNote = fmt.Sprintf(
+			"\nSkipped %s since it is already being posted. Try again once the current run finishes.",
+			strings.Join(busyBoards, ", "),
+		)
+	}
+
+	err = errors.Join(errs...)
+	if len(boardNames) == 0 {
+		err = errors.New("no boards are configured")
+	}
 	if err != nil {
 		log.Println(err)
 
 		_, err = s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
 			Flags:   discordgo.MessageFlagsEphemeral,
-			Content: "Failed to post hiscores message(s)...",
+			Content: "Failed to post hiscores message(s)..." + busyNote,
 		})
 		if err != nil {
 			log.Println(err)
@@ -58,9 +108,14 @@ func postHiscoresCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		return
 	}
 
+	content := "Hiscores message(s) posted!" + busyNote
+	if len(busyBoards) == len(boardNames) {
+		content = strings.TrimPrefix(busyNote, "\n")
+	}
+
 	_, err = s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
 		Flags:   discordgo.MessageFlagsEphemeral,
-		Content: "Hiscores message(s) posted!",
+		Content: content,
 	})
 	if err != nil {
 		log.Println(err)
@@ -0,0 +1,440 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/michohl/osrs-clan-leaderboard/hiscores"
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+	"github.com/michohl/osrs-clan-leaderboard/schedule"
+	"github.com/michohl/osrs-clan-leaderboard/utils"
+)
+
+// RanksCommandInfo is the information we'll use to
+// "register" this command with Discord so it appears
+// as an option to end users
+var RanksCommandInfo = discordgo.ApplicationCommand{
+	Name:                     "ranks",
+	Description:              "Manage the clan rank ladder and see who is due a promotion",
+	Type:                     discordgo.ChatApplicationCommand,
+	DefaultMemberPermissions: &manageServerPermission,
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Name:        "add",
+			Description: "Add a rank to the ladder (or update an existing one)",
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Name:        "name",
+					Description: "The name of the in-game rank e.g. Captain",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    true,
+				},
+				{
+					Name:        "position",
+					Description: "Where the rank sits on the ladder. Higher positions are better ranks",
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Required:    true,
+				},
+				{
+					Name:        "requirements",
+					Description: "Comma separated requirements e.g. Overall>=1500, Zulrah>=100",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    false,
+				},
+			},
+		},
+		{
+			Name:        "remove",
+			Description: "Remove a rank from the ladder",
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Name:         "name",
+					Description:  "The name of the rank to remove",
+					Type:         discordgo.ApplicationCommandOptionString,
+					Required:     true,
+					Autocomplete: true,
+				},
+			},
+		},
+		{
+			Name:        "list",
+			Description: "Show the rank ladder",
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+		},
+		{
+			Name:        "check",
+			Description: "Work out the rank every user deserves from fresh hiscores",
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+		},
+		{
+			Name:        "set",
+			Description: "Record the in-game rank a user currently holds",
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Name:         "osrs_user",
+					Description:  "The user's OSRS username",
+					Type:         discordgo.ApplicationCommandOptionString,
+					Required:     true,
+					Autocomplete: true,
+				},
+				{
+					Name:         "name",
+					Description:  "The rank they hold in game",
+					Type:         discordgo.ApplicationCommandOptionString,
+					Required:     true,
+					Autocomplete: true,
+				},
+			},
+		},
+		{
+			Name:        "report",
+			Description: "Post a promotion report to the hiscores channel on a schedule",
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Name:        "schedule",
+					Description: "A cron expression e.g. 0 19 * * SUN. Leave empty to stop posting reports",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    false,
+				},
+			},
+		},
+	},
+}
+
+// RanksAutocompleteHandler suggests either users or ranks
+// depending on which option the user is currently typing in
+func RanksAutocompleteHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	choices := []*discordgo.ApplicationCommandOptionChoice{}
+
+	focusedOption := ""
+	for _, option := range i.ApplicationCommandData().Options[0].Options {
+		if option.Focused {
+			focusedOption = option.Name
+		}
+	}
+
+	switch focusedOption {
+	case "osrs_user":
+		allUsers, err := store.FetchAllUsers(i.GuildID)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		for _, u := range allUsers {
+			choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+				Name:  u.OsrsUsername,
+				Value: u.OsrsUsername,
+			})
+		}
+	case "name":
+		ranks, err := store.FetchRanks(i.GuildID)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		for _, r := range ranks {
+			choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+				Name:  r.RankName,
+				Value: r.RankName,
+			})
+		}
+	}
+
+	// Discord will reject the response if we send more than 25 choices
+	if len(choices) > 25 {
+		choices = choices[:25]
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{
+			Choices: choices,
+		},
+	})
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+}
+
+// RanksHandler will take a command request from Discord and translate
+// that into an action. This is where we decide if we're taking action
+// or if Discord is just asking what autocomplete options are available
+func RanksHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+		ranksCommand(s, i)
+	}
+}
+
+// Actually do the command the user is requesting
+func ranksCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	subcommand := i.ApplicationCommandData().Options[0]
+
+	var content string
+	var err error
+
+	switch subcommand.Name {
+	case "add":
+		content, err = addRank(i, subcommand.Options)
+	case "remove":
+		content, err = removeRank(i, subcommand.Options)
+	case "list":
+		content, err = listRanks(i)
+	case "set":
+		content, err = setUserRank(i, subcommand.Options)
+	case "report":
+		content, err = setRankReportSchedule(s, i, subcommand.Options)
+	case "check":
+		checkRanks(s, i)
+		return
+	}
+
+	if err != nil {
+		log.Println(err)
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Println(err)
+		return
+	}
+}
+
+func addRank(i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) (string, error) {
+	rank := model.Ranks{
+		ServerID: i.GuildID,
+		RankName: strings.Trim(options[0].StringValue(), " "),
+		Position: int32(options[1].IntValue()),
+	}
+
+	if len(options) > 2 {
+		rank.Requirements = strings.Trim(options[2].StringValue(), " ")
+	}
+
+	requirements, err := hiscores.ParseRankRequirements(rank.Requirements)
+	if err != nil {
+		return err.Error(), nil
+	}
+
+	activities := []string{}
+	for _, requirement := range requirements {
+		activities = append(activities, requirement.Activity)
+	}
+
+	if len(activities) > 0 {
+		err = utils.ValidateActivities(strings.Join(activities, ","))
+		if err != nil {
+			return err.Error(), nil
+		}
+	}
+
+	err = store.EnrollRank(rank)
+	if err != nil {
+		return "Failed to save rank...", err
+	}
+
+	return fmt.Sprintf("Rank %s saved at position %d", rank.RankName, rank.Position), nil
+}
+
+func removeRank(i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) (string, error) {
+	rankName := options[0].StringValue()
+
+	err := store.RemoveRank(i.GuildID, rankName)
+	if err != nil {
+		return "Failed to remove rank...", err
+	}
+
+	return fmt.Sprintf("Rank %s removed", rankName), nil
+}
+
+func listRanks(i *discordgo.InteractionCreate) (string, error) {
+	ranks, err := store.FetchRanks(i.GuildID)
+	if err != nil {
+		return "Failed to fetch ranks...", err
+	}
+
+	if len(ranks) == 0 {
+		return "No ranks are configured. Use `/ranks add` to create one.", nil
+	}
+
+	content := "Rank ladder (highest first):"
+	for _, rank := range ranks {
+		requirements := rank.Requirements
+		if requirements == "" {
+			requirements = "No requirements"
+		}
+		content += fmt.Sprintf("\n%d. **%s**: %s", rank.Position, rank.RankName, requirements)
+	}
+
+	return content, nil
+}
+
+func setUserRank(i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) (string, error) {
+	osrsUsername := options[0].StringValue()
+	rankName := options[1].StringValue()
+
+	user, err := store.FetchUser(i.GuildID, hiscores.EncodeRSN(osrsUsername))
+	if err != nil {
+		return fmt.Sprintf("OSRS User %s isn't assigned in this server...", osrsUsername), err
+	}
+
+	ranks, err := store.FetchRanks(i.GuildID)
+	if err != nil {
+		return "Failed to fetch ranks...", err
+	}
+
+	// Rank checks compare names exactly so we store the name as it's configured
+	rankIndex := slices.IndexFunc(ranks, func(r model.Ranks) bool {
+		return strings.EqualFold(r.RankName, strings.Trim(rankName, " "))
+	})
+	if rankIndex == -1 {
+		return fmt.Sprintf("Rank %s doesn't exist. Use `/ranks list` to see the rank ladder.", rankName), nil
+	}
+	rankName = ranks[rankIndex].RankName
+
+	err = store.SetUserClanRank(user, rankName)
+	if err != nil {
+		return "Failed to save rank...", err
+	}
+
+	return fmt.Sprintf("OSRS User %s now holds rank %s", osrsUsername, rankName), nil
+}
+
+func setRankReportSchedule(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) (string, error) {
+	server, err := store.FetchServer(i.GuildID)
+	if err != nil {
+		return "This server hasn't been configured yet. Please run `/configure` first.", err
+	}
+
+	server.RankReportSchedule = ""
+	if len(options) > 0 {
+		server.RankReportSchedule = strings.Trim(options[0].StringValue(), " ")
+	}
+
+	if server.RankReportSchedule != "" && !utils.IsValidCronExpression(schedule.WithTimezone(server.RankReportSchedule, server.Timezone)) {
+		return fmt.Sprintf("Invalid cron expression: %s", server.RankReportSchedule), nil
+	}
+
+	err = store.UpdateRankReportSchedule(server.ID, server.RankReportSchedule)
+	if err != nil {
+		return "Failed to save the rank report schedule...", err
+	}
+
+	DisableRankReportCronjob(server)
+
+	if server.RankReportSchedule == "" {
+		return "Rank reports will no longer be posted", nil
+	}
+
+	err = EnableRankReportCronjob(server, s)
+	if err != nil {
+		return "Failed to schedule the rank report...", err
+	}
+
+	board, err := rankReportBoard(server.ID)
+	if err != nil {
+		return "Rank reports are scheduled but there is no board to post them to. Please run `/configure`.", err
+	}
+
+	return fmt.Sprintf(
+		"Rank reports will be posted to %s on schedule `%s`%s",
+		channelMention(board),
+		server.RankReportSchedule,
+		formatNextRuns(server.RankReportSchedule, server.Timezone),
+	), nil
+}
+
+// checkRanks fetches fresh hiscores and shows the admin the rank
+// every user deserves with anyone due a promotion at the top
+func checkRanks(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	// Defer our message so we have time to do processing
+	// before discord times us out (we get 15 minutes now)
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	content, err := formatRankCheck(i.GuildID)
+	if err != nil {
+		log.Println(err)
+	}
+
+	_, err = s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+		Flags:   discordgo.MessageFlagsEphemeral,
+		Content: content,
+		// Only show the mentions, don't actually ping anybody
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	})
+	if err != nil {
+		log.Println(err)
+		return
+	}
+}
+
+func formatRankCheck(guildID string) (string, error) {
+	server, err := store.FetchServer(guildID)
+	if err != nil {
+		return "This server hasn't been configured yet. Please run `/configure` first.", err
+	}
+
+	results, err := calculateRanks(server)
+	if err != nil {
+		return err.Error(), err
+	}
+
+	promotions := ""
+	others := ""
+	for _, result := range results {
+		if result.DuePromotion {
+			promotions += "\n" + formatPromotion(result)
+			continue
+		}
+
+		deservedRank := result.DeservedRank
+		if deservedRank == "" {
+			deservedRank = "No rank"
+		}
+		others += fmt.Sprintf("\n* %s: %s", result.User.OsrsUsername, deservedRank)
+	}
+
+	content := "**Due a promotion:**"
+	if promotions == "" {
+		content += "\nNobody"
+	} else {
+		content += promotions
+	}
+
+	if others != "" {
+		content += "\n\n**Up to date:**" + others
+	}
+
+	// Stay under Discord's 2000 character message limit
+	if len(content) > 1900 {
+		content = content[:1900] + "\n..."
+	}
+
+	return content, nil
+}
@@ -0,0 +1,190 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/michohl/osrs-clan-leaderboard/hiscores"
+	"github.com/michohl/osrs-clan-leaderboard/utils"
+
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+)
+
+// RenameCommandInfo lets a member (or an admin) update the
+// RSN of an already assigned OSRS user after a name change
+var RenameCommandInfo = discordgo.ApplicationCommand{
+	Name:        "rename",
+	Description: "Update an assigned OSRS user after they changed their RSN",
+	Type:        discordgo.ChatApplicationCommand,
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Name:         "osrs_user",
+			Description:  "The user's old OSRS username",
+			Type:         discordgo.ApplicationCommandOptionString,
+			Required:     true,
+			Autocomplete: true,
+		},
+		{
+			Name:        "new_osrs_user",
+			Description: "The user's new OSRS username",
+			Type:        discordgo.ApplicationCommandOptionString,
+			Required:    true,
+		},
+	},
+}
+
+// RenameAutocompleteHandler lists the server's users matching what has
+// been typed so far with the ones we suspect have been renamed at the top
+func RenameAutocompleteHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	typed := ""
+	for _, option := range i.ApplicationCommandData().Options {
+		if option.Focused {
+			typed = option.StringValue()
+		}
+	}
+
+	allUsers, err := store.FetchAllUsers(i.GuildID)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	choices := renameChoices(allUsers, typed)
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{
+			Choices: choices,
+		},
+	})
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+}
+
+// renameChoices are the users whose username contains typed, ignoring case,
+// with the ones we suspect have been renamed first
+func renameChoices(users []model.Users, typed string) []*discordgo.ApplicationCommandOptionChoice {
+	choices := []*discordgo.ApplicationCommandOptionChoice{}
+	typed = strings.ToLower(strings.TrimSpace(typed))
+
+	users = slices.Clone(users)
+	slices.SortStableFunc(users, func(a, b model.Users) int {
+		switch {
+		case a.PossiblyRenamed && !b.PossiblyRenamed:
+			return -1
+		case !a.PossiblyRenamed && b.PossiblyRenamed:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	for _, u := range users {
+		if !strings.Contains(strings.ToLower(u.OsrsUsername), typed) {
+			continue
+		}
+
+		name := u.OsrsUsername
+		if u.PossiblyRenamed {
+			name += " (possibly renamed)"
+		}
+
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  name,
+			Value: u.OsrsUsername,
+		})
+	}
+
+	// Discord will reject the response if we send more than 25 choices
+	if len(choices) > 25 {
+		choices = choices[:25]
+	}
+
+	return choices
+}
+
+// RenameHandler will take a command request from Discord and translate
+// that into an action. This is where we decide if we're taking action
+// or if Discord is just asking what autocomplete options are available
+func RenameHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+		renameCommand(s, i)
+	}
+}
+
+// Actually do the command the user is requesting
+func renameCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+
+	data := i.ApplicationCommandData().Options
+	osrsUsername := data[0].StringValue()
+	newOsrsUsername := data[1].StringValue()
+
+	content, err := renameUser(s, i, osrsUsername, newOsrsUsername)
+	if err != nil {
+		log.Println(err)
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Println(err)
+		return
+	}
+}
+
+// renameUser validates and applies a rename and returns the
+// message we should send back to the member who asked for it
+func renameUser(s *discordgo.Session, i *discordgo.InteractionCreate, osrsUsername string, newOsrsUsername string) (string, error) {
+	user, err := store.FetchUser(i.GuildID, hiscores.EncodeRSN(osrsUsername))
+	if err != nil {
+		return fmt.Sprintf("OSRS User %s isn't assigned in this server...", osrsUsername), err
+	}
+
+	// Only the linked member or someone who manages the server can rename a user
+	if user.DiscordUserID != i.Member.User.ID && !utils.IsServerAdmin(i.Member) {
+		return fmt.Sprintf("Only <@%s> or a server admin can rename OSRS User %s", user.DiscordUserID, osrsUsername), nil
+	}
+
+	newOsrsUsernameKey := hiscores.EncodeRSN(newOsrsUsername)
+
+	// A change in capitalization keeps the same key so it can't conflict with anything
+	if newOsrsUsernameKey != user.OsrsUsernameKey {
+		if _, err := store.FetchUser(i.GuildID, newOsrsUsernameKey); err == nil {
+			return fmt.Sprintf("OSRS User %s is already assigned in this server...", newOsrsUsername), nil
+		}
+	}
+
+	_, err = hiscores.GetPlayerHiscores(model.Users{OsrsUsername: newOsrsUsername, OsrsAccountType: "main"})
+	if err != nil {
+		return fmt.Sprintf("OSRS User %s couldn't be found...", newOsrsUsername), err
+	}
+
+	err = store.RenameUser(user, newOsrsUsernameKey, newOsrsUsername)
+	if err != nil {
+		return fmt.Sprintf("OSRS User %s couldn't be renamed...", osrsUsername), err
+	}
+
+	content := fmt.Sprintf("OSRS User %s renamed to %s", osrsUsername, newOsrsUsername)
+
+	server, err := store.FetchServer(i.GuildID)
+	if err == nil {
+		err = ApplyNickname(s, server, user.DiscordUserID)
+		if err != nil {
+			content += fmt.Sprintf("\nUnable to update <@%s>'s nickname because %s", user.DiscordUserID, describeNicknameError(err))
+		}
+	}
+
+	return content, err
+}
@@ -0,0 +1,55 @@
+package discord
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+)
+
+func TestRenameChoices(t *testing.T) {
+	users := []model.Users{
+		{OsrsUsername: "Zezima"},
+		{OsrsUsername: "Lynx Titan", PossiblyRenamed: true},
+		{OsrsUsername: "Woox"},
+	}
+
+	var many []model.Users
+	for n := range 30 {
+		many = append(many, model.Users{OsrsUsername: fmt.Sprintf("Player %d", n)})
+	}
+
+	tests := []struct {
+		name  string
+		users []model.Users
+		typed string
+		want  []string
+	}{
+		{name: "nothing typed", users: users, typed: "", want: []string{"Lynx Titan (possibly renamed)", "Zezima", "Woox"}},
+		{name: "filtered on what was typed", users: users, typed: "zez", want: []string{"Zezima"}},
+		{name: "ignores case", users: users, typed: "WOO", want: []string{"Woox"}},
+		{name: "matches inside the name", users: users, typed: "titan", want: []string{"Lynx Titan (possibly renamed)"}},
+		{name: "no matches", users: users, typed: "durial", want: []string{}},
+		{name: "capped at 25", users: many, typed: "player", want: func() []string {
+			var want []string
+			for _, u := range many[:25] {
+				want = append(want, u.OsrsUsername)
+			}
+			return want
+		}()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, choice := range renameChoices(tt.users, tt.typed) {
+				got = append(got, choice.Name)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("renameChoices() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
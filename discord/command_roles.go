@@ -0,0 +1,263 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/michohl/osrs-clan-leaderboard/hiscores"
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+)
+
+// RolesCommandInfo is the information we'll use to
+// "register" this command with Discord so it appears
+// as an option to end users
+var RolesCommandInfo = discordgo.ApplicationCommand{
+	Name:                     "roles",
+	Description:              "Manage roles that are granted automatically based on hiscores",
+	Type:                     discordgo.ChatApplicationCommand,
+	DefaultMemberPermissions: &manageServerPermission,
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Name:        "add",
+			Description: "Grant a role to members who reach a level or score in a skill or activity",
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Name:        "role",
+					Description: "The role to grant",
+					Type:        discordgo.ApplicationCommandOptionRole,
+					Required:    true,
+				},
+				{
+					Name:        "activity",
+					Description: "The skill or activity to check e.g. Overall or TzKal-Zuk",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    true,
+				},
+				{
+					Name:        "threshold",
+					Description: "The minimum level (skills) or score (activities) required",
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Required:    true,
+				},
+			},
+		},
+		{
+			Name:        "remove",
+			Description: "Stop granting a role automatically",
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Name:        "role",
+					Description: "The role to stop granting",
+					Type:        discordgo.ApplicationCommandOptionRole,
+					Required:    true,
+				},
+				{
+					Name:        "activity",
+					Description: "Only remove the rule for this skill or activity",
+					Type:        discordgo.ApplicationCommandOptionString,
+					Required:    false,
+				},
+			},
+		},
+		{
+			Name:        "list",
+			Description: "List every configured role rule",
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+		},
+		{
+			Name:        "preview",
+			Description: "Show which roles would be added or removed without changing anything",
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+		},
+	},
+}
+
+// RolesHandler will take a command request from Discord and translate
+// that into an action. This is where we decide if we're taking action
+// or if Discord is just asking what autocomplete options are available
+func RolesHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+		rolesCommand(s, i)
+	}
+}
+
+// Actually do the command the user is requesting
+func rolesCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	subcommand := i.ApplicationCommandData().Options[0]
+
+	var content string
+	var err error
+
+	switch subcommand.Name {
+	case "add":
+		content, err = addRoleRule(s, i, subcommand.Options)
+	case "remove":
+		content, err = removeRoleRules(s, i, subcommand.Options)
+	case "list":
+		content, err = listRoleRules(i)
+	case "preview":
+		previewRoleChanges(s, i)
+		return
+	}
+
+	if err != nil {
+		log.Println(err)
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Println(err)
+		return
+	}
+}
+
+func addRoleRule(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) (string, error) {
+	role := options[0].RoleValue(s, i.GuildID)
+	activity := strings.Trim(options[1].StringValue(), " ")
+	threshold := options[2].IntValue()
+
+	// Roles are synced from the main hiscores which don't
+	// have the activities of leagues or deadman mode
+	if hiscores.IsSeasonal(activity) {
+		return fmt.Sprintf("%s is only on the seasonal hiscores. Role rules can only use skills and activities from the main hiscores.", activity), nil
+	}
+
+	_, err := hiscores.IsActivityOrSkill(activity)
+	if err != nil {
+		return err.Error(), nil
+	}
+
+	err = store.EnrollRoleRule(model.RoleRules{
+		ServerID:  i.GuildID,
+		RoleID:    role.ID,
+		Activity:  activity,
+		Threshold: int32(threshold),
+	})
+	if err != nil {
+		return "Failed to save role rule...", err
+	}
+
+	return fmt.Sprintf("<@&%s> will be granted for %s >= %d", role.ID, activity, threshold), nil
+}
+
+func removeRoleRules(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) (string, error) {
+	role := options[0].RoleValue(s, i.GuildID)
+
+	activity := ""
+	if len(options) > 1 {
+		activity = strings.Trim(options[1].StringValue(), " ")
+	}
+
+	err := store.RemoveRoleRules(i.GuildID, role.ID, activity)
+	if err != nil {
+		return "Failed to remove role rule(s)...", err
+	}
+
+	return fmt.Sprintf("Role rule(s) removed for <@&%s>", role.ID), nil
+}
+
+func listRoleRules(i *discordgo.InteractionCreate) (string, error) {
+	rules, err := store.FetchRoleRules(i.GuildID)
+	if err != nil {
+		return "Failed to fetch role rules...", err
+	}
+
+	if len(rules) == 0 {
+		return "No role rules are configured. Use `/roles add` to create one.", nil
+	}
+
+	content := "Configured role rules:"
+	for _, rule := range rules {
+		content += fmt.Sprintf("\n* <@&%s> requires %s >= %d", rule.RoleID, rule.Activity, rule.Threshold)
+	}
+
+	return content, nil
+}
+
+// previewRoleChanges fetches fresh hiscores and shows the admin every role
+// change a sync would make. This is a dry run so nothing is applied
+func previewRoleChanges(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	// Defer our message so we have time to do processing
+	// before discord times us out (we get 15 minutes now)
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	content, err := formatRoleChangePreview(s, i.GuildID)
+	if err != nil {
+		log.Println(err)
+	}
+
+	_, err = s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+		Flags:   discordgo.MessageFlagsEphemeral,
+		Content: content,
+		// Only show the mentions, don't actually ping anybody
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	})
+	if err != nil {
+		log.Println(err)
+		return
+	}
+}
+
+func formatRoleChangePreview(s *discordgo.Session, guildID string) (string, error) {
+	server, err := store.FetchServer(guildID)
+	if err != nil {
+		return "This server hasn't been configured yet. Please run `/configure` first.", err
+	}
+
+	allUsers, err := store.FetchAllUsers(server.ID)
+	if err != nil {
+		return "Failed to fetch users...", err
+	}
+
+	userHiscores, _, err := hiscores.GetUserHiscores(allUsers, "")
+	if err != nil {
+		return "Failed to fetch hiscores...", err
+	}
+
+	changes, err := planRoleChanges(s, server, userHiscores)
+	if err != nil {
+		return "Failed to calculate role changes...", err
+	}
+
+	if len(changes) == 0 {
+		return "Every member already has the roles they should have.", nil
+	}
+
+	content := fmt.Sprintf("Role sync would make %d change(s):", len(changes))
+	for _, change := range changes {
+		line := "\n" + change.String()
+
+		// Stay under Discord's 2000 character message limit
+		if len(content)+len(line) > 1900 {
+			content += "\n..."
+			break
+		}
+		content += line
+	}
+
+	if !server.RoleSyncEnabled {
+		content += "\n\nRole sync is currently disabled. Use `/settings role_sync:True` to enable it."
+	}
+
+	return content, nil
+}
@@ -0,0 +1,209 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/michohl/osrs-clan-leaderboard/hiscores"
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+	"github.com/michohl/osrs-clan-leaderboard/schedule"
+	"github.com/michohl/osrs-clan-leaderboard/storage"
+)
+
+// ScheduleCommandInfo is the information we'll use to
+// "register" this command with Discord so it appears
+// as an option to end users
+var ScheduleCommandInfo = discordgo.ApplicationCommand{
+	Name:                     "schedule",
+	Description:              "Check on the schedules that post the leaderboards",
+	Type:                     discordgo.ChatApplicationCommand,
+	DefaultMemberPermissions: &manageServerPermission,
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Name:        "status",
+			Description: "Show when each leaderboard will be posted next and how its last run went",
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Name:         "board",
+					Description:  "Only show this board",
+					Type:         discordgo.ApplicationCommandOptionString,
+					Required:     false,
+					Autocomplete: true,
+				},
+			},
+		},
+	},
+}
+
+// ScheduleHandler will take a command request from Discord and translate
+// that into an action. This is where we decide if we're taking action
+// or if Discord is just asking what autocomplete options are available
+func ScheduleHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+		scheduleCommand(s, i)
+	}
+}
+
+// Actually do the command the user is requesting
+func scheduleCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	subcommand := i.ApplicationCommandData().Options[0]
+
+	var content string
+	var err error
+
+	switch subcommand.Name {
+	case "status":
+		content, err = scheduleStatus(i, subcommand.Options)
+	}
+
+	if err != nil {
+		log.Println(err)
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Println(err)
+		return
+	}
+}
+
+func scheduleStatus(i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) (string, error) {
+	server, err := store.FetchServer(i.GuildID)
+	if err != nil {
+		return "This server hasn't been configured yet. Please run `/configure` first.", err
+	}
+
+	boards, err := store.FetchBoards(i.GuildID)
+	if err != nil {
+		return "Failed to fetch boards...", err
+	}
+
+	if len(options) > 0 {
+		boardName := options[0].StringValue()
+		board, err := store.FetchBoard(i.GuildID, boardName)
+		if err != nil {
+			return fmt.Sprintf("Board %s doesn't exist", boardName), err
+		}
+		boards = []model.Boards{board}
+	}
+
+	if len(boards) == 0 {
+		return "No boards are configured. Use `/configure` or `/board create` to create one.", nil
+	}
+
+	content := formatCircuitStatus(hiscores.GetCircuitStatus())
+	if !server.IsEnabled {
+		content += "Posting is disabled for this server so none of these schedules will run.\n"
+	}
+
+	for _, board := range boards {
+		status, err := formatScheduleStatus(server, board)
+		if err != nil {
+			return "Failed to fetch schedule history...", err
+		}
+
+		// Discord messages are limited to 2000 characters
+		if len(content)+len(status) > 1900 {
+			content += "\n..."
+			break
+		}
+		content += status
+	}
+
+	return content, nil
+}
+
+// formatCircuitStatus describes whether we can reach the hiscores right now
+func formatCircuitStatus(status hiscores.CircuitStatus) string {
+	// The last error can quote a whole response from the API
+	if len(status.LastError) > 200 {
+		status.LastError = status.LastError[:200] + "..."
+	}
+
+	switch status.State {
+	case hiscores.CircuitOpen:
+		return fmt.Sprintf(
+			"Hiscores: **unavailable** since <t:%d:f>. Requests are paused until <t:%d:T> (%s)\n",
+			status.OpenedAt.Unix(),
+			status.OpenUntil.Unix(),
+			status.LastError,
+		)
+	case hiscores.CircuitHalfOpen:
+		return fmt.Sprintf("Hiscores: recovering from an outage since <t:%d:f>\n", status.OpenedAt.Unix())
+	}
+
+	return "Hiscores: available\n"
+}
+
+// formatScheduleStatus describes a board's schedule, when it runs
+// next and how its last run went for /schedule status
+func formatScheduleStatus(server model.Servers, board model.Boards) (string, error) {
+	content := fmt.Sprintf(
+		"\n**%s** posting to %s\nSchedule: `%s` in %s",
+		board.BoardName,
+		channelMention(board),
+		board.Schedule,
+		server.Timezone,
+	)
+
+	_, registered := schedule.BoardJob(board.ServerID, board.BoardName)
+	if !registered {
+		content += " (not scheduled)"
+	}
+
+	retryAt, postponed := postponedUntil(board.ServerID, board.BoardName)
+	if postponed {
+		content += fmt.Sprintf("\nPostponed until the hiscores are available again. Trying again <t:%d:R>", retryAt.Unix())
+	}
+
+	runs, err := schedule.NextRuns(board.Schedule, server.Timezone, 3)
+	if err != nil {
+		content += fmt.Sprintf("\nNext runs: unknown (%s)", err)
+	} else {
+		content += "\nNext runs:"
+		for _, run := range runs {
+			content += fmt.Sprintf(" <t:%d:f>", run.Unix())
+		}
+	}
+
+	lastRun, err := store.FetchLastScheduleRun(board.ServerID, board.BoardName)
+	if errors.Is(err, storage.ErrNotFound) {
+		return content + "\nLast run: never\n", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	duration := time.Duration(lastRun.DurationMs) * time.Millisecond
+	content += fmt.Sprintf(
+		"\nLast run: <t:%d:f> (%s) %s in %s",
+		lastRun.StartedAt.Unix(),
+		lastRun.TriggeredBy,
+		lastRun.Outcome,
+		duration.Round(100*time.Millisecond),
+	)
+	if lastRun.ErrorMessage != "" {
+		content += fmt.Sprintf(": %s", lastRun.ErrorMessage)
+	}
+
+	failures, err := store.FetchRunFetchFailures(lastRun)
+	if err != nil {
+		return "", err
+	}
+	if len(failures) > 0 {
+		content += fmt.Sprintf(" (%s)", formatFetchFailureCount(len(failures)))
+	}
+
+	return content + "\n", nil
+}
@@ -0,0 +1,223 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+	"github.com/michohl/osrs-clan-leaderboard/types"
+)
+
+// manageServerPermission restricts admin-only commands to
+// members who can manage the server by default
+var manageServerPermission int64 = discordgo.PermissionManageGuild
+
+// minCatchUpLateness and maxCatchUpLateness bound the catch_up_max_lateness setting (in hours)
+var minCatchUpLateness, maxCatchUpLateness float64 = 1, 720
+
+// minStaleDataMaxAge and maxStaleDataMaxAge bound the stale_data_max_age setting (in hours)
+var minStaleDataMaxAge, maxStaleDataMaxAge float64 = 0, 720
+
+// SettingsCommandInfo is the information we'll use to
+// "register" this command with Discord so it appears
+// as an option to end users
+var SettingsCommandInfo = discordgo.ApplicationCommand{
+	Name:                     "settings",
+	Description:              "View or change optional server settings",
+	Type:                     discordgo.ChatApplicationCommand,
+	DefaultMemberPermissions: &manageServerPermission,
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Name:        "member_leave",
+			Description: "What to do with a member's OSRS users when they leave the server",
+			Type:        discordgo.ApplicationCommandOptionString,
+			Required:    false,
+			Choices: []*discordgo.ApplicationCommandOptionChoice{
+				{
+					Name:  "Keep them on the leaderboards",
+					Value: types.MemberLeaveActionKeep,
+				},
+				{
+					Name:  "Unassign them",
+					Value: types.MemberLeaveActionUnassign,
+				},
+				{
+					Name:  "Archive them until the member rejoins",
+					Value: types.MemberLeaveActionArchive,
+				},
+			},
+		},
+		{
+			Name:        "role_sync",
+			Description: "Automatically add and remove roles configured with /roles",
+			Type:        discordgo.ApplicationCommandOptionBoolean,
+			Required:    false,
+		},
+		{
+			Name:        "nickname_sync",
+			Description: "Set each linked member's nickname to their primary RSN",
+			Type:        discordgo.ApplicationCommandOptionBoolean,
+			Required:    false,
+		},
+		{
+			Name:        "nickname_prefix",
+			Description: "Prefix synced nicknames with the account type e.g. [HCIM]",
+			Type:        discordgo.ApplicationCommandOptionBoolean,
+			Required:    false,
+		},
+		{
+			Name:        "catch_up",
+			Description: "Post leaderboards that were missed while the bot was offline when it starts again",
+			Type:        discordgo.ApplicationCommandOptionBoolean,
+			Required:    false,
+		},
+		{
+			Name:        "catch_up_max_lateness",
+			Description: "How many hours late a missed post can be and still be caught up",
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Required:    false,
+			MinValue:    &minCatchUpLateness,
+			MaxValue:    maxCatchUpLateness,
+		},
+		{
+			Name:        "orphaned_messages",
+			Description: "What to do with leaderboard messages of removed activities or old channels",
+			Type:        discordgo.ApplicationCommandOptionString,
+			Required:    false,
+			Choices: []*discordgo.ApplicationCommandOptionChoice{
+				{
+					Name:  "Delete them",
+					Value: types.OrphanedMessageActionDelete,
+				},
+				{
+					Name:  "Leave them in place marked as no longer updated",
+					Value: types.OrphanedMessageActionArchive,
+				},
+			},
+		},
+		{
+			Name:        "stale_data_max_age",
+			Description: "How many hours old a player's last hiscores can be and still be shown when fetching fails",
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Required:    false,
+			MinValue:    &minStaleDataMaxAge,
+			MaxValue:    maxStaleDataMaxAge,
+		},
+		{
+			Name:         "admin_channel",
+			Description:  "Channel to send problems with posting leaderboards and how to fix them to",
+			Type:         discordgo.ApplicationCommandOptionChannel,
+			Required:     false,
+			ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText},
+		},
+		{
+			Name:        "remove_admin_channel",
+			Description: "Stop sending problems to the admin channel",
+			Type:        discordgo.ApplicationCommandOptionBoolean,
+			Required:    false,
+		},
+	},
+}
+
+// SettingsHandler will take a command request from Discord and translate
+// that into an action. This is where we decide if we're taking action
+// or if Discord is just asking what autocomplete options are available
+func SettingsHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+		settingsCommand(s, i)
+	}
+}
+
+// Actually do the command the user is requesting
+func settingsCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+
+	server, err := store.FetchServer(i.GuildID)
+	if err != nil {
+		err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: "This server hasn't been configured yet. Please run `/configure` first.",
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		if err != nil {
+			log.Println(err)
+		}
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	for _, option := range options {
+		switch option.Name {
+		case "member_leave":
+			server.MemberLeaveAction = option.StringValue()
+		case "role_sync":
+			server.RoleSyncEnabled = option.BoolValue()
+		case "nickname_sync":
+			server.NicknameSyncEnabled = option.BoolValue()
+		case "nickname_prefix":
+			server.NicknameAccountTypePrefix = option.BoolValue()
+		case "catch_up":
+			server.CatchUpEnabled = option.BoolValue()
+		case "catch_up_max_lateness":
+			server.CatchUpMaxLatenessHours = int32(option.IntValue())
+		case "orphaned_messages":
+			server.OrphanedMessageAction = option.StringValue()
+		case "stale_data_max_age":
+			server.StaleDataMaxAgeHours = int32(option.IntValue())
+		case "admin_channel":
+			server.AdminChannelID = option.ChannelValue(nil).ID
+		case "remove_admin_channel":
+			if option.BoolValue() {
+				server.AdminChannelID = ""
+			}
+		}
+	}
+
+	content := "Current server settings:"
+	if len(options) > 0 {
+		err = store.UpdateServerSettings(server)
+		if err != nil {
+			log.Println(err)
+			content = "Failed to update server settings..."
+		} else {
+			content = "Server settings updated:"
+		}
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("%s\n%s", content, formatServerSettings(server)),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Println(err)
+		return
+	}
+}
+
+// formatServerSettings renders the optional settings for a server as a
+// bulleted list we can show back to the admin
+func formatServerSettings(server model.Servers) string {
+	adminChannel := "none"
+	if server.AdminChannelID != "" {
+		adminChannel = "<#" + server.AdminChannelID + ">"
+	}
+
+	return fmt.Sprintf(
+		"* Member leave action: `%s`\n* Role sync: `%t`\n* Nickname sync: `%t`\n* Nickname account type prefix: `%t`\n* Catch up missed posts: `%t` (up to %d hours late)\n* Orphaned messages: `%s`\n* Show old hiscores when fetching fails: up to %d hours old\n* Admin channel: %s",
+		server.MemberLeaveAction,
+		server.RoleSyncEnabled,
+		server.NicknameSyncEnabled,
+		server.NicknameAccountTypePrefix,
+		server.CatchUpEnabled,
+		server.CatchUpMaxLatenessHours,
+		server.OrphanedMessageAction,
+		server.StaleDataMaxAgeHours,
+		adminChannel,
+	)
+}
@@ -6,7 +6,6 @@ import (
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/michohl/osrs-clan-leaderboard/hiscores"
-	"github.com/michohl/osrs-clan-leaderboard/storage"
 
 	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
 )
@@ -44,7 +43,7 @@ func unassignCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	data := i.ApplicationCommandData().Options
 	osrsUsername := data[0].StringValue()
 
-	err := storage.RemoveUser(model.Users{
+	err := store.RemoveUser(model.Users{
 		OsrsUsernameKey: hiscores.EncodeRSN(osrsUsername),
 		ServerID:        i.GuildID,
 	})
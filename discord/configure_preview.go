@@ -0,0 +1,239 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+	"github.com/michohl/osrs-clan-leaderboard/types"
+)
+
+const (
+	// configurePreviewPrefix starts the custom ID of the buttons on a configuration preview
+	configurePreviewPrefix = "configure_preview_"
+
+	// configurePreviewTimeout is how long a preview waits to be saved. Discord
+	// stops us from editing the preview after 15 minutes anyway
+	configurePreviewTimeout = 15 * time.Minute
+
+	// previewActivities is how many of a board's activities are shown in its preview
+	previewActivities = 3
+)
+
+// pendingConfiguration is a board configuration that was submitted
+// but hasn't been saved yet because the admin is looking at its preview
+type pendingConfiguration struct {
+	server      model.Servers
+	board       model.Boards
+	activities  string
+	submittedAt time.Time
+}
+
+// pendingConfigurations are the configurations waiting to be saved
+// keyed by the ID of the modal submit that created them
+var pendingConfigurations = struct {
+	sync.Mutex
+	pending map[string]pendingConfiguration
+}{pending: map[string]pendingConfiguration{}}
+
+// showConfigurePreview shows the admin what the first activities of a board will
+// look like with Save and Cancel buttons. Nothing is saved until they press Save
+func showConfigurePreview(s *discordgo.Session, i *discordgo.InteractionCreate, pending pendingConfiguration) {
+	pending.submittedAt = time.Now()
+
+	pendingConfigurations.Lock()
+	for id, p := range pendingConfigurations.pending {
+		if time.Since(p.submittedAt) > configurePreviewTimeout {
+			delete(pendingConfigurations.pending, id)
+		}
+	}
+	pendingConfigurations.pending[i.ID] = pending
+	pendingConfigurations.Unlock()
+
+	embeds, note, err := generatePreview(pending)
+	if err != nil {
+		log.Printf("Unable to preview board %s in server %s: %s", pending.board.BoardName, pending.server.ServerName, err)
+	}
+
+	content := fmt.Sprintf(
+		"Here's a preview of the **%s** board in #%s. Nothing has been saved yet.%s\n"+
+			"Press Save to apply the configuration or Cancel to keep things as they are. This preview expires <t:%d:R>.",
+		pending.board.BoardName,
+		pending.board.ChannelName,
+		note,
+		pending.submittedAt.Add(configurePreviewTimeout).Unix(),
+	)
+
+	_, err = s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+		Flags:   discordgo.MessageFlagsEphemeral,
+		Content: content,
+		Embeds:  embeds,
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{
+				Components: []discordgo.MessageComponent{
+					discordgo.Button{
+						Label:    "Save",
+						Style:    discordgo.SuccessButton,
+						CustomID: configurePreviewPrefix + "save_" + i.ID,
+					},
+					discordgo.Button{
+						Label:    "Cancel",
+						Style:    discordgo.SecondaryButton,
+						CustomID: configurePreviewPrefix + "cancel_" + i.ID,
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+// generatePreview generates the first message of a board the way it would be
+// posted with the pending configuration. The note explains anything missing
+func generatePreview(pending pendingConfiguration) ([]*discordgo.MessageEmbed, string, error) {
+	users, err := store.FetchAllUsers(pending.server.ID)
+	if err != nil {
+		return nil, "\nUnable to generate a preview.", err
+	}
+
+	if len(users) == 0 {
+		return nil, "\nNobody is tracked yet so there's nothing to preview. Users can add themselves with `/assign`.", nil
+	}
+
+	activities := strings.Split(pending.activities, ",")
+	activities = activities[:min(previewActivities, len(activities))]
+
+	// The modal doesn't include settings like how old the hiscores we show can be
+	server, err := store.FetchServer(pending.server.ID)
+	if err != nil {
+		server = pending.server
+		server.StaleDataMaxAgeHours = types.DefaultStaleDataMaxAgeHours
+	}
+
+	userHiscores, err := fetchHiscores(server, users, "")
+	if err != nil {
+		return nil, "\nUnable to generate a preview.", err
+	}
+
+	userSeasonalHiscores := fetchedHiscores{scores: map[model.Users]types.Hiscores{}, stale: map[model.Users]time.Time{}}
+	if needsSeasonalHiscores(activities) {
+		userSeasonalHiscores, err = fetchHiscores(server, users, "seasonal")
+		if err != nil {
+			return nil, "\nUnable to generate a preview.", err
+		}
+	}
+
+	// Activities that fail are left out of the preview just like they would be posted
+	prepared, err := generateActivityEmbeds(pending.board, activities, userHiscores, userSeasonalHiscores)
+	if len(prepared) == 0 {
+		return nil, "\nUnable to generate a preview.", err
+	}
+
+	packed := packMessages(prepared)
+	if len(packed) == 0 {
+		return nil, "\nNobody is ranked in these activities yet so there's nothing to preview.", err
+	}
+
+	return packed[0].embeds, "", err
+}
+
+// ConfigurePreviewHandler takes action when the admin presses
+// Save or Cancel on the preview of a board configuration
+func ConfigurePreviewHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	// Saving reschedules every job of the server which can take a moment
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	action, id, _ := strings.Cut(strings.TrimPrefix(i.MessageComponentData().CustomID, configurePreviewPrefix), "_")
+
+	// Taking the configuration out means pressing Save twice can't save it twice
+	pendingConfigurations.Lock()
+	pending, ok := pendingConfigurations.pending[id]
+	delete(pendingConfigurations.pending, id)
+	pendingConfigurations.Unlock()
+
+	var content string
+	switch {
+	case !ok || pending.board.ServerID != i.GuildID || time.Since(pending.submittedAt) > configurePreviewTimeout:
+		content = "This preview has expired. Please configure the board again."
+	case action == "cancel":
+		content = fmt.Sprintf("Cancelled. The configuration of board %s has not been changed.", pending.board.BoardName)
+	default:
+		content, err = saveBoardConfiguration(s, pending)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+
+	_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content:    &content,
+		Embeds:     &[]*discordgo.MessageEmbed{},
+		Components: &[]discordgo.MessageComponent{},
+	})
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+// saveBoardConfiguration saves a board configuration the admin confirmed
+// and reschedules the server's jobs to match
+func saveBoardConfiguration(s *discordgo.Session, pending pendingConfiguration) (string, error) {
+	board := pending.board
+
+	// Remember where the board's messages were so we can clean up
+	// the ones it won't update any more once the change is saved
+	previous, err := store.FetchBoard(board.ServerID, board.BoardName)
+	if err != nil {
+		previous = model.Boards{}
+	}
+	previousMessages, err := store.FetchAllMessages(board.ServerID, board.BoardName)
+	if err != nil {
+		log.Println(err)
+	}
+
+	// Once we know what server the user selected we can store that choice
+	err = store.EnrollBoard(pending.server, board, pending.activities)
+	if err != nil {
+		// Nothing was saved so the previous configuration is still in place
+		return fmt.Sprintf("Failed to save the configuration for channel %s. Your previous configuration has not been changed. Please try again.", board.ChannelName), err
+	}
+
+	// The configuration is saved but its schedule isn't running yet
+	notScheduled := fmt.Sprintf("Board %s was saved but its schedule couldn't be updated. Please run `/configure` again to schedule it.", board.BoardName)
+
+	// The timezone applies to every board and the rank report so
+	// all of the server's jobs are rescheduled, not just this board's
+	server, err := store.FetchServer(pending.server.ID)
+	if err != nil {
+		return notScheduled, err
+	}
+	DisableServerMessageCronjob(server)
+	scheduleErr := EnableServerMessageCronjob(server, s)
+	DisableRankReportCronjob(server)
+	scheduleErr = errors.Join(scheduleErr, EnableRankReportCronjob(server, s))
+
+	cleanUpBoardMessages(s, server, previous, previousMessages, board)
+
+	if scheduleErr != nil {
+		return notScheduled, scheduleErr
+	}
+
+	return fmt.Sprintf(
+		"Board %s is now successfully configured in channel %s!%s",
+		board.BoardName,
+		board.ChannelName,
+		formatNextRuns(board.Schedule, server.Timezone),
+	), nil
+}
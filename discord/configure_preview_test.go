@@ -0,0 +1,69 @@
+package discord
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/michohl/osrs-clan-leaderboard/schedule"
+)
+
+func TestSaveBoardConfigurationReportsScheduleErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		boardSchedule string
+		rankSchedule  string
+		wantScheduled bool
+	}{
+		{name: "everything scheduled", boardSchedule: "0 19 * * SUN", rankSchedule: "0 12 * * MON", wantScheduled: true},
+		{name: "board schedule is invalid", boardSchedule: "not a schedule", rankSchedule: "0 12 * * MON"},
+		{name: "rank report schedule is invalid", boardSchedule: "0 19 * * SUN", rankSchedule: "not a schedule"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newTestStore(t)
+			s, _ := newFakeDiscord(t)
+
+			server := enrollTestServer(t, []string{"11"}, []string{"Overall"}, 0)
+			t.Cleanup(func() {
+				DisableServerMessageCronjob(server)
+				DisableRankReportCronjob(server)
+			})
+
+			err := store.UpdateRankReportSchedule(server.ID, tt.rankSchedule)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			board := newBoard(server.ID, "board0")
+			board.ChannelID = "11"
+			board.ChannelName = "channel-11"
+			board.Schedule = tt.boardSchedule
+
+			content, err := saveBoardConfiguration(s, pendingConfiguration{server: server, board: board, activities: "Overall"})
+
+			if tt.wantScheduled {
+				if err != nil || !strings.Contains(content, "successfully configured") {
+					t.Errorf("saving failed: %q %v", content, err)
+				}
+				if _, ok := schedule.BoardJob(server.ID, board.BoardName); !ok {
+					t.Error("board isn't scheduled")
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(content, "couldn't be updated") {
+				t.Errorf("schedule error wasn't reported: %q %v", content, err)
+			}
+
+			// The configuration itself is still saved
+			saved, err := store.FetchBoard(server.ID, board.BoardName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if saved.Schedule != tt.boardSchedule {
+				t.Errorf("saved schedule is %q, want %q", saved.Schedule, tt.boardSchedule)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+)
+
+// formatFetchFailureCount is the note shown on leaderboards
+// that are missing players we couldn't fetch
+func formatFetchFailureCount(count int) string {
+	if count == 1 {
+		return "1 player could not be fetched"
+	}
+
+	return fmt.Sprintf("%d players could not be fetched", count)
+}
+
+// markFetchProblems notes on an embed which players are missing or shown with old
+// hiscores so readers don't think those players dropped off the leaderboard
+func markFetchProblems(embed *discordgo.MessageEmbed, hs fetchedHiscores) {
+	notes := []string{}
+
+	missing := len(hs.failures) - len(hs.stale)
+	if missing > 0 {
+		notes = append(notes, formatFetchFailureCount(missing))
+	}
+
+	// Discord shows the timestamp right after the footer in each reader's own timezone
+	if len(hs.stale) > 0 {
+		oldest := time.Now()
+		for _, fetchedAt := range hs.stale {
+			if fetchedAt.Before(oldest) {
+				oldest = fetchedAt
+			}
+		}
+
+		if len(hs.stale) == 1 {
+			notes = append(notes, "1 player shown with data as of")
+		} else {
+			notes = append(notes, fmt.Sprintf("%d players shown with data as of", len(hs.stale)))
+		}
+		embed.Timestamp = oldest.Format(time.RFC3339)
+	}
+
+	if len(notes) > 0 {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: strings.Join(notes, " · ")}
+	}
+}
+
+// reportFetchFailures tells the admin channel which players a run
+// of a board left out and why we couldn't fetch them
+func reportFetchFailures(s *discordgo.Session, serverID string, boardName string, failures []model.RunFetchFailures) {
+	server, err := store.FetchServer(serverID)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	problem := fmt.Sprintf("The **%s** leaderboard was posted without up to date hiscores for these players:", boardName)
+	for _, f := range failures {
+		line := fmt.Sprintf("\n* %s (%s): %s", f.OsrsUsername, f.OsrsAccountType, f.Reason)
+
+		// Keep room in the embed for the rest of the notice
+		if len(problem)+len(line) > 1900 {
+			problem += "\n..."
+			break
+		}
+		problem += line
+	}
+
+	notifyAdmins(s, server, adminNotice{
+		key:     "fetch-failures/" + boardName,
+		title:   fmt.Sprintf("Some players of the %s leaderboard could not be fetched", boardName),
+		problem: problem,
+		hint: "Players that are not found on the hiscores have usually changed their RSN. " +
+			"Use `/rename` to update them. Other problems usually clear up by the next post.",
+	})
+}
@@ -15,6 +15,15 @@ var commands = []*discordgo.ApplicationCommand{
 	&UnassignCommandInfo,
 	&PostHiscoresCommandInfo,
 	&HiscoreCommandInfo,
+	&SettingsCommandInfo,
+	&RenameCommandInfo,
+	&RolesCommandInfo,
+	&NicknamesCommandInfo,
+	&RanksCommandInfo,
+	&BoardCommandInfo,
+	&ScheduleCommandInfo,
+	&CleanupCommandInfo,
+	&DiagnoseCommandInfo,
 }
 
 // CommandHandler is the contract any function we want to use as a handler must satisfy
@@ -28,11 +37,27 @@ var commandHandlers = map[string]CommandHandler{
 	"unassign":  UnassignHandler,
 	"post":      PostHiscoresHandler,
 	"hiscore":   HiscoreHandler,
+	"settings":  SettingsHandler,
+	"rename":    RenameHandler,
+	"roles":     RolesHandler,
+	"nicknames": NicknamesHandler,
+	"ranks":     RanksHandler,
+	"board":     BoardHandler,
+	"schedule":  ScheduleHandler,
+	"cleanup":   CleanupHandler,
+	"diagnose":  DiagnoseHandler,
 }
 
 var autocompleteHandlers = map[string]CommandHandler{
 	"hiscore":  HiscoreAutocompleteHandler,
 	"unassign": HiscoreAutocompleteHandler,
+	"rename":   RenameAutocompleteHandler,
+	"ranks":    RanksAutocompleteHandler,
+	"board":    BoardAutocompleteHandler,
+	"post":     BoardAutocompleteHandler,
+	"schedule": BoardAutocompleteHandler,
+	"cleanup":  BoardAutocompleteHandler,
+	"diagnose": BoardAutocompleteHandler,
 }
 
 // GetCommandHandler takes the user specified command and returns
@@ -53,7 +78,8 @@ func GetCommandHandler(command string) CommandHandler {
 // each modal survey is suffixed with the user's ID to make it unique.
 func GetModalSubmitHandler(customID string) CommandHandler {
 	switch {
-	case strings.Contains(customID, "modals_survey_configure_"):
+	case strings.Contains(customID, "modals_survey_configure_"),
+		strings.Contains(customID, "modals_survey_board_"):
 		return ConfigureModalSubmit
 	default:
 		log.Printf("No Modal Submit Handler that matches %s\n", customID)
@@ -62,6 +88,21 @@ func GetModalSubmitHandler(customID string) CommandHandler {
 	return nil
 }
 
+// GetComponentHandler takes the custom ID of a button or select menu
+// on one of our messages and tries to find which function should handle
+// the interaction. Like modal surveys each custom ID starts with a
+// hardcoded prefix followed by something that makes it unique.
+func GetComponentHandler(customID string) CommandHandler {
+	switch {
+	case strings.HasPrefix(customID, configurePreviewPrefix):
+		return ConfigurePreviewHandler
+	default:
+		log.Printf("No Component Handler that matches %s\n", customID)
+	}
+
+	return nil
+}
+
 // GetAutocompleteHandler takes the user specified command and returns
 // the relevant function responsible for generating autocomplete options
 func GetAutocompleteHandler(command string) CommandHandler {
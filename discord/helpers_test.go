@@ -0,0 +1,219 @@
+package discord
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/michohl/osrs-clan-leaderboard/storage"
+	"github.com/michohl/osrs-clan-leaderboard/types"
+)
+
+// testGuildID is the guild every fake channel belongs to
+const testGuildID = "100"
+
+// newTestStore points the package at an empty in-memory store
+func newTestStore(t *testing.T) *storage.MemoryStore {
+	t.Helper()
+
+	st := storage.NewMemoryStore()
+	store = st
+	return st
+}
+
+// fakeDiscord answers the Discord API calls made while posting a board
+type fakeDiscord struct {
+	mu sync.Mutex
+	// posted are the IDs of the messages posted to each channel
+	posted map[string][]string
+	// deleted are the IDs of the messages deleted from each channel
+	deleted map[string][]string
+	// history is what listing a channel's messages returns
+	history map[string][]*discordgo.Message
+	// sent are the messages posted to each channel
+	sent map[string][]*discordgo.MessageSend
+	// edited are the IDs of the messages edited in each channel
+	edited map[string][]string
+	// gone are the messages that were deleted and answer Unknown Message
+	gone map[string]bool
+	// forbidden channels answer every request with Missing Permissions
+	forbidden map[string]bool
+	nextID    int
+}
+
+// newFakeDiscord starts a fake Discord API and a session that talks to it
+func newFakeDiscord(t *testing.T) (*discordgo.Session, *fakeDiscord) {
+	t.Helper()
+
+	fake := &fakeDiscord{
+		posted:    map[string][]string{},
+		deleted:   map[string][]string{},
+		history:   map[string][]*discordgo.Message{},
+		sent:      map[string][]*discordgo.MessageSend{},
+		edited:    map[string][]string{},
+		gone:      map[string]bool{},
+		forbidden: map[string]bool{},
+		nextID:    1000,
+	}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	previous := discordgo.EndpointChannels
+	discordgo.EndpointChannels = srv.URL + "/channels/"
+	t.Cleanup(func() { discordgo.EndpointChannels = previous })
+
+	s, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.State.User = &discordgo.User{ID: "bot"}
+
+	return s, fake
+}
+
+func (f *fakeDiscord) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/channels/"), "/")
+	channelID := parts[0]
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case f.forbidden[channelID]:
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(discordgo.APIErrorMessage{Code: discordgo.ErrCodeMissingPermissions, Message: "Missing Permissions"})
+	case len(parts) == 1:
+		json.NewEncoder(w).Encode(discordgo.Channel{ID: channelID, GuildID: testGuildID, Name: "channel-" + channelID})
+	case len(parts) == 2 && r.Method == http.MethodPost:
+		var message discordgo.MessageSend
+		json.NewDecoder(r.Body).Decode(&message)
+		f.nextID++
+		id := fmt.Sprint(f.nextID)
+		f.posted[channelID] = append(f.posted[channelID], id)
+		f.sent[channelID] = append(f.sent[channelID], &message)
+		json.NewEncoder(w).Encode(discordgo.Message{ID: id, ChannelID: channelID})
+	case len(parts) == 2 && r.URL.Query().Get("before") == "":
+		json.NewEncoder(w).Encode(f.history[channelID])
+	case len(parts) == 2:
+		// The whole history fits on the first page
+		json.NewEncoder(w).Encode([]*discordgo.Message{})
+	case f.gone[parts[2]]:
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(discordgo.APIErrorMessage{Code: discordgo.ErrCodeUnknownMessage, Message: "Unknown Message"})
+	case r.Method == http.MethodDelete:
+		f.deleted[channelID] = append(f.deleted[channelID], parts[2])
+		f.gone[parts[2]] = true
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		io.Copy(io.Discard, r.Body)
+		f.edited[channelID] = append(f.edited[channelID], parts[2])
+		json.NewEncoder(w).Encode(discordgo.Message{ID: parts[2], ChannelID: channelID})
+	}
+}
+
+// postedTo returns the IDs of the messages posted to a channel
+func (f *fakeDiscord) postedTo(channelID string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string{}, f.posted[channelID]...)
+}
+
+// sentTo returns the messages posted to a channel
+func (f *fakeDiscord) sentTo(channelID string) []*discordgo.MessageSend {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]*discordgo.MessageSend{}, f.sent[channelID]...)
+}
+
+// editedIn returns the IDs of the messages edited in a channel
+func (f *fakeDiscord) editedIn(channelID string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string{}, f.edited[channelID]...)
+}
+
+// deletedFrom returns the IDs of the messages the bot deleted from a channel
+func (f *fakeDiscord) deletedFrom(channelID string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string{}, f.deleted[channelID]...)
+}
+
+// deleteByHand deletes a message the way someone in the server would
+func (f *fakeDiscord) deleteByHand(messageID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.gone[messageID] = true
+}
+
+// forbid makes a channel answer every request with Missing Permissions
+func (f *fakeDiscord) forbid(channelID string, forbidden bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.forbidden[channelID] = forbidden
+}
+
+// fakeHiscores answers requests to the hiscores API with made up
+// hiscores and passes every other request on to the real transport
+type fakeHiscores struct {
+	next http.RoundTripper
+}
+
+// useFakeHiscores replaces the hiscores API for the rest of a test
+func useFakeHiscores(t *testing.T) {
+	t.Helper()
+
+	previous := http.DefaultTransport
+	http.DefaultTransport = fakeHiscores{next: previous}
+	t.Cleanup(func() { http.DefaultTransport = previous })
+
+	// Formatting falls back to the trophy for activities without their own emoji
+	types.ApplicationEmojis["osrstrophy"] = &discordgo.Emoji{ID: "1", Name: "osrstrophy"}
+}
+
+func (f fakeHiscores) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.URL.Host != "secure.runescape.com" {
+		return f.next.RoundTrip(r)
+	}
+
+	// Every player gets different but stable hiscores
+	h := fnv.New32a()
+	h.Write([]byte(r.URL.Query().Get("player")))
+	n := int(h.Sum32() % 1000)
+
+	hs := types.Hiscores{
+		Name: r.URL.Query().Get("player"),
+		Skills: []types.SkillHiscore{
+			{ID: 0, Name: "Overall", Rank: 1000 - n, Level: 500 + n, XP: 100000 * (n + 1)},
+			{ID: 1, Name: "Attack", Rank: 1000 - n, Level: 1 + n%99, XP: 1000 * (n + 1)},
+		},
+		Activities: []types.ActivityHiscore{
+			{ID: 0, Name: "Zulrah", Rank: 1000 - n, Score: n + 1},
+			{ID: 1, Name: "Vorkath", Rank: 1000 - n, Score: 2*n + 1},
+		},
+	}
+	body, err := json.Marshal(hs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(string(body))),
+		Request:    r,
+	}, nil
+}
@@ -0,0 +1,97 @@
+package discord
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/michohl/osrs-clan-leaderboard/hiscores"
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+	"github.com/michohl/osrs-clan-leaderboard/types"
+)
+
+// fetchHiscores fetches the hiscores of a server's users on a leaderboard.
+// Users we can't fetch are shown with the last hiscores we fetched for them
+// as long as those aren't older than the server allows
+func fetchHiscores(server model.Servers, users []model.Users, leaderboardOverride string) (fetchedHiscores, error) {
+	fetched := fetchedHiscores{stale: map[model.Users]time.Time{}}
+
+	scores, failures, err := hiscores.GetUserHiscores(users, leaderboardOverride)
+	if err != nil {
+		return fetched, err
+	}
+	fetched.scores = scores
+
+	// Only fresh hiscores are saved so a snapshot never looks newer than it is
+	saveHiscoresSnapshots(scores, leaderboardOverride)
+
+	maxAge := time.Duration(server.StaleDataMaxAgeHours) * time.Hour
+	for _, f := range failures {
+		hs, fetchedAt, ok := lastKnownHiscores(f.User, leaderboardOverride, maxAge)
+		if ok {
+			fetched.scores[f.User] = hs
+			fetched.stale[f.User] = fetchedAt
+			f.Err = fmt.Errorf("%w (showing hiscores from %s)", f.Err, fetchedAt.Format(time.DateTime+" MST"))
+		}
+
+		fetched.failures = append(fetched.failures, f)
+	}
+
+	return fetched, nil
+}
+
+// saveHiscoresSnapshots remembers the hiscores we just fetched from a
+// leaderboard so we have something to show if we can't fetch them next time
+func saveHiscoresSnapshots(scores map[model.Users]types.Hiscores, leaderboardOverride string) {
+	now := time.Now().UTC()
+
+	snapshots := []model.HiscoresSnapshots{}
+	for user, hs := range scores {
+		data, err := json.Marshal(hs)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		snapshots = append(snapshots, model.HiscoresSnapshots{
+			OsrsUsername:    hiscores.EncodeRSN(user.OsrsUsername),
+			OsrsAccountType: user.OsrsAccountType,
+			Leaderboard:     leaderboardOverride,
+			FetchedAt:       now,
+			Hiscores:        string(data),
+		})
+	}
+
+	err := store.SaveHiscoresSnapshots(snapshots)
+	if err != nil {
+		log.Printf("Unable to save hiscores snapshots: %s", err)
+	}
+}
+
+// lastKnownHiscores returns the last hiscores we fetched for a user from
+// a leaderboard if we have any that aren't older than maxAge
+func lastKnownHiscores(user model.Users, leaderboardOverride string, maxAge time.Duration) (types.Hiscores, time.Time, bool) {
+	if maxAge <= 0 {
+		return types.Hiscores{}, time.Time{}, false
+	}
+
+	snapshot, err := store.FetchHiscoresSnapshot(hiscores.EncodeRSN(user.OsrsUsername), user.OsrsAccountType, leaderboardOverride)
+	if err != nil {
+		return types.Hiscores{}, time.Time{}, false
+	}
+
+	if time.Since(snapshot.FetchedAt) > maxAge {
+		log.Printf("Last hiscores of user %s are from %s which is too old to show\n", user.OsrsUsername, snapshot.FetchedAt)
+		return types.Hiscores{}, time.Time{}, false
+	}
+
+	var hs types.Hiscores
+	err = json.Unmarshal([]byte(snapshot.Hiscores), &hs)
+	if err != nil {
+		log.Printf("Unable to read last hiscores of user %s: %s", user.OsrsUsername, err)
+		return types.Hiscores{}, time.Time{}, false
+	}
+
+	return hs, snapshot.FetchedAt, true
+}
@@ -0,0 +1,36 @@
+package discord
+
+import (
+	"testing"
+	"time"
+
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+	"github.com/michohl/osrs-clan-leaderboard/types"
+)
+
+func TestSeasonalSnapshotsKeepMainFallback(t *testing.T) {
+	newTestStore(t)
+
+	user := model.Users{OsrsUsernameKey: "zezima", ServerID: testGuildID, OsrsUsername: "Zezima", OsrsAccountType: "main"}
+	main := types.Hiscores{Name: "Zezima", Skills: []types.SkillHiscore{{Name: "Attack", Level: 99}}}
+	seasonal := types.Hiscores{Name: "Zezima", Skills: []types.SkillHiscore{{Name: "Attack", Level: 12}}}
+
+	saveHiscoresSnapshots(map[model.Users]types.Hiscores{user: main}, "")
+	saveHiscoresSnapshots(map[model.Users]types.Hiscores{user: seasonal}, "seasonal")
+
+	hs, _, ok := lastKnownHiscores(user, "", time.Hour)
+	if !ok {
+		t.Fatal("no main hiscores to fall back on")
+	}
+	if level, _ := hs.GetLevelOrScore("Attack"); level != 99 {
+		t.Errorf("main fallback shows Attack %d, want 99", level)
+	}
+
+	hs, _, ok = lastKnownHiscores(user, "seasonal", time.Hour)
+	if !ok {
+		t.Fatal("no seasonal hiscores to fall back on")
+	}
+	if level, _ := hs.GetLevelOrScore("Attack"); level != 12 {
+		t.Errorf("seasonal fallback shows Attack %d, want 12", level)
+	}
+}
@@ -0,0 +1,144 @@
+package discord
+
+import (
+	"log"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+	"github.com/michohl/osrs-clan-leaderboard/types"
+)
+
+// ReadyHandler runs once the bot has connected to Discord. The Ready event
+// lists every guild the bot is still a member of so we use it to catch
+// any guilds that removed the bot while we were offline
+func ReadyHandler(s *discordgo.Session, r *discordgo.Ready) {
+	log.Println("Bot is up!")
+
+	currentGuilds := map[string]bool{}
+	for _, g := range r.Guilds {
+		currentGuilds[g.ID] = true
+	}
+
+	allServers, err := store.FetchAllServers()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	for _, server := range allServers {
+		if server.IsEnabled && !currentGuilds[server.ID] {
+			log.Printf("Bot is no longer a member of server '%s'", server.ServerName)
+			disableRemovedServer(server)
+		}
+	}
+}
+
+// GuildCreateHandler is called for every guild when we connect and
+// whenever the bot is added to a guild. If we previously disabled the
+// server because the bot was removed we can pick back up where we left off
+func GuildCreateHandler(s *discordgo.Session, g *discordgo.GuildCreate) {
+	server, err := store.FetchServer(g.ID)
+	if err != nil {
+		// Not every guild we're in will have been configured yet
+		return
+	}
+
+	resolveBoardChannelIDs(g.Guild)
+
+	if server.IsEnabled || server.DisabledReason != types.DisabledReasonGuildRemoved {
+		return
+	}
+
+	log.Printf("Bot was added back to server '%s'. Re-enabling it", server.ServerName)
+
+	err = store.SetServerEnabled(server.ID, true, "")
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	server.IsEnabled = true
+	server.DisabledReason = ""
+	EnableServerMessageCronjob(server, s)
+	EnableRankReportCronjob(server, s)
+}
+
+// GuildDeleteHandler is called when the bot is removed from a guild
+// or when a guild becomes unavailable because of a Discord outage
+func GuildDeleteHandler(s *discordgo.Session, g *discordgo.GuildDelete) {
+	// An outage isn't the same as being removed so we leave everything alone
+	if g.Unavailable {
+		log.Printf("Guild %s is temporarily unavailable", g.ID)
+		return
+	}
+
+	server, err := store.FetchServer(g.ID)
+	if err != nil || !server.IsEnabled {
+		return
+	}
+
+	log.Printf("Bot was removed from server '%s'", server.ServerName)
+	disableRemovedServer(server)
+}
+
+// GuildMemberAddHandler restores any archived OSRS users
+// when the Discord member they belong to rejoins the server
+func GuildMemberAddHandler(s *discordgo.Session, m *discordgo.GuildMemberAdd) {
+	server, err := store.FetchServer(m.GuildID)
+	if err != nil || !server.IsEnabled {
+		return
+	}
+
+	err = store.SetUsersArchived(server.ID, m.User.ID, false)
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+// GuildMemberRemoveHandler applies the server's configured
+// member leave action to every OSRS user linked to the departed member
+func GuildMemberRemoveHandler(s *discordgo.Session, m *discordgo.GuildMemberRemove) {
+	server, err := store.FetchServer(m.GuildID)
+	if err != nil || !server.IsEnabled {
+		return
+	}
+
+	err = applyMemberLeaveAction(server, m.User.ID)
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+// applyMemberLeaveAction removes or archives the OSRS users linked
+// to a Discord member depending on how the server is configured
+func applyMemberLeaveAction(server model.Servers, discordUserID string) error {
+	switch server.MemberLeaveAction {
+	case types.MemberLeaveActionUnassign:
+		log.Printf("Unassigning OSRS users for departed member %s in server '%s'", discordUserID, server.ServerName)
+		return store.RemoveUsersByDiscordID(server.ID, discordUserID)
+	case types.MemberLeaveActionArchive:
+		log.Printf("Archiving OSRS users for departed member %s in server '%s'", discordUserID, server.ServerName)
+		return store.SetUsersArchived(server.ID, discordUserID, true)
+	default:
+		return nil
+	}
+}
+
+// disableRemovedServer stops all scheduled work for a server the bot is
+// no longer a member of and forgets the messages we posted there since
+// we won't be able to edit or delete them anymore
+func disableRemovedServer(server model.Servers) {
+	DisableServerMessageCronjob(server)
+	DisableRankReportCronjob(server)
+
+	err := store.SetServerEnabled(server.ID, false, types.DisabledReasonGuildRemoved)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	err = store.ResetMessages(server.ID)
+	if err != nil {
+		log.Println(err)
+	}
+}
@@ -0,0 +1,98 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/michohl/osrs-clan-leaderboard/types"
+	"github.com/michohl/osrs-clan-leaderboard/utils"
+
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+)
+
+// Discord won't accept nicknames longer than this
+const maxNicknameLength = 32
+
+// formatNickname builds the nickname a member should have based on
+// their primary RSN e.g. "Zezima" or "[HCIM] Zezima"
+func formatNickname(server model.Servers, user model.Users) string {
+	nickname := user.OsrsUsername
+
+	if abbreviation, ok := types.AccountTypeAbbreviations[user.OsrsAccountType]; ok && server.NicknameAccountTypePrefix {
+		nickname = fmt.Sprintf("[%s] %s", abbreviation, nickname)
+	}
+
+	if len(nickname) > maxNicknameLength {
+		nickname = nickname[:maxNicknameLength]
+	}
+
+	return nickname
+}
+
+// ApplyNickname sets a member's nickname to their primary RSN if the
+// server has nickname sync enabled. Members that already have the
+// right nickname are left alone
+func ApplyNickname(s *discordgo.Session, server model.Servers, discordUserID string) error {
+	if !server.NicknameSyncEnabled || discordUserID == "" {
+		return nil
+	}
+
+	user, err := store.FetchPrimaryUser(server.ID, discordUserID)
+	if err != nil {
+		return err
+	}
+
+	member, err := s.GuildMember(server.ID, discordUserID)
+	if err != nil {
+		return err
+	}
+
+	nickname := formatNickname(server, user)
+	if member.Nick == nickname {
+		return nil
+	}
+
+	log.Printf("Setting nickname for member %s in server %s to %s", discordUserID, server.ServerName, nickname)
+	return s.GuildMemberNickname(server.ID, discordUserID, nickname)
+}
+
+// SyncNicknames applies nicknames to every linked member of a server and
+// returns the members we couldn't rename along with the reason why
+func SyncNicknames(s *discordgo.Session, server model.Servers) (map[string]error, error) {
+	failures := map[string]error{}
+
+	allUsers, err := store.FetchAllUsers(server.ID)
+	if err != nil {
+		return failures, err
+	}
+
+	checkedMembers := map[string]bool{}
+	for _, user := range allUsers {
+		if user.DiscordUserID == "" || checkedMembers[user.DiscordUserID] {
+			continue
+		}
+		checkedMembers[user.DiscordUserID] = true
+
+		err := ApplyNickname(s, server, user.DiscordUserID)
+		if err != nil {
+			log.Printf("Unable to set nickname for member %s in server %s: %s", user.DiscordUserID, server.ServerName, err)
+			failures[user.DiscordUserID] = err
+		}
+	}
+
+	return failures, nil
+}
+
+// describeNicknameError turns the errors Discord gives us when
+// renaming a member into something an admin can act on
+func describeNicknameError(err error) string {
+	switch {
+	case utils.IsDiscordErrorCode(err, discordgo.ErrCodeMissingPermissions):
+		return "the member's highest role is above the bot's role (or they own the server)"
+	case utils.IsDiscordErrorCode(err, discordgo.ErrCodeUnknownMember):
+		return "the member is no longer in the server"
+	default:
+		return err.Error()
+	}
+}
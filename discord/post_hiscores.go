@@ -1,208 +1,417 @@
 package discord
 
 import (
+	"errors"
+	"fmt"
 	"log"
-	"maps"
 	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/michohl/osrs-clan-leaderboard/hiscores"
 	"github.com/michohl/osrs-clan-leaderboard/schedule"
-	"github.com/michohl/osrs-clan-leaderboard/storage"
 	"github.com/michohl/osrs-clan-leaderboard/types"
 	"github.com/michohl/osrs-clan-leaderboard/utils"
 
 	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
 )
 
-// PostHiscoresMessages posts a message per activity to the user configured
-// channel. This is done on a cron configured by the user
-func PostHiscoresMessages(serverID string, s *discordgo.Session) error {
+// maxGenerationWorkers is how many activities of a board
+// are turned into embeds at the same time
+const maxGenerationWorkers = 4
+
+// fetchedHiscores are the hiscores of a server's users on one leaderboard
+// along with the users we couldn't fetch from it. Users we couldn't fetch
+// may still be in scores with old hiscores, stale says when those are from
+type fetchedHiscores struct {
+	scores   map[model.Users]types.Hiscores
+	failures []hiscores.FetchFailure
+	stale    map[model.Users]time.Time
+}
+
+// PostHiscoresMessages posts a message per activity on a board to the
+// board's channel. This is done on a cron configured by the user. It returns
+// the users that were left out because we couldn't fetch their hiscores
+func PostHiscoresMessages(serverID string, boardName string, s *discordgo.Session) ([]hiscores.FetchFailure, error) {
 
 	// Fetch the latest data from our db in case it has changed
-	server, err := storage.FetchServer(serverID)
+	server, err := store.FetchServer(serverID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	messages, err := storage.FetchAllMessages(serverID)
+	board, err := store.FetchBoard(serverID, boardName)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	channel, err := utils.GetChannel(s, server.ID, server.ChannelName)
+	messages, err := store.FetchAllMessages(serverID, boardName)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	allUsers, err := storage.FetchAllUsers(server.ID)
+	channel, err := boardChannel(s, board)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	allActivitiesAndSkills, err := storage.FetchAllActivitiesAndSkills(server.ID)
+	allUsers, err := store.FetchAllUsers(server.ID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	userHiscores, err := hiscores.GetUserHiscores(allUsers, "")
+	allActivitiesAndSkills, err := store.FetchAllActivitiesAndSkills(server.ID, boardName)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	userSeasonalHiscores := map[model.Users]types.Hiscores{}
-	for _, aos := range allActivitiesAndSkills {
-		if hiscores.IsSeasonal(aos) || slices.Contains(types.SEASONAL_ACTIVITIES, strings.ToLower(aos)) {
-			log.Println("At least one seasonal activity/skill detected so generating list of seasonal hiscores now...")
-			userSeasonalHiscores, err = hiscores.GetUserHiscores(allUsers, "seasonal")
-			if err != nil {
-				return err
-			}
-			break
+	userHiscores, err := fetchHiscores(server, allUsers, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Replacing the board with an empty one wouldn't tell anybody anything
+	if len(allUsers) > 0 && len(userHiscores.scores) == 0 {
+		err = errors.New("no hiscores could be fetched")
+		if hiscores.IsCircuitOpen() {
+			err = fmt.Errorf("%w: %w", err, hiscores.ErrCircuitOpen)
 		}
+		return userHiscores.failures, err
 	}
 
-	log.Printf("Generating %d Hiscores messages for server %s", len(messages), server.ServerName)
+	// Role problems shouldn't stop the leaderboards from being posted
+	if server.RoleSyncEnabled {
+		err = SyncRoles(s, server, userHiscores.scores)
+		if err != nil {
+			log.Printf("Unable to sync roles for server %s: %s", server.ServerName, err)
+			notifyAdmins(s, server, adminNotice{
+				key:     "roles",
+				title:   "Unable to update roles",
+				problem: err.Error(),
+				hint: remediationHint(err,
+					"Make sure the bot has the Manage Roles permission and its own role is above every role in `/roles`.",
+				),
+			})
+		}
+	}
 
-	// Keep track of each activity message that has been posted
-	// so we can prevent threads from skipping past each other
-	type preparedHiscoresMessage struct {
-		activity string
-		embed    *discordgo.MessageEmbed
+	userSeasonalHiscores := fetchedHiscores{scores: map[model.Users]types.Hiscores{}, stale: map[model.Users]time.Time{}}
+	if needsSeasonalHiscores(allActivitiesAndSkills) {
+		log.Println("At least one seasonal activity/skill detected so generating list of seasonal hiscores now...")
+		userSeasonalHiscores, err = fetchHiscores(server, allUsers, "seasonal")
+		if err != nil {
+			return nil, err
+		}
 	}
-	preparedEmbeds := map[int][]preparedHiscoresMessage{}
 
-	var wg sync.WaitGroup
-	for i, aos := range allActivitiesAndSkills {
-		wg.Add(1)
-		go func() error {
-			defer wg.Done()
-			log.Printf("Generating Hiscores message for activity %s", aos)
-			var messageEmbeds []*discordgo.MessageEmbed
+	log.Printf("Generating %d Hiscores messages for board %s in server %s", len(messages), board.BoardName, server.ServerName)
 
-			if hiscores.IsSeasonal(aos) && strings.LastIndex(aos, "(") != -1 {
+	prepared, generateErr := generateActivityEmbeds(board, allActivitiesAndSkills, userHiscores, userSeasonalHiscores)
+	if generateErr != nil {
+		log.Printf("Unable to generate some activities of board %s in server %s: %s", board.BoardName, server.ServerName, generateErr)
 
-			}
+		// Posting an empty board would delete every message it has
+		if len(prepared) == 0 {
+			return nil, generateErr
+		}
+	}
 
-			var hs map[model.Users]types.Hiscores
+	log.Println("All Hiscores are generated. Starting to post discord messages")
 
-			if hiscores.IsSeasonal(aos) {
-				if strings.LastIndex(aos, "(") != -1 {
-					aos = aos[:strings.LastIndex(aos, "(")]
-				}
-				hs = userSeasonalHiscores
-			} else if slices.Contains(types.SEASONAL_ACTIVITIES, strings.ToLower(aos)) {
-				hs = userSeasonalHiscores
-			} else {
-				hs = userHiscores
-			}
+	failures := append(userHiscores.failures, userSeasonalHiscores.failures...)
 
-			messageEmbeds, err = hiscores.FormatEmbeds(aos, hs, true, true)
+	// The activities that worked are still posted but the run is reported as failed
+	err = publishBoard(s, server, board, channel.ID, prepared)
+	return failures, errors.Join(err, generateErr)
+}
 
-			if err != nil {
-				return err
-			}
+// needsSeasonalHiscores checks if any of a board's activities
+// are ranked on the seasonal hiscores instead of the regular ones
+func needsSeasonalHiscores(activities []string) bool {
+	for _, aos := range activities {
+		if hiscores.IsSeasonal(aos) || slices.Contains(types.SEASONAL_ACTIVITIES, strings.ToLower(aos)) {
+			return true
+		}
+	}
+
+	return false
+}
 
-			log.Printf("Generated embeds for %s: %d\n", aos, len(messageEmbeds))
+// generateActivityEmbeds turns each activity of a board into embeds using a
+// fixed number of workers. The results are in the same order as activities.
+// Activities that fail are left out and their errors are returned together
+func generateActivityEmbeds(board model.Boards, activities []string, userHiscores fetchedHiscores, userSeasonalHiscores fetchedHiscores) ([]activityEmbeds, error) {
+	results := make([]activityEmbeds, len(activities))
+	errs := make([]error, len(activities))
+
+	positions := make(chan int)
+	var wg sync.WaitGroup
+	for range min(maxGenerationWorkers, len(activities)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
 
-			for _, messageEmbed := range messageEmbeds {
-				// If we filter out all of the users from an embed because every user has
-				// zero score or level 1 then we can just throw the whole message away
-				if messageEmbed != nil {
-					preparedEmbeds[i] = append(preparedEmbeds[i], preparedHiscoresMessage{activity: aos, embed: messageEmbed})
+			// Every position is handed to exactly one worker so
+			// the workers never write to the same element
+			for i := range positions {
+				results[i], errs[i] = generateActivity(board, activities[i], userHiscores, userSeasonalHiscores)
+				if errs[i] != nil {
+					errs[i] = fmt.Errorf("activity %s: %w", activities[i], errs[i])
 				}
 			}
-
-			return nil
 		}()
 	}
 
+	for i := range activities {
+		positions <- i
+	}
+	close(positions)
+
 	// Wait for all messages to be generated before posting
 	wg.Wait()
 
-	log.Println("All Hiscores are generated. Starting to post discord messages")
+	prepared := []activityEmbeds{}
+	for i, result := range results {
+		if errs[i] == nil {
+			prepared = append(prepared, result)
+		}
+	}
+
+	return prepared, errors.Join(errs...)
+}
 
-	for _, key := range slices.Sorted(maps.Keys(preparedEmbeds)) {
+// generateActivity formats the embeds of a single activity on a board
+func generateActivity(board model.Boards, aos string, userHiscores fetchedHiscores, userSeasonalHiscores fetchedHiscores) (activityEmbeds, error) {
+	log.Printf("Generating Hiscores message for activity %s", aos)
 
-		if len(preparedEmbeds[key]) < 1 {
-			continue
-		}
+	// Messages are stored under the configured activity name which
+	// still has the seasonal suffix we trim off while generating
+	prepared := activityEmbeds{activity: aos}
 
-		activityMessages, err := storage.FetchMessage(serverID, preparedEmbeds[key][0].activity)
-		if err != nil {
-			return err
+	var hs fetchedHiscores
+
+	if hiscores.IsSeasonal(aos) {
+		if strings.LastIndex(aos, "(") != -1 {
+			aos = aos[:strings.LastIndex(aos, "(")]
 		}
+		hs = userSeasonalHiscores
+	} else if slices.Contains(types.SEASONAL_ACTIVITIES, strings.ToLower(aos)) {
+		hs = userSeasonalHiscores
+	} else {
+		hs = userHiscores
+	}
 
-		for _, activityMessage := range activityMessages {
-			// We remove the message every time instead of editing the existing message so we can guarantee
-			// that the order the skills are posted in matches the order the server admin configured
-			if activityMessage.MessageID != "" && server.ShouldEditMessage {
-				log.Printf("Removing existing hiscores message for %s in server %s\n", activityMessage.Activity, server.ServerName)
-				err := s.ChannelMessageDelete(channel.ID, activityMessage.MessageID)
-				if err != nil {
-					log.Printf("Error removing existing message: %s", err)
-				}
+	messageEmbeds, err := hiscores.FormatEmbeds(aos, hs.scores, board.HideUnranked, !board.ShowRank)
+	if err != nil {
+		return prepared, err
+	}
 
-				err = storage.RemoveMessage(activityMessage)
-				if err != nil {
-					log.Printf("Error removing deleted message from db: %s", err)
-				}
-			}
+	log.Printf("Generated embeds for %s: %d\n", aos, len(messageEmbeds))
+
+	for _, messageEmbed := range messageEmbeds {
+		// If we filter out all of the users from an embed because every user has
+		// zero score or level 1 then we can just throw the whole message away
+		if messageEmbed != nil {
+			prepared.embeds = append(prepared.embeds, messageEmbed)
 		}
+	}
 
-		for _, embed := range preparedEmbeds[key] {
-			he := embed.embed
+	if len(prepared.embeds) > 0 {
+		markFetchProblems(prepared.embeds[len(prepared.embeds)-1], hs)
+	}
 
-			log.Printf("Posting new scheduled hiscores message for %s in server %s\n", embed.activity, server.ServerName)
-			newMessage, err := s.ChannelMessageSendEmbed(channel.ID, he)
-			if err != nil {
-				return err
-			}
+	return prepared, nil
+}
 
-			position, err := storage.FetchActivityPosition(serverID, embed.activity)
-			if err != nil {
-				return err
-			}
+// postBoard posts a board's hiscores messages and records how the
+// run went so admins can check on it with /schedule status. Only one
+// run can post a board at a time, others get ErrRunInProgress.
+//
+// Posts an admin didn't ask for wait for the hiscores to come back
+// rather than post old hiscores. Those return ErrPostponed
+func postBoard(serverID string, boardName string, triggeredBy string, s *discordgo.Session) error {
+	release, err := lockBoardRun(serverID, boardName)
+	if err != nil {
+		return err
+	}
+	defer release()
 
-			activityMessage := model.Messages{
-				MessageID: newMessage.ID,
-				ServerID:  serverID,
-				Activity:  embed.activity,
-				Position:  position,
-			}
+	startedAt := time.Now().UTC()
+	canPostpone := triggeredBy != types.RunTriggerManual
 
-			// Update our records to keep track of this message so we can edit it later
-			err = storage.EnrollMessage(server, activityMessage)
-			if err != nil {
-				return err
-			}
+	if canPostpone && hiscores.IsCircuitOpen() {
+		retryAt, ok := postponeBoard(serverID, boardName, s)
+		if ok {
+			return recordPostponedRun(serverID, boardName, triggeredBy, startedAt, retryAt)
 		}
 	}
 
-	return nil
+	failures, err := PostHiscoresMessages(serverID, boardName, s)
+
+	// The hiscores went down part way through and left us nothing to post
+	if canPostpone && errors.Is(err, hiscores.ErrCircuitOpen) {
+		retryAt, ok := postponeBoard(serverID, boardName, s)
+		if ok {
+			return recordPostponedRun(serverID, boardName, triggeredBy, startedAt, retryAt)
+		}
+	}
+	forgetPostponedBoard(serverID, boardName)
+
+	run := model.ScheduleRuns{
+		ServerID:    serverID,
+		BoardName:   boardName,
+		StartedAt:   startedAt,
+		DurationMs:  int32(time.Since(startedAt).Milliseconds()),
+		TriggeredBy: triggeredBy,
+		Outcome:     types.RunOutcomeSuccess,
+	}
+	if err != nil {
+		run.Outcome = types.RunOutcomeFailure
+		run.ErrorMessage = err.Error()
+	}
+
+	failureRows := []model.RunFetchFailures{}
+	for _, f := range failures {
+		failureRows = append(failureRows, model.RunFetchFailures{
+			ServerID:        serverID,
+			BoardName:       boardName,
+			StartedAt:       startedAt,
+			OsrsUsername:    f.User.OsrsUsername,
+			OsrsAccountType: f.User.OsrsAccountType,
+			Reason:          f.Err.Error(),
+		})
+	}
+
+	recordErr := store.RecordScheduleRun(run, failureRows)
+	if recordErr != nil {
+		log.Printf("Unable to record run of board %s in server %s: %s", boardName, serverID, recordErr)
+	}
+
+	if len(failures) > 0 {
+		reportFetchFailures(s, serverID, boardName, failureRows)
+	}
+
+	// Whoever used /post already sees what went wrong
+	switch {
+	case err == nil:
+		forgetAdminNotice(serverID, boardNoticeKey(boardName))
+	case triggeredBy != types.RunTriggerManual:
+		notifyBoardFailure(s, serverID, boardName, err)
+	}
+
+	return err
 }
 
-// EnableServerMessageCronjob takes information about all of our
-// enrolled servers and starts a cronjob to post their hiscores
-// update messages on the configured schedule
+// notifyBoardFailure tells the server's admins that a board couldn't be posted
+func notifyBoardFailure(s *discordgo.Session, serverID string, boardName string, problem error) {
+	// boardChannel has already told the admins about problems with the channel
+	if errors.Is(problem, utils.ErrChannelNotFound) || errors.Is(problem, utils.ErrChannelAmbiguous) {
+		return
+	}
+
+	server, err := store.FetchServer(serverID)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	board, err := store.FetchBoard(serverID, boardName)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	notifyAdmins(s, server, adminNotice{
+		key:     boardNoticeKey(boardName),
+		title:   fmt.Sprintf("Unable to post the %s leaderboard", boardName),
+		problem: problem.Error(),
+		hint: remediationHint(problem, fmt.Sprintf(
+			"Make sure the bot can View Channel, Send Messages, Embed Links and Read Message History in %s.",
+			channelMention(board),
+		)),
+	})
+}
+
+// recordPostponedRun records a run that was put off until retryAt
+func recordPostponedRun(serverID string, boardName string, triggeredBy string, startedAt time.Time, retryAt time.Time) error {
+	err := store.RecordScheduleRun(model.ScheduleRuns{
+		ServerID:     serverID,
+		BoardName:    boardName,
+		StartedAt:    startedAt,
+		DurationMs:   int32(time.Since(startedAt).Milliseconds()),
+		TriggeredBy:  triggeredBy,
+		Outcome:      types.RunOutcomePostponed,
+		ErrorMessage: fmt.Sprintf("%s, trying again <t:%d:R>", hiscores.ErrCircuitOpen, retryAt.Unix()),
+	}, nil)
+	if err != nil {
+		log.Printf("Unable to record run of board %s in server %s: %s", boardName, serverID, err)
+	}
+
+	return ErrPostponed
+}
+
+// EnableServerMessageCronjob takes information about one of our
+// enrolled servers and starts a cronjob per board to post their
+// hiscores update messages on the configured schedules
 //
 // We moved this outside the schedule package to avoid some circular imports
 func EnableServerMessageCronjob(server model.Servers, s *discordgo.Session) error {
+	boards, err := store.FetchBoards(server.ID)
+	if err != nil {
+		log.Printf("Unable to fetch boards for server %s because %s\n", server.ServerName, err)
+		return err
+	}
 
-	jobID, err := schedule.Cron.AddFunc(server.Schedule, func() {
-		PostHiscoresMessages(server.ID, s)
+	// One board that can't be scheduled shouldn't stop the others
+	var errs []error
+	for _, board := range boards {
+		errs = append(errs, EnableBoardMessageCronjob(server, board, s))
+	}
+
+	return errors.Join(errs...)
+}
+
+// EnableBoardMessageCronjob starts a cronjob to post a single
+// board's hiscores messages on the board's schedule
+func EnableBoardMessageCronjob(server model.Servers, board model.Boards, s *discordgo.Session) error {
+
+	jobID, err := schedule.Cron.AddFunc(schedule.WithTimezone(board.Schedule, server.Timezone), func() {
+		err := postBoard(server.ID, board.BoardName, types.RunTriggerSchedule, s)
+		if err != nil {
+			log.Printf("Unable to post board %s for server %s because %s\n", board.BoardName, server.ServerName, err)
+		}
 	})
 	if err != nil {
-		log.Printf("Unable to schedule cron job for server %s because %s\n", server.ServerName, err)
+		log.Printf("Unable to schedule cron job for board %s in server %s because %s\n", board.BoardName, server.ServerName, err)
+		return err
 	}
 
-	log.Printf("Cron successfully scheduled for server %s. Job ID %d\n", server.ServerName, jobID)
-	schedule.ScheduledJobs[server.ServerName] = types.CronSchedule{
+	log.Printf("Cron successfully scheduled for board %s in server %s. Job ID %d\n", board.BoardName, server.ServerName, jobID)
+	schedule.SetBoardJob(server.ID, board.BoardName, types.CronSchedule{
 		JobID:          jobID,
 		DiscordSession: s,
-	}
+	})
 
 	return nil
 }
+
+// DisableServerMessageCronjob removes the scheduled hiscores job of every
+// board in a server so it stops posting until it's enabled again
+func DisableServerMessageCronjob(server model.Servers) {
+	for boardName, job := range schedule.RemoveServerJobs(server.ID) {
+		log.Printf("Cron removed for board %s in server %s. Job ID %d\n", boardName, server.ID, job.JobID)
+	}
+}
+
+// DisableBoardMessageCronjob removes the scheduled hiscores job for a
+// board (if there is one) so it stops posting until it's enabled again
+func DisableBoardMessageCronjob(serverID string, boardName string) {
+	job, ok := schedule.RemoveBoardJob(serverID, boardName)
+	if !ok {
+		return
+	}
+
+	log.Printf("Cron removed for board %s in server %s. Job ID %d\n", boardName, serverID, job.JobID)
+}
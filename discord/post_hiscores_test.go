@@ -0,0 +1,232 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/michohl/osrs-clan-leaderboard/hiscores"
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+	"github.com/michohl/osrs-clan-leaderboard/schedule"
+	"github.com/michohl/osrs-clan-leaderboard/types"
+)
+
+// enrollTestServer creates a server with a board per channel tracking
+// activities and a few players for the boards to rank
+func enrollTestServer(t *testing.T, channelIDs []string, activities []string, players int) model.Servers {
+	t.Helper()
+
+	server := model.Servers{
+		ID:                   testGuildID,
+		ServerName:           "Test Server",
+		IsEnabled:            true,
+		Timezone:             "UTC",
+		StaleDataMaxAgeHours: types.DefaultStaleDataMaxAgeHours,
+	}
+
+	for i, channelID := range channelIDs {
+		board := newBoard(server.ID, fmt.Sprintf("board%d", i))
+		board.ChannelID = channelID
+		board.ChannelName = "channel-" + channelID
+		board.Schedule = "0 19 * * SUN"
+		board.ShouldEditMessage = true
+
+		err := store.EnrollBoard(server, board, strings.Join(activities, ","))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := range players {
+		err := store.EnrollUser(model.Users{
+			OsrsUsernameKey: fmt.Sprintf("player%d", i),
+			ServerID:        server.ID,
+			OsrsUsername:    fmt.Sprintf("player%d", i),
+			OsrsAccountType: "main",
+			DiscordUserID:   fmt.Sprint(500 + i),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	server, err := store.FetchServer(server.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return server
+}
+
+func TestGenerateActivityEmbedsKeepsActivityOrder(t *testing.T) {
+	newTestStore(t)
+	useFakeHiscores(t)
+
+	server := enrollTestServer(t, []string{"1"}, []string{"Overall"}, 8)
+	users, err := store.FetchAllUsers(server.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fetched, err := fetchHiscores(server, users, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	noSeasonal := fetchedHiscores{scores: map[model.Users]types.Hiscores{}, stale: map[model.Users]time.Time{}}
+
+	// More activities than workers, with a bad one in the middle
+	activities := []string{}
+	for range 5 {
+		activities = append(activities, "Overall", "Zulrah", "Attack", "Vorkath")
+	}
+	activities = slices.Insert(activities, 7, "Not An Activity")
+
+	board := newBoard(server.ID, "board0")
+	prepared, err := generateActivityEmbeds(board, activities, fetched, noSeasonal)
+	if !errors.Is(err, hiscores.ErrUnknownActivity) {
+		t.Fatalf("expected the unknown activity to be reported, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "activity Not An Activity") {
+		t.Errorf("error doesn't name the activity that failed: %s", err)
+	}
+
+	want := slices.Delete(slices.Clone(activities), 7, 8)
+	got := []string{}
+	for _, p := range prepared {
+		got = append(got, p.activity)
+		if len(p.embeds) == 0 {
+			t.Errorf("activity %s has no embeds", p.activity)
+		}
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("activities out of order\n got: %v\nwant: %v", got, want)
+	}
+}
+
+func TestPostBoardConcurrently(t *testing.T) {
+	newTestStore(t)
+	useFakeHiscores(t)
+	s, fake := newFakeDiscord(t)
+
+	channelIDs := []string{"11", "12", "13"}
+	server := enrollTestServer(t, channelIDs, []string{"Overall", "Attack", "Zulrah", "Vorkath"}, 12)
+
+	boards, err := store.FetchBoards(server.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every board is posted twice at once. Only one of each pair can hold the
+	// lock at a time so the other either waits its turn or is turned away
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*len(boards))
+	for _, board := range boards {
+		for range 2 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs <- postBoard(server.ID, board.BoardName, types.RunTriggerManual, s)
+			}()
+		}
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil && !errors.Is(err, ErrRunInProgress) {
+			t.Errorf("unexpected error posting a board: %s", err)
+		}
+	}
+
+	for _, board := range boards {
+		messages, err := store.FetchAllMessages(server.ID, board.BoardName)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tracked := []string{}
+		for _, m := range messages {
+			// Every activity also has a row without a message to remember its position
+			if m.MessageID != "" && !slices.Contains(tracked, m.MessageID) {
+				tracked = append(tracked, m.MessageID)
+			}
+		}
+
+		if len(tracked) == 0 {
+			t.Errorf("board %s doesn't track any messages", board.BoardName)
+		}
+
+		// A second run edits the first run's messages rather than posting more
+		posted := fake.postedTo(board.ChannelID)
+		slices.Sort(posted)
+		slices.Sort(tracked)
+		if !slices.Equal(posted, tracked) {
+			t.Errorf("board %s posted %v but tracks %v", board.BoardName, posted, tracked)
+		}
+
+		run, err := store.FetchLastSuccessfulScheduleRun(server.ID, board.BoardName)
+		if err != nil {
+			t.Errorf("board %s has no successful run recorded: %s", board.BoardName, err)
+		} else if run.Outcome != types.RunOutcomeSuccess {
+			t.Errorf("board %s last run was %s", board.BoardName, run.Outcome)
+		}
+	}
+}
+
+func TestEnableAndDisableBoardJobsConcurrently(t *testing.T) {
+	newTestStore(t)
+	s, _ := newFakeDiscord(t)
+
+	server := enrollTestServer(t, []string{"11", "12", "13", "14"}, []string{"Overall"}, 0)
+	boards, err := store.FetchBoards(server.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The cron isn't started so none of the jobs actually run
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for _, board := range boards {
+				err := EnableBoardMessageCronjob(server, board, s)
+				if err != nil {
+					t.Error(err)
+				}
+				schedule.BoardJob(board.ServerID, board.BoardName)
+				if i%2 == 0 {
+					DisableBoardMessageCronjob(board.ServerID, board.BoardName)
+				}
+			}
+			if i%3 == 0 {
+				DisableServerMessageCronjob(server)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Every board ends up with at most one job no matter how the calls interleaved
+	err = EnableServerMessageCronjob(server, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, board := range boards {
+		if _, ok := schedule.BoardJob(board.ServerID, board.BoardName); !ok {
+			t.Errorf("board %s isn't scheduled", board.BoardName)
+		}
+	}
+	if entries := len(schedule.Cron.Entries()); entries != len(boards) {
+		t.Errorf("expected %d cron entries, got %d", len(boards), entries)
+	}
+
+	DisableServerMessageCronjob(server)
+	if entries := len(schedule.Cron.Entries()); entries != 0 {
+		t.Errorf("expected every cron entry to be removed, got %d", entries)
+	}
+}
@@ -0,0 +1,107 @@
+package discord
+
+import (
+	"errors"
+	"log"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/michohl/osrs-clan-leaderboard/hiscores"
+	"github.com/michohl/osrs-clan-leaderboard/schedule"
+	"github.com/michohl/osrs-clan-leaderboard/types"
+)
+
+// ErrPostponed is returned when a post waits for the hiscores to be available again
+var ErrPostponed = errors.New("postponed until the hiscores are available again")
+
+// maxPostponement is how long a post waits for the hiscores to come back. After
+// that it's posted with the last hiscores we have for everybody
+const maxPostponement = 6 * time.Hour
+
+// postponedBoards are the boards waiting for the hiscores to come back
+var postponedBoards = struct {
+	sync.Mutex
+	boards map[string]*postponedPost
+}{boards: map[string]*postponedPost{}}
+
+// postponedPost is a board waiting to be posted again
+type postponedPost struct {
+	since   time.Time
+	retryAt time.Time
+	timer   *time.Timer
+}
+
+// postponeBoard posts a board again once the hiscores should be available.
+// It returns false if the board has already waited as long as it can
+func postponeBoard(serverID string, boardName string, s *discordgo.Session) (time.Time, bool) {
+	key := schedule.BoardJobKey(serverID, boardName)
+	now := time.Now()
+
+	postponedBoards.Lock()
+	defer postponedBoards.Unlock()
+
+	p, ok := postponedBoards.boards[key]
+	if !ok {
+		p = &postponedPost{since: now}
+		postponedBoards.boards[key] = p
+	}
+
+	if now.Sub(p.since) >= maxPostponement {
+		return time.Time{}, false
+	}
+
+	if p.timer != nil {
+		p.timer.Stop()
+	}
+
+	// Spread the retries out a little so every board doesn't hit the hiscores at once
+	p.retryAt = hiscores.GetCircuitStatus().OpenUntil
+	if p.retryAt.Before(now) {
+		p.retryAt = now
+	}
+	p.retryAt = p.retryAt.Add(time.Duration(rand.Int64N(int64(time.Minute))))
+
+	p.timer = time.AfterFunc(time.Until(p.retryAt), func() {
+		err := postBoard(serverID, boardName, types.RunTriggerRetry, s)
+		if err != nil {
+			log.Printf("Unable to post postponed board %s for server %s because %s\n", boardName, serverID, err)
+		}
+	})
+
+	log.Printf("Postponed board %s in server %s until %s\n", boardName, serverID, p.retryAt)
+
+	return p.retryAt, true
+}
+
+// forgetPostponedBoard stops waiting to post a board again
+func forgetPostponedBoard(serverID string, boardName string) {
+	key := schedule.BoardJobKey(serverID, boardName)
+
+	postponedBoards.Lock()
+	defer postponedBoards.Unlock()
+
+	p, ok := postponedBoards.boards[key]
+	if !ok {
+		return
+	}
+
+	if p.timer != nil {
+		p.timer.Stop()
+	}
+	delete(postponedBoards.boards, key)
+}
+
+// postponedUntil returns when a postponed board will be tried again
+func postponedUntil(serverID string, boardName string) (time.Time, bool) {
+	postponedBoards.Lock()
+	defer postponedBoards.Unlock()
+
+	p, ok := postponedBoards.boards[schedule.BoardJobKey(serverID, boardName)]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return p.retryAt, true
+}
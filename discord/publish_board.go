@@ -0,0 +1,219 @@
+package discord
+
+import (
+	"cmp"
+	"log"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+	"github.com/michohl/osrs-clan-leaderboard/utils"
+)
+
+const (
+	// maxEmbedsPerMessage is the most embeds Discord allows in a single message
+	maxEmbedsPerMessage = 10
+
+	// maxEmbedLengthPerMessage is the most characters Discord allows
+	// across every embed in a single message
+	maxEmbedLengthPerMessage = 6000
+)
+
+// activityEmbeds are the embeds generated for a single activity on a board
+type activityEmbeds struct {
+	activity string
+	embeds   []*discordgo.MessageEmbed
+}
+
+// packedMessage is a single Discord message holding the embeds of
+// one or more activities. activities is every activity with at
+// least one embed in the message in the order they appear
+type packedMessage struct {
+	embeds     []*discordgo.MessageEmbed
+	activities []string
+}
+
+// packMessages groups the embeds of a board into as few messages as possible
+// while keeping them in order and within Discord's limits for a message
+func packMessages(prepared []activityEmbeds) []packedMessage {
+	packed := []packedMessage{}
+	current := packedMessage{}
+	currentLength := 0
+
+	for _, a := range prepared {
+		for _, embed := range a.embeds {
+			length := embedLength(embed)
+			if len(current.embeds) == maxEmbedsPerMessage || (len(current.embeds) > 0 && currentLength+length > maxEmbedLengthPerMessage) {
+				packed = append(packed, current)
+				current = packedMessage{}
+				currentLength = 0
+			}
+
+			current.embeds = append(current.embeds, embed)
+			currentLength += length
+			if !slices.Contains(current.activities, a.activity) {
+				current.activities = append(current.activities, a.activity)
+			}
+		}
+	}
+
+	if len(current.embeds) > 0 {
+		packed = append(packed, current)
+	}
+
+	return packed
+}
+
+// embedLength counts the characters of an embed the way Discord does
+// when it checks the limit for a whole message
+func embedLength(embed *discordgo.MessageEmbed) int {
+	length := utf8.RuneCountInString(embed.Title) + utf8.RuneCountInString(embed.Description)
+	for _, f := range embed.Fields {
+		length += utf8.RuneCountInString(f.Name) + utf8.RuneCountInString(f.Value)
+	}
+	if embed.Footer != nil {
+		length += utf8.RuneCountInString(embed.Footer.Text)
+	}
+	if embed.Author != nil {
+		length += utf8.RuneCountInString(embed.Author.Name)
+	}
+
+	return length
+}
+
+// publishBoard brings a board's messages in its channel up to date.
+//
+// When the board re-uses its messages the existing messages are edited in
+// order to hold the new embeds. Any messages we need on top of those are posted
+// at the bottom and any we no longer need are deleted. Discord can only add new
+// messages to the bottom of a channel so if someone deleted one of the messages
+// everything from that message onwards is deleted and posted again
+func publishBoard(s *discordgo.Session, server model.Servers, board model.Boards, channelID string, prepared []activityEmbeds) error {
+	existing := map[string][]model.Messages{}
+	existingIDs := []string{}
+	if board.ShouldEditMessage {
+		messages, err := store.FetchAllMessages(board.ServerID, board.BoardName)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range messages {
+			// Every activity has a row without a message to remember its position
+			if m.MessageID == "" {
+				continue
+			}
+			if _, ok := existing[m.MessageID]; !ok {
+				existingIDs = append(existingIDs, m.MessageID)
+			}
+			existing[m.MessageID] = append(existing[m.MessageID], m)
+		}
+
+		slices.SortFunc(existingIDs, compareSnowflakes)
+	}
+
+	packed := packMessages(prepared)
+	log.Printf("Packed board %s in server %s into %d messages\n", board.BoardName, server.ServerName, len(packed))
+
+	reposting := !board.ShouldEditMessage
+	for i, pm := range packed {
+		if !reposting && i < len(existingIDs) {
+			messageID := existingIDs[i]
+			_, err := s.ChannelMessageEditEmbeds(channelID, messageID, pm.embeds)
+			if err == nil {
+				err = trackMessage(board, messageID, pm.activities, existing[messageID])
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			if !utils.IsDiscordErrorCode(err, discordgo.ErrCodeUnknownMessage) {
+				return err
+			}
+
+			log.Printf("Hiscores message %s of board %s in server %s was deleted. Reposting from here on\n", messageID, board.BoardName, server.ServerName)
+			reposting = true
+			deleteMessages(s, channelID, existingIDs[i:], existing)
+		}
+
+		log.Printf("Posting new hiscores message for %s on board %s\n", strings.Join(pm.activities, ", "), board.BoardName)
+		newMessage, err := s.ChannelMessageSendEmbeds(channelID, pm.embeds)
+		if err != nil {
+			return err
+		}
+
+		err = trackMessage(board, newMessage.ID, pm.activities, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The board fits in fewer messages than it used to
+	if !reposting && len(existingIDs) > len(packed) {
+		deleteMessages(s, channelID, existingIDs[len(packed):], existing)
+	}
+
+	return nil
+}
+
+// trackMessage records which activities live in a message so we can edit it
+// later. rows are what we had recorded for the message before (if anything)
+func trackMessage(board model.Boards, messageID string, activities []string, rows []model.Messages) error {
+	for _, row := range rows {
+		if !slices.Contains(activities, row.Activity) {
+			err := store.RemoveMessage(row)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, activity := range activities {
+		position, err := store.FetchActivityPosition(board.ServerID, board.BoardName, activity)
+		if err != nil {
+			return err
+		}
+
+		err = store.EnrollMessage(model.Messages{
+			MessageID: messageID,
+			ServerID:  board.ServerID,
+			BoardName: board.BoardName,
+			Activity:  activity,
+			Position:  position,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteMessages removes messages from the channel and our records.
+// Messages someone already deleted by hand are simply forgotten
+func deleteMessages(s *discordgo.Session, channelID string, messageIDs []string, rows map[string][]model.Messages) {
+	for _, messageID := range messageIDs {
+		log.Printf("Removing existing hiscores message %s\n", messageID)
+		err := s.ChannelMessageDelete(channelID, messageID)
+		if err != nil && !utils.IsDiscordErrorCode(err, discordgo.ErrCodeUnknownMessage) {
+			log.Printf("Error removing existing message: %s", err)
+		}
+
+		for _, row := range rows[messageID] {
+			err = store.RemoveMessage(row)
+			if err != nil {
+				log.Printf("Error removing deleted message from db: %s", err)
+			}
+		}
+	}
+}
+
+// compareSnowflakes orders Discord IDs by when they were created
+func compareSnowflakes(a, b string) int {
+	if len(a) != len(b) {
+		return cmp.Compare(len(a), len(b))
+	}
+
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,287 @@
+package discord
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+)
+
+func TestEmbedLength(t *testing.T) {
+	tests := []struct {
+		name  string
+		embed *discordgo.MessageEmbed
+		want  int
+	}{
+		{name: "empty", embed: &discordgo.MessageEmbed{}, want: 0},
+		{
+			name: "every counted part",
+			embed: &discordgo.MessageEmbed{
+				Title:       "Zulrah",
+				Description: "Kills",
+				Fields: []*discordgo.MessageEmbedField{
+					{Name: "Player", Value: "Zezima"},
+					{Name: "Score", Value: "1,000"},
+				},
+				Footer: &discordgo.MessageEmbedFooter{Text: "footer"},
+				Author: &discordgo.MessageEmbedAuthor{Name: "author"},
+			},
+			want: 6 + 5 + 6 + 6 + 5 + 5 + 6 + 6,
+		},
+		{
+			name: "urls aren't counted",
+			embed: &discordgo.MessageEmbed{
+				Title:     "Zulrah",
+				URL:       "https://example.com",
+				Thumbnail: &discordgo.MessageEmbedThumbnail{URL: "https://example.com/image.png"},
+			},
+			want: 6,
+		},
+		{
+			name:  "characters rather than bytes",
+			embed: &discordgo.MessageEmbed{Title: "🏆 Zulrah", Description: "ñ"},
+			want:  8 + 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := embedLength(tt.embed); got != tt.want {
+				t.Errorf("embedLength() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPackMessages(t *testing.T) {
+	// embeds returns count embeds of length characters each
+	embeds := func(count int, length int) []*discordgo.MessageEmbed {
+		var e []*discordgo.MessageEmbed
+		for range count {
+			e = append(e, &discordgo.MessageEmbed{Description: strings.Repeat("x", length)})
+		}
+		return e
+	}
+
+	tests := []struct {
+		name     string
+		prepared []activityEmbeds
+		// Each message is described by how many embeds it has and its activities
+		wantEmbeds     []int
+		wantActivities [][]string
+	}{
+		{
+			name:           "nothing to post",
+			prepared:       nil,
+			wantEmbeds:     []int{},
+			wantActivities: [][]string{},
+		},
+		{
+			name:           "exactly ten embeds fit in one message",
+			prepared:       []activityEmbeds{{activity: "Overall", embeds: embeds(4, 10)}, {activity: "Zulrah", embeds: embeds(6, 10)}},
+			wantEmbeds:     []int{10},
+			wantActivities: [][]string{{"Overall", "Zulrah"}},
+		},
+		{
+			name:           "the eleventh embed starts a new message",
+			prepared:       []activityEmbeds{{activity: "Overall", embeds: embeds(4, 10)}, {activity: "Zulrah", embeds: embeds(7, 10)}},
+			wantEmbeds:     []int{10, 1},
+			wantActivities: [][]string{{"Overall", "Zulrah"}, {"Zulrah"}},
+		},
+		{
+			name:           "exactly 6000 characters fit in one message",
+			prepared:       []activityEmbeds{{activity: "Overall", embeds: embeds(2, 3000)}},
+			wantEmbeds:     []int{2},
+			wantActivities: [][]string{{"Overall"}},
+		},
+		{
+			name:           "an embed pushing past 6000 characters starts a new message",
+			prepared:       []activityEmbeds{{activity: "Overall", embeds: embeds(2, 3000)}, {activity: "Zulrah", embeds: embeds(1, 1)}},
+			wantEmbeds:     []int{2, 1},
+			wantActivities: [][]string{{"Overall"}, {"Zulrah"}},
+		},
+		{
+			name:           "an activity split across messages is listed in both",
+			prepared:       []activityEmbeds{{activity: "Overall", embeds: embeds(3, 2500)}},
+			wantEmbeds:     []int{2, 1},
+			wantActivities: [][]string{{"Overall"}, {"Overall"}},
+		},
+		{
+			name: "a single oversized embed gets a message to itself",
+			prepared: []activityEmbeds{
+				{activity: "Overall", embeds: embeds(1, 10)},
+				{activity: "Zulrah", embeds: embeds(1, 7000)},
+				{activity: "Vorkath", embeds: embeds(1, 10)},
+			},
+			wantEmbeds:     []int{1, 1, 1},
+			wantActivities: [][]string{{"Overall"}, {"Zulrah"}, {"Vorkath"}},
+		},
+		{
+			name:           "activities without embeds are left out",
+			prepared:       []activityEmbeds{{activity: "Overall", embeds: embeds(1, 10)}, {activity: "Zulrah"}},
+			wantEmbeds:     []int{1},
+			wantActivities: [][]string{{"Overall"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			packed := packMessages(tt.prepared)
+
+			gotEmbeds := []int{}
+			gotActivities := [][]string{}
+			for _, message := range packed {
+				gotEmbeds = append(gotEmbeds, len(message.embeds))
+				gotActivities = append(gotActivities, message.activities)
+			}
+
+			if !slices.Equal(gotEmbeds, tt.wantEmbeds) {
+				t.Errorf("embeds per message = %v, want %v", gotEmbeds, tt.wantEmbeds)
+			}
+			if !slices.EqualFunc(gotActivities, tt.wantActivities, slices.Equal) {
+				t.Errorf("activities per message = %v, want %v", gotActivities, tt.wantActivities)
+			}
+
+			// Embeds stay in order
+			var all, want []*discordgo.MessageEmbed
+			for _, message := range packed {
+				all = append(all, message.embeds...)
+			}
+			for _, a := range tt.prepared {
+				want = append(want, a.embeds...)
+			}
+			if !slices.Equal(all, want) {
+				t.Error("embeds were reordered")
+			}
+		})
+	}
+}
+
+// trackedMessages returns the activities we've recorded for each message of a board
+func trackedMessages(t *testing.T, board model.Boards) map[string][]string {
+	t.Helper()
+
+	messages, err := store.FetchAllMessages(board.ServerID, board.BoardName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tracked := map[string][]string{}
+	for _, m := range messages {
+		if m.MessageID != "" {
+			tracked[m.MessageID] = append(tracked[m.MessageID], m.Activity)
+		}
+	}
+	for _, activities := range tracked {
+		slices.Sort(activities)
+	}
+
+	return tracked
+}
+
+// fullMessage is an activity with enough embeds to fill a message by itself
+func fullMessage(activity string) activityEmbeds {
+	a := activityEmbeds{activity: activity}
+	for range maxEmbedsPerMessage {
+		a.embeds = append(a.embeds, &discordgo.MessageEmbed{Title: activity})
+	}
+	return a
+}
+
+func TestPublishBoardEditsItsMessages(t *testing.T) {
+	newTestStore(t)
+	s, fake := newFakeDiscord(t)
+
+	server := enrollTestServer(t, []string{"11"}, []string{"Overall", "Zulrah", "Vorkath"}, 0)
+	board, err := store.FetchBoard(server.ID, "board0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	prepared := []activityEmbeds{fullMessage("Overall"), fullMessage("Zulrah"), fullMessage("Vorkath")}
+
+	err = publishBoard(s, server, board, "11", prepared)
+	if err != nil {
+		t.Fatal(err)
+	}
+	posted := fake.postedTo("11")
+	if len(posted) != 3 {
+		t.Fatalf("posted %d messages, want 3", len(posted))
+	}
+	want := map[string][]string{posted[0]: {"Overall"}, posted[1]: {"Zulrah"}, posted[2]: {"Vorkath"}}
+
+	// Posting again edits the same messages in place
+	err = publishBoard(s, server, board, "11", prepared)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.postedTo("11"); len(got) != 3 {
+		t.Errorf("posted %d messages instead of editing, want 3", len(got))
+	}
+	if edited := fake.editedIn("11"); !slices.Equal(edited, posted) {
+		t.Errorf("edited %v, want %v", edited, posted)
+	}
+	if tracked := trackedMessages(t, board); !maps.EqualFunc(tracked, want, slices.Equal) {
+		t.Errorf("tracked %v, want %v", tracked, want)
+	}
+
+	// A board that shrinks deletes the messages it no longer needs
+	err = publishBoard(s, server, board, "11", prepared[:2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted := fake.deletedFrom("11"); !slices.Equal(deleted, posted[2:]) {
+		t.Errorf("deleted %v, want %v", deleted, posted[2:])
+	}
+	delete(want, posted[2])
+	if tracked := trackedMessages(t, board); !maps.EqualFunc(tracked, want, slices.Equal) {
+		t.Errorf("tracked %v, want %v", tracked, want)
+	}
+}
+
+func TestPublishBoardRecoversFromDeletedMessage(t *testing.T) {
+	newTestStore(t)
+	s, fake := newFakeDiscord(t)
+
+	server := enrollTestServer(t, []string{"11"}, []string{"Overall", "Zulrah", "Vorkath"}, 0)
+	board, err := store.FetchBoard(server.ID, "board0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	prepared := []activityEmbeds{fullMessage("Overall"), fullMessage("Zulrah"), fullMessage("Vorkath")}
+
+	err = publishBoard(s, server, board, "11", prepared)
+	if err != nil {
+		t.Fatal(err)
+	}
+	posted := fake.postedTo("11")
+
+	// Someone deletes the middle message by hand
+	fake.deleteByHand(posted[1])
+
+	err = publishBoard(s, server, board, "11", prepared)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first message is still edited in place
+	if edited := fake.editedIn("11"); !slices.Equal(edited, posted[:1]) {
+		t.Errorf("edited %v, want %v", edited, posted[:1])
+	}
+
+	// Everything after the deleted message is posted again below it
+	if deleted := fake.deletedFrom("11"); !slices.Equal(deleted, posted[2:]) {
+		t.Errorf("deleted %v, want %v", deleted, posted[2:])
+	}
+	reposted := fake.postedTo("11")[3:]
+	if len(reposted) != 2 {
+		t.Fatalf("reposted %d messages, want 2", len(reposted))
+	}
+
+	want := map[string][]string{posted[0]: {"Overall"}, reposted[0]: {"Zulrah"}, reposted[1]: {"Vorkath"}}
+	if tracked := trackedMessages(t, board); !maps.EqualFunc(tracked, want, slices.Equal) {
+		t.Errorf("tracked %v, want %v", tracked, want)
+	}
+}
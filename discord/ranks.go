@@ -0,0 +1,249 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/michohl/osrs-clan-leaderboard/hiscores"
+	"github.com/michohl/osrs-clan-leaderboard/schedule"
+	"github.com/michohl/osrs-clan-leaderboard/types"
+	"github.com/michohl/osrs-clan-leaderboard/utils"
+
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+)
+
+// rankReportTitle is the title of the rank report embed
+const rankReportTitle = "Promotions Due"
+
+// rankResult is the rank a single OSRS user deserves compared
+// to the rank they currently hold in game
+type rankResult struct {
+	User         model.Users
+	DeservedRank string
+	DuePromotion bool
+}
+
+// calculateRanks fetches fresh hiscores for every user in a server and
+// works out the highest rank on the ladder each user meets the
+// requirements for. Users we couldn't fetch are left out
+func calculateRanks(server model.Servers) ([]rankResult, error) {
+	ranks, err := store.FetchRanks(server.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ranks) == 0 {
+		return nil, fmt.Errorf("No ranks are configured for server %s", server.ServerName)
+	}
+
+	// Keep track of where each rank sits so we can compare
+	// the rank a user holds with the one they deserve
+	rankPositions := map[string]int32{}
+	rankRequirements := map[string][]types.RankRequirement{}
+	for _, rank := range ranks {
+		rankPositions[rank.RankName] = rank.Position
+
+		requirements, err := hiscores.ParseRankRequirements(rank.Requirements)
+		if err != nil {
+			return nil, err
+		}
+		rankRequirements[rank.RankName] = requirements
+	}
+
+	allUsers, err := store.FetchAllUsers(server.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	userHiscores, _, err := hiscores.GetUserHiscores(allUsers, "")
+	if err != nil {
+		return nil, err
+	}
+
+	results := []rankResult{}
+	for user, hs := range userHiscores {
+		result := rankResult{User: user}
+
+		// Ranks are sorted from highest to lowest so the first match is the best one
+		for _, rank := range ranks {
+			if hiscores.MeetsRankRequirements(hs, rankRequirements[rank.RankName]) {
+				result.DeservedRank = rank.RankName
+				break
+			}
+		}
+
+		currentPosition, hasRank := rankPositions[user.ClanRank]
+		if result.DeservedRank != "" {
+			result.DuePromotion = !hasRank || rankPositions[result.DeservedRank] > currentPosition
+		}
+
+		results = append(results, result)
+	}
+
+	slices.SortFunc(results, func(a, b rankResult) int {
+		return strings.Compare(strings.ToLower(a.User.OsrsUsername), strings.ToLower(b.User.OsrsUsername))
+	})
+
+	return results, nil
+}
+
+// formatPromotion renders a single line of a promotion report
+func formatPromotion(result rankResult) string {
+	currentRank := result.User.ClanRank
+	if currentRank == "" {
+		currentRank = "No rank"
+	}
+
+	line := fmt.Sprintf("* **%s** %s → %s", result.User.OsrsUsername, currentRank, result.DeservedRank)
+	if result.User.DiscordUserID != "" {
+		line += fmt.Sprintf(" (<@%s>)", result.User.DiscordUserID)
+	}
+
+	return line
+}
+
+// rankReportNoticeKey groups the notices about posting the rank report
+const rankReportNoticeKey = "rank-report"
+
+// rankReportBoard returns the board whose channel rank reports are posted
+// to. That's the default board or the first board if it was deleted
+func rankReportBoard(serverID string) (model.Boards, error) {
+	boards, err := store.FetchBoards(serverID)
+	if err != nil {
+		return model.Boards{}, err
+	}
+
+	if len(boards) == 0 {
+		return model.Boards{}, fmt.Errorf("Server %s has no boards configured", serverID)
+	}
+
+	for _, board := range boards {
+		if board.BoardName == types.DefaultBoardName {
+			return board, nil
+		}
+	}
+
+	return boards[0], nil
+}
+
+// PostRankReport posts the list of users who are due a promotion to the
+// server's hiscores channel. Nothing is posted if nobody is due one
+func PostRankReport(serverID string, s *discordgo.Session) error {
+	server, err := store.FetchServer(serverID)
+	if err != nil {
+		return err
+	}
+
+	results, err := calculateRanks(server)
+	if err != nil {
+		return err
+	}
+
+	description := ""
+	for _, result := range results {
+		if !result.DuePromotion {
+			continue
+		}
+
+		line := formatPromotion(result) + "\n"
+
+		// Embed descriptions are limited to 4096 characters
+		if len(description)+len(line) > 4000 {
+			description += "..."
+			break
+		}
+		description += line
+	}
+
+	if description == "" {
+		log.Printf("Nobody is due a promotion in server %s", server.ServerName)
+		return nil
+	}
+
+	board, err := rankReportBoard(server.ID)
+	if err != nil {
+		return err
+	}
+
+	channel, err := boardChannel(s, board)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.ChannelMessageSendEmbed(channel.ID, &discordgo.MessageEmbed{
+		Title:       rankReportTitle,
+		Description: description,
+	})
+
+	return err
+}
+
+// EnableRankReportCronjob schedules a server's rank promotion report if it has one configured
+func EnableRankReportCronjob(server model.Servers, s *discordgo.Session) error {
+	if server.RankReportSchedule == "" {
+		return nil
+	}
+
+	jobID, err := schedule.Cron.AddFunc(schedule.WithTimezone(server.RankReportSchedule, server.Timezone), func() {
+		err := PostRankReport(server.ID, s)
+		if err != nil {
+			log.Printf("Unable to post rank report for server %s because %s\n", server.ServerName, err)
+			notifyRankReportFailure(s, server.ID, err)
+			return
+		}
+		forgetAdminNotice(server.ID, rankReportNoticeKey)
+	})
+	if err != nil {
+		log.Printf("Unable to schedule rank report for server %s because %s\n", server.ServerName, err)
+		return err
+	}
+
+	log.Printf("Rank report successfully scheduled for server %s. Job ID %d\n", server.ServerName, jobID)
+	schedule.SetRankReportJob(server.ID, types.CronSchedule{
+		JobID:          jobID,
+		DiscordSession: s,
+	})
+
+	return nil
+}
+
+// notifyRankReportFailure tells the server's admins that the rank report couldn't be posted
+func notifyRankReportFailure(s *discordgo.Session, serverID string, problem error) {
+	// boardChannel has already told the admins about problems with the channel
+	if errors.Is(problem, utils.ErrChannelNotFound) || errors.Is(problem, utils.ErrChannelAmbiguous) {
+		return
+	}
+
+	server, err := store.FetchServer(serverID)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	permissionsHint := "Make sure the bot can View Channel, Send Messages and Embed Links in the leaderboard channel."
+	board, err := rankReportBoard(serverID)
+	if err == nil {
+		permissionsHint = fmt.Sprintf("Make sure the bot can View Channel, Send Messages and Embed Links in %s.", channelMention(board))
+	}
+
+	notifyAdmins(s, server, adminNotice{
+		key:     rankReportNoticeKey,
+		title:   "Unable to post the rank report",
+		problem: problem.Error(),
+		hint:    remediationHint(problem, permissionsHint),
+	})
+}
+
+// DisableRankReportCronjob removes a server's rank promotion report job if there is one
+func DisableRankReportCronjob(server model.Servers) {
+	job, ok := schedule.RemoveRankReportJob(server.ID)
+	if !ok {
+		return
+	}
+
+	log.Printf("Rank report removed for server %s. Job ID %d\n", server.ServerName, job.JobID)
+}
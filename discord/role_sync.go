@@ -0,0 +1,155 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/michohl/osrs-clan-leaderboard/types"
+
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+)
+
+// roleChange is a single role we want to add to
+// or remove from a member of the server
+type roleChange struct {
+	DiscordUserID string
+	RoleID        string
+	Add           bool
+}
+
+// String renders the change the way we show it to admins in /roles preview
+func (c roleChange) String() string {
+	if c.Add {
+		return fmt.Sprintf("+ <@&%s> to <@%s>", c.RoleID, c.DiscordUserID)
+	}
+
+	return fmt.Sprintf("- <@&%s> from <@%s>", c.RoleID, c.DiscordUserID)
+}
+
+// qualifiesForRole checks if a set of hiscores meets every rule configured
+// for a role. Rules for the same role are combined so a role with
+// "Attack >= 99" and "Strength >= 99" needs both to be met. known is false
+// when we can't tell because a rule's activity isn't in the hiscores at all
+func qualifiesForRole(hs types.Hiscores, rules []model.RoleRules) (qualifies bool, known bool) {
+	known = true
+	for _, rule := range rules {
+		value, ok := hs.GetLevelOrScore(rule.Activity)
+		if !ok {
+			known = false
+			continue
+		}
+
+		if value < int(rule.Threshold) {
+			return false, true
+		}
+	}
+
+	return known, known
+}
+
+// planRoleChanges compares the roles every linked member currently has with
+// the roles their hiscores say they should have and returns the difference.
+// Members whose hiscores couldn't be fetched are left alone so an API
+// outage doesn't strip everyone's roles
+func planRoleChanges(s *discordgo.Session, server model.Servers, userHiscores map[model.Users]types.Hiscores) ([]roleChange, error) {
+	rules, err := store.FetchRoleRules(server.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	rulesByRole := map[string][]model.RoleRules{}
+	for _, rule := range rules {
+		rulesByRole[rule.RoleID] = append(rulesByRole[rule.RoleID], rule)
+	}
+
+	// A member qualifies for a role if any of their linked accounts do
+	qualifiedRoles := map[string]map[string]bool{}
+	unknownRoles := map[string]map[string]bool{}
+	for user, hs := range userHiscores {
+		if user.DiscordUserID == "" {
+			continue
+		}
+
+		if _, ok := qualifiedRoles[user.DiscordUserID]; !ok {
+			qualifiedRoles[user.DiscordUserID] = map[string]bool{}
+			unknownRoles[user.DiscordUserID] = map[string]bool{}
+		}
+
+		for roleID, roleRules := range rulesByRole {
+			qualifies, known := qualifiesForRole(hs, roleRules)
+			if qualifies {
+				qualifiedRoles[user.DiscordUserID][roleID] = true
+			}
+			if !known {
+				unknownRoles[user.DiscordUserID][roleID] = true
+			}
+		}
+	}
+
+	changes := []roleChange{}
+	for _, discordUserID := range slices.Sorted(maps.Keys(qualifiedRoles)) {
+		member, err := s.GuildMember(server.ID, discordUserID)
+		if err != nil {
+			log.Printf("Unable to look up member %s in server %s: %s", discordUserID, server.ServerName, err)
+			continue
+		}
+
+		for _, roleID := range slices.Sorted(maps.Keys(rulesByRole)) {
+			hasRole := slices.Contains(member.Roles, roleID)
+			shouldHaveRole := qualifiedRoles[discordUserID][roleID]
+
+			// Only take a role away when we know the member no longer qualifies
+			if hasRole && !shouldHaveRole && unknownRoles[discordUserID][roleID] {
+				continue
+			}
+
+			if hasRole != shouldHaveRole {
+				changes = append(changes, roleChange{
+					DiscordUserID: discordUserID,
+					RoleID:        roleID,
+					Add:           shouldHaveRole,
+				})
+			}
+		}
+	}
+
+	return changes, nil
+}
+
+// SyncRoles adds and removes roles for every linked member of a server
+// based on the server's role rules and the hiscores we just fetched.
+// Changes that fail don't stop the rest and are returned together
+func SyncRoles(s *discordgo.Session, server model.Servers, userHiscores map[model.Users]types.Hiscores) error {
+	changes, err := planRoleChanges(s, server, userHiscores)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, change := range changes {
+		log.Printf("Applying role change in server %s: %s", server.ServerName, change)
+
+		if change.Add {
+			err = s.GuildMemberRoleAdd(server.ID, change.DiscordUserID, change.RoleID)
+		} else {
+			err = s.GuildMemberRoleRemove(server.ID, change.DiscordUserID, change.RoleID)
+		}
+
+		// Usually this means the role is above the bot's highest role. We
+		// keep going so one bad role doesn't block every other change
+		if err != nil {
+			log.Printf("Unable to apply role change in server %s: %s", server.ServerName, err)
+			errs = append(errs, fmt.Errorf("%s: %w", change, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
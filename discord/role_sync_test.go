@@ -0,0 +1,34 @@
+package discord
+
+import (
+	"testing"
+
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+	"github.com/michohl/osrs-clan-leaderboard/types"
+)
+
+func TestQualifiesForRole(t *testing.T) {
+	hs := types.Hiscores{
+		Skills:     []types.SkillHiscore{{Name: "Attack", Level: 99}, {Name: "Strength", Level: 80}},
+		Activities: []types.ActivityHiscore{{Name: "Zulrah", Score: 50}},
+	}
+
+	tests := []struct {
+		name      string
+		rules     []model.RoleRules
+		qualifies bool
+		known     bool
+	}{
+		{"met", []model.RoleRules{{Activity: "Attack", Threshold: 99}, {Activity: "zulrah", Threshold: 50}}, true, true},
+		{"not met", []model.RoleRules{{Activity: "Attack", Threshold: 99}, {Activity: "Strength", Threshold: 99}}, false, true},
+		{"unknown activity", []model.RoleRules{{Activity: "Attack", Threshold: 99}, {Activity: "Zulrah (Leagues)", Threshold: 1}}, false, false},
+		{"not met with an unknown activity", []model.RoleRules{{Activity: "Zulrah (Leagues)", Threshold: 1}, {Activity: "Strength", Threshold: 99}}, false, true},
+	}
+
+	for _, test := range tests {
+		qualifies, known := qualifiesForRole(hs, test.rules)
+		if qualifies != test.qualifies || known != test.known {
+			t.Errorf("%s: got qualifies=%t known=%t, want qualifies=%t known=%t", test.name, qualifies, known, test.qualifies, test.known)
+		}
+	}
+}
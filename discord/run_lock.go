@@ -0,0 +1,86 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+	"github.com/michohl/osrs-clan-leaderboard/schedule"
+)
+
+// ErrRunInProgress is returned when a board is already being posted
+var ErrRunInProgress = errors.New("a run is already in progress")
+
+// runLockTTL is how long a run can hold a board's lock in the database.
+// It only matters if a bot dies part way through a run since the lock is
+// released as soon as a run finishes
+const runLockTTL = 30 * time.Minute
+
+// runLockOwner tells the locks of this process apart from those
+// of any other copy of the bot sharing the same database
+var runLockOwner = fmt.Sprintf("%s-%d-%d", hostname(), os.Getpid(), time.Now().UnixNano())
+
+// runningBoards are the boards this process is currently posting.
+// Checking it first saves a trip to the database in the common case
+var runningBoards = struct {
+	sync.Mutex
+	keys map[string]bool
+}{keys: map[string]bool{}}
+
+// lockBoardRun makes sure nobody else posts a board until the returned
+// release function is called. If the board is already being posted
+// (by us or another copy of the bot) it returns ErrRunInProgress
+func lockBoardRun(serverID string, boardName string) (func(), error) {
+	key := schedule.BoardJobKey(serverID, boardName)
+
+	runningBoards.Lock()
+	if runningBoards.keys[key] {
+		runningBoards.Unlock()
+		return nil, ErrRunInProgress
+	}
+	runningBoards.keys[key] = true
+	runningBoards.Unlock()
+
+	releaseLocal := func() {
+		runningBoards.Lock()
+		delete(runningBoards.keys, key)
+		runningBoards.Unlock()
+	}
+
+	now := time.Now().UTC()
+	acquired, err := store.AcquireRunLock(model.RunLocks{
+		ServerID:   serverID,
+		BoardName:  boardName,
+		Owner:      runLockOwner,
+		AcquiredAt: now,
+		ExpiresAt:  now.Add(runLockTTL),
+	})
+	if err != nil || !acquired {
+		releaseLocal()
+		if err != nil {
+			return nil, err
+		}
+		return nil, ErrRunInProgress
+	}
+
+	return func() {
+		err := store.ReleaseRunLock(serverID, boardName, runLockOwner)
+		if err != nil {
+			log.Printf("Unable to release run lock of board %s in server %s: %s", boardName, serverID, err)
+		}
+		releaseLocal()
+	}, nil
+}
+
+func hostname() string {
+	name, err := os.Hostname()
+	if err != nil {
+		return "unknown"
+	}
+
+	return name
+}
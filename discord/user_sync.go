@@ -0,0 +1,145 @@
+package discord
+
+import (
+	"errors"
+	"log"
+	"os"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/michohl/osrs-clan-leaderboard/hiscores"
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+	"github.com/michohl/osrs-clan-leaderboard/schedule"
+	"github.com/michohl/osrs-clan-leaderboard/utils"
+)
+
+var (
+	// UserSyncSchedule is the cron expression for how often we refresh
+	// Discord usernames and check for renamed RSNs across all servers
+	UserSyncSchedule = os.Getenv("USER_SYNC_SCHEDULE")
+)
+
+// EnableUserSyncCronjob schedules the periodic user sync for every enrolled server
+func EnableUserSyncCronjob(s *discordgo.Session) error {
+	if UserSyncSchedule == "" {
+		UserSyncSchedule = "@daily"
+	}
+
+	jobID, err := schedule.Cron.AddFunc(UserSyncSchedule, func() {
+		SyncAllServers(s)
+	})
+	if err != nil {
+		log.Printf("Unable to schedule user sync because %s\n", err)
+		return err
+	}
+
+	log.Printf("User sync successfully scheduled. Job ID %d\n", jobID)
+
+	return nil
+}
+
+// SyncAllServers runs SyncServerUsers for every enabled server
+func SyncAllServers(s *discordgo.Session) {
+	allServers, err := store.FetchAllServers()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	for _, server := range allServers {
+		if !server.IsEnabled {
+			continue
+		}
+
+		SyncServerUsers(s, server)
+	}
+}
+
+// SyncServerUsers refreshes the Discord usernames of every linked member
+// and flags any RSNs that can no longer be found on the hiscores so they
+// can be fixed with /rename
+func SyncServerUsers(s *discordgo.Session, server model.Servers) {
+	log.Printf("Syncing users for server %s", server.ServerName)
+
+	allUsers, err := store.FetchAllUsers(server.ID)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	// Several RSNs can be linked to the same member so we
+	// only need to look each member up once
+	checkedMembers := map[string]bool{}
+
+	for _, user := range allUsers {
+		if user.DiscordUserID != "" && !checkedMembers[user.DiscordUserID] {
+			checkedMembers[user.DiscordUserID] = true
+			syncDiscordMember(s, server, user)
+		}
+
+		_, err := hiscores.GetPlayerHiscores(user)
+		switch {
+		case errors.Is(err, hiscores.ErrPlayerNotFound) && !user.PossiblyRenamed:
+			log.Printf("OSRS user %s in server %s may have been renamed", user.OsrsUsername, server.ServerName)
+			err = store.SetUserPossiblyRenamed(user, true)
+		case err == nil && user.PossiblyRenamed:
+			log.Printf("OSRS user %s in server %s is back on the hiscores", user.OsrsUsername, server.ServerName)
+			err = store.SetUserPossiblyRenamed(user, false)
+		case err != nil:
+			log.Printf("Unable to check hiscores for OSRS user %s: %s", user.OsrsUsername, err)
+			err = nil
+		}
+		if err != nil {
+			log.Println(err)
+		}
+	}
+
+	if server.NicknameSyncEnabled {
+		_, err = SyncNicknames(s, server)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// syncDiscordMember looks up the Discord member linked to a user and
+// either refreshes their username or, if they left while we were
+// offline, applies the server's member leave action
+func syncDiscordMember(s *discordgo.Session, server model.Servers, user model.Users) {
+	member, err := s.GuildMember(server.ID, user.DiscordUserID)
+	if err != nil {
+		if utils.IsDiscordErrorCode(err, discordgo.ErrCodeUnknownMember) {
+			err = applyMemberLeaveAction(server, user.DiscordUserID)
+		}
+		if err != nil {
+			log.Println(err)
+		}
+		return
+	}
+
+	if member.User.Username == user.DiscordUsername {
+		return
+	}
+
+	err = store.UpdateDiscordUsername(server.ID, user.DiscordUserID, member.User.Username)
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+// GuildMemberUpdateHandler keeps stored Discord usernames up to
+// date as soon as Discord tells us a member has changed
+func GuildMemberUpdateHandler(s *discordgo.Session, m *discordgo.GuildMemberUpdate) {
+	if m.BeforeUpdate != nil && m.BeforeUpdate.User != nil && m.BeforeUpdate.User.Username == m.User.Username {
+		return
+	}
+
+	server, err := store.FetchServer(m.GuildID)
+	if err != nil || !server.IsEnabled {
+		return
+	}
+
+	err = store.UpdateDiscordUsername(server.ID, m.User.ID, m.User.Username)
+	if err != nil {
+		log.Println(err)
+	}
+}
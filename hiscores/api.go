@@ -2,12 +2,14 @@ package hiscores
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/avast/retry-go/v5"
@@ -15,6 +17,10 @@ import (
 	"github.com/michohl/osrs-clan-leaderboard/types"
 )
 
+// ErrPlayerNotFound is returned when the hiscores API has no record of a player.
+// This usually means the player changed their RSN (or has never been ranked)
+var ErrPlayerNotFound = errors.New("player not found on hiscores")
+
 // HiscoreModes is the `m=` parameter on our API URI we reach out to to get Hiscores from Jagex
 var HiscoreModes = map[string]string{
 	"hardcore_ironman": "hiscore_oldschool_hardcore_ironman",
@@ -66,6 +72,13 @@ func queryAPI(user model.Users) (types.Hiscores, error) {
 	}
 	defer resp.Body.Close()
 
+	// There's no point retrying a player that doesn't exist
+	if resp.StatusCode == http.StatusNotFound {
+		return types.Hiscores{}, retry.Unrecoverable(
+			fmt.Errorf("%w: %s on %s leaderboard", ErrPlayerNotFound, user.OsrsUsername, user.OsrsAccountType),
+		)
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		log.Println("Unable to read body from HTTP response")
@@ -86,9 +99,17 @@ func queryAPI(user model.Users) (types.Hiscores, error) {
 // API endpoint that returns the hiscores for one specific user.
 // Documentation: https://runescape.wiki/w/Application_programming_interface#Old_School_Hiscores
 func GetPlayerHiscores(user model.Users) (types.Hiscores, error) {
+	// Don't add to the load while the hiscores look to be down
+	err := breaker.allow()
+	if err != nil {
+		return types.Hiscores{}, err
+	}
+
+	var lastErr error
 	hiscores, err := retry.NewWithData[types.Hiscores](retry.Attempts(5), retry.Delay(100*time.Millisecond)).Do(
 		func() (types.Hiscores, error) {
 			hiscores, err := queryAPI(user)
+			lastErr = err
 			if err != nil {
 				return types.Hiscores{}, err
 			}
@@ -96,6 +117,9 @@ func GetPlayerHiscores(user model.Users) (types.Hiscores, error) {
 		},
 	)
 
+	// A request counts once towards the breaker however many attempts it took
+	breaker.record(lastErr)
+
 	if err != nil {
 		return types.Hiscores{}, err
 	}
@@ -103,11 +127,34 @@ func GetPlayerHiscores(user model.Users) (types.Hiscores, error) {
 	return hiscores, nil
 }
 
+// Ping checks that the hiscores API answers and returns how long it took
+func Ping() (time.Duration, error) {
+	start := time.Now()
+	_, err := GetPlayerHiscores(model.Users{OsrsUsername: "sample", OsrsAccountType: "main"})
+
+	return time.Since(start), err
+}
+
+// knownNames are the skills and activities the hiscores API returned the last
+// time we asked. They rarely change so we fall back to them when the API is down
+var knownNames = struct {
+	sync.Mutex
+	skills     []string
+	activities []string
+}{}
+
 // GetAllSkills will return all the valid skill options that the API
 // can possibly return
 func GetAllSkills() ([]string, error) {
 	hs, err := GetPlayerHiscores(model.Users{OsrsUsername: "sample", OsrsAccountType: "main"})
+
+	knownNames.Lock()
+	defer knownNames.Unlock()
+
 	if err != nil {
+		if knownNames.skills != nil {
+			return knownNames.skills, nil
+		}
 		return []string{}, err
 	}
 
@@ -115,6 +162,7 @@ func GetAllSkills() ([]string, error) {
 	for _, skill := range hs.Skills {
 		discoveredSkills = append(discoveredSkills, skill.Name)
 	}
+	knownNames.skills = discoveredSkills
 
 	return discoveredSkills, nil
 }
@@ -123,7 +171,14 @@ func GetAllSkills() ([]string, error) {
 // can possibly return
 func GetAllActivities() ([]string, error) {
 	hs, err := GetPlayerHiscores(model.Users{OsrsUsername: "sample", OsrsAccountType: "main"})
+
+	knownNames.Lock()
+	defer knownNames.Unlock()
+
 	if err != nil {
+		if knownNames.activities != nil {
+			return knownNames.activities, nil
+		}
 		return []string{}, err
 	}
 
@@ -131,6 +186,7 @@ func GetAllActivities() ([]string, error) {
 	for _, skill := range hs.Activities {
 		discoveredActivities = append(discoveredActivities, skill.Name)
 	}
+	knownNames.activities = discoveredActivities
 
 	return discoveredActivities, nil
 }
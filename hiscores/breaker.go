@@ -0,0 +1,145 @@
+package hiscores
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned instead of calling the hiscores API while it looks to be down
+var ErrCircuitOpen = errors.New("the hiscores are unavailable")
+
+const (
+	// breakerThreshold is how many requests in a row have to fail
+	// before we stop calling the hiscores API
+	breakerThreshold = 10
+
+	// breakerCooldown is how long we stop calling the hiscores API for
+	breakerCooldown = 5 * time.Minute
+)
+
+const (
+	// CircuitClosed means requests go through as usual
+	CircuitClosed = "closed"
+
+	// CircuitOpen means requests fail straight away without calling the API
+	CircuitOpen = "open"
+
+	// CircuitHalfOpen means the cool down is over and a single request goes
+	// through to check whether the API is back. A failure stops requests for
+	// another cool down
+	CircuitHalfOpen = "half-open"
+)
+
+// CircuitStatus is a snapshot of the circuit breaker in front of the hiscores API
+type CircuitStatus struct {
+	State               string
+	ConsecutiveFailures int
+	OpenedAt            time.Time
+	OpenUntil           time.Time
+	LastError           string
+}
+
+// circuitBreaker stops every server's jobs from hammering the hiscores
+// API (and waiting on retries) while Jagex has it down for maintenance
+type circuitBreaker struct {
+	mu                  sync.Mutex
+	consecutiveFailures int
+	openedAt            time.Time
+	openUntil           time.Time
+	lastErr             error
+	// probing is set while the request checking whether the API is back is in flight
+	probing bool
+}
+
+// breaker is shared by every request to the hiscores API
+var breaker = &circuitBreaker{}
+
+// allow returns ErrCircuitOpen if we shouldn't call the API right now
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if time.Now().Before(b.openUntil) {
+		return fmt.Errorf("%w until %s: %w", ErrCircuitOpen, b.openUntil.UTC().Format(time.TimeOnly+" MST"), b.lastErr)
+	}
+
+	// Once the cool down is over only one request checks whether the API is back
+	if !b.openUntil.IsZero() {
+		if b.probing {
+			return fmt.Errorf("%w while we check whether they're back: %w", ErrCircuitOpen, b.lastErr)
+		}
+		b.probing = true
+	}
+
+	return nil
+}
+
+// record keeps track of how a request to the API went. A player that doesn't
+// exist is a perfectly good answer so only problems with the API itself count
+func (b *circuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+
+	if err == nil || errors.Is(err, ErrPlayerNotFound) {
+		if !b.openUntil.IsZero() {
+			log.Println("Hiscores are available again. Resuming requests")
+		}
+		b.consecutiveFailures = 0
+		b.openedAt = time.Time{}
+		b.openUntil = time.Time{}
+		return
+	}
+
+	b.consecutiveFailures++
+	b.lastErr = err
+
+	// A failure once the cool down is over means the API is still down
+	stillDown := !b.openUntil.IsZero() && !time.Now().Before(b.openUntil)
+	if b.consecutiveFailures == breakerThreshold || stillDown {
+		if b.openedAt.IsZero() {
+			b.openedAt = time.Now()
+		}
+		b.openUntil = time.Now().Add(breakerCooldown)
+		log.Printf("Hiscores failed %d times in a row. Pausing requests until %s: %s\n", b.consecutiveFailures, b.openUntil, err)
+	}
+}
+
+// status describes the breaker in its current state
+func (b *circuitBreaker) status() CircuitStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := CircuitStatus{
+		State:               CircuitClosed,
+		ConsecutiveFailures: b.consecutiveFailures,
+		OpenedAt:            b.openedAt,
+		OpenUntil:           b.openUntil,
+	}
+	if b.lastErr != nil {
+		status.LastError = b.lastErr.Error()
+	}
+
+	switch {
+	case time.Now().Before(b.openUntil):
+		status.State = CircuitOpen
+	case !b.openUntil.IsZero():
+		status.State = CircuitHalfOpen
+	}
+
+	return status
+}
+
+// GetCircuitStatus reports whether we're currently calling the hiscores API
+func GetCircuitStatus() CircuitStatus {
+	return breaker.status()
+}
+
+// IsCircuitOpen checks if requests to the hiscores API are currently paused
+func IsCircuitOpen() bool {
+	return breaker.status().State == CircuitOpen
+}
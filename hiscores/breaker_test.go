@@ -0,0 +1,113 @@
+package hiscores
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	errDown := errors.New("503 Service Unavailable")
+
+	// Each step either asks to make a request, records how a request went
+	// or lets the cool down pass. state is what the breaker reports after it
+	type step struct {
+		allow      bool
+		record     error
+		success    bool
+		cooledDown bool
+		wantErr    error
+		state      string
+	}
+
+	failures := func(n int) []step {
+		var steps []step
+		for range n {
+			steps = append(steps, step{record: errDown, state: CircuitClosed})
+		}
+		return steps
+	}
+	opened := append(failures(breakerThreshold-1), step{record: errDown, state: CircuitOpen})
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name:  "failures below the threshold keep it closed",
+			steps: slices.Concat(failures(breakerThreshold-1), []step{{allow: true, state: CircuitClosed}}),
+		},
+		{
+			name: "a success resets the count",
+			steps: slices.Concat(failures(breakerThreshold-1), []step{{success: true, state: CircuitClosed}},
+				failures(breakerThreshold-1)),
+		},
+		{
+			name: "a missing player isn't a failure",
+			steps: slices.Concat(failures(breakerThreshold-1), []step{
+				{record: ErrPlayerNotFound, state: CircuitClosed},
+				{record: errDown, state: CircuitClosed},
+			}),
+		},
+		{
+			name:  "the threshold opens it",
+			steps: slices.Concat(opened, []step{{allow: true, wantErr: ErrCircuitOpen, state: CircuitOpen}}),
+		},
+		{
+			name: "half open lets a single probe through",
+			steps: slices.Concat(opened, []step{
+				{cooledDown: true, state: CircuitHalfOpen},
+				{allow: true, state: CircuitHalfOpen},
+				{allow: true, wantErr: ErrCircuitOpen, state: CircuitHalfOpen},
+			}),
+		},
+		{
+			name: "a successful probe closes it",
+			steps: slices.Concat(opened, []step{
+				{cooledDown: true, state: CircuitHalfOpen},
+				{allow: true, state: CircuitHalfOpen},
+				{success: true, state: CircuitClosed},
+				{allow: true, state: CircuitClosed},
+				{allow: true, state: CircuitClosed},
+			}),
+		},
+		{
+			name: "a failed probe opens it again",
+			steps: slices.Concat(opened, []step{
+				{cooledDown: true, state: CircuitHalfOpen},
+				{allow: true, state: CircuitHalfOpen},
+				{record: errDown, state: CircuitOpen},
+				{allow: true, wantErr: ErrCircuitOpen, state: CircuitOpen},
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &circuitBreaker{}
+
+			for i, s := range tt.steps {
+				switch {
+				case s.allow:
+					err := b.allow()
+					if !errors.Is(err, s.wantErr) {
+						t.Fatalf("step %d: allow() = %v, want %v", i, err, s.wantErr)
+					}
+				case s.success:
+					b.record(nil)
+				case s.cooledDown:
+					b.mu.Lock()
+					b.openUntil = time.Now().Add(-time.Second)
+					b.mu.Unlock()
+				default:
+					b.record(s.record)
+				}
+
+				if state := b.status().State; state != s.state {
+					t.Fatalf("step %d: state is %s, want %s", i, state, s.state)
+				}
+			}
+		})
+	}
+}
@@ -1,12 +1,14 @@
 package hiscores
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"slices"
 	"sort"
 	"strings"
 	"sync"
+	"unicode/utf8"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/michohl/osrs-clan-leaderboard/types"
@@ -14,13 +16,22 @@ import (
 	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
 )
 
-func getEmbedSize(embed *discordgo.MessageEmbed) int {
-	size := 0
-	for _, f := range embed.Fields {
-		size += len(f.Value)
+// ErrUnknownActivity is returned for names that aren't a skill or activity on the hiscores
+var ErrUnknownActivity = errors.New("not a known skill or activity")
+
+// MaxFieldLength is the most characters Discord allows in the value of an embed field
+const MaxFieldLength = 1024
+
+// fieldsWouldOverflow checks if appending a line to each field of an
+// embed would push any of them past Discord's field length limit
+func fieldsWouldOverflow(embed *discordgo.MessageEmbed, lines []string) bool {
+	for i, f := range embed.Fields {
+		if utf8.RuneCountInString(f.Value)+utf8.RuneCountInString(lines[i]) > MaxFieldLength {
+			return true
+		}
 	}
 
-	return size
+	return false
 }
 
 func IsSeasonal(activity string) bool {
@@ -35,6 +46,15 @@ func IsSeasonal(activity string) bool {
 	return false
 }
 
+// usernameFieldName names the first column of every leaderboard embed
+const usernameFieldName = "Username"
+
+// IsLeaderboardEmbed checks if an embed is a leaderboard made by FormatEmbeds.
+// It goes by the columns so leaderboards posted by older versions are recognised too
+func IsLeaderboardEmbed(embed *discordgo.MessageEmbed) bool {
+	return len(embed.Fields) > 0 && embed.Fields[0].Name == usernameFieldName && embed.Fields[0].Inline
+}
+
 // FormatEmbeds takes an activity and user hiscores and formats that information into our final
 // set of embeds that we'll pass back to discord to present to the user in the message
 func FormatEmbeds(activity string, userHiscores map[model.Users]types.Hiscores, removeUnrankedUsers bool, removeRank bool) ([]*discordgo.MessageEmbed, error) {
@@ -79,9 +99,9 @@ func FormatEmbeds(activity string, userHiscores map[model.Users]types.Hiscores,
 		{
 			Title: fmt.Sprintf("%s %s", emoji, activity),
 			Fields: []*discordgo.MessageEmbedField{
-				{Name: "Username", Value: "", Inline: true},       // User field
-				{Name: quantifierHeader, Value: "", Inline: true}, // Quantifier field
-				{Name: "Rank", Value: "", Inline: true},           // Rank field
+				{Name: usernameFieldName, Value: "", Inline: true}, // User field
+				{Name: quantifierHeader, Value: "", Inline: true},  // Quantifier field
+				{Name: "Rank", Value: "", Inline: true},            // Rank field
 			},
 		},
 	}
@@ -110,64 +130,57 @@ func FormatEmbeds(activity string, userHiscores map[model.Users]types.Hiscores,
 
 	for _, rankedUser := range sortedUserHiscores.Rankings {
 
-		// If we get close to the character limit (1024) then we should split
-		// the embed into multiple messages
-		if getEmbedSize(currentEmbed) > 850 {
-			messageEmbeds = append(messageEmbeds, &discordgo.MessageEmbed{
-				Title: fmt.Sprintf("%s %s", emoji, activity),
-				Fields: []*discordgo.MessageEmbedField{
-					{Name: "Username", Value: "", Inline: true},       // User field
-					{Name: quantifierHeader, Value: "", Inline: true}, // Quantifier field
-					{Name: "Rank", Value: "", Inline: true},           // Rank field
-				},
-			},
-			)
-
-			currentEmbedIndex = len(messageEmbeds) - 1
-			currentEmbed = messageEmbeds[currentEmbedIndex]
-
-			userField = currentEmbed.Fields[0]
-			quantifierField = currentEmbed.Fields[1]
-			rankField = currentEmbed.Fields[2]
-		}
-
-		userField.Value = fmt.Sprintf("%s\n", userField.Value)
+		userLine := "\n"
 
 		if len(sortedUserHiscores.Rankings) > 1 {
-			userField.Value += fmt.Sprintf(" %d -", rankedUser.LocalRank)
+			userLine += fmt.Sprintf(" %d -", rankedUser.LocalRank)
 		}
 
-		userField.Value += fmt.Sprintf(" %s", rankedUser.User.OsrsUsername)
+		userLine += fmt.Sprintf(" %s", rankedUser.User.OsrsUsername)
 
 		accountTypeEmoji, ok := types.ApplicationEmojis[rankedUser.User.OsrsAccountType]
 		if ok {
-			userField.Value += fmt.Sprintf(" <:%s>", accountTypeEmoji.APIName())
+			userLine += fmt.Sprintf(" <:%s>", accountTypeEmoji.APIName())
 		}
 
 		if rankedUser.User.DiscordUserID != "" {
-			userField.Value += fmt.Sprintf(" <@%s>", rankedUser.User.DiscordUserID)
+			userLine += fmt.Sprintf(" <@%s>", rankedUser.User.DiscordUserID)
 		}
 
+		var quantifierLine string
 		switch activityKind {
 		case "skill":
-			quantifierField.Value = fmt.Sprintf(
-				"%s\n%d",
-				quantifierField.Value,
-				rankedUser.Level,
-			)
+			quantifierLine = fmt.Sprintf("\n%d", rankedUser.Level)
 		case "activity":
-			quantifierField.Value = fmt.Sprintf(
-				"%s\n%d",
-				quantifierField.Value,
-				rankedUser.Score,
+			quantifierLine = fmt.Sprintf("\n%d", rankedUser.Score)
+		}
+
+		rankLine := fmt.Sprintf("\n%d", rankedUser.Rank)
+
+		// If any field would go over the character limit (1024) then
+		// we split the rest of the users into another embed
+		if fieldsWouldOverflow(currentEmbed, []string{userLine, quantifierLine, rankLine}) {
+			messageEmbeds = append(messageEmbeds, &discordgo.MessageEmbed{
+				Title: fmt.Sprintf("%s %s", emoji, activity),
+				Fields: []*discordgo.MessageEmbedField{
+					{Name: usernameFieldName, Value: "", Inline: true}, // User field
+					{Name: quantifierHeader, Value: "", Inline: true},  // Quantifier field
+					{Name: "Rank", Value: "", Inline: true},            // Rank field
+				},
+			},
 			)
+
+			currentEmbedIndex = len(messageEmbeds) - 1
+			currentEmbed = messageEmbeds[currentEmbedIndex]
+
+			userField = currentEmbed.Fields[0]
+			quantifierField = currentEmbed.Fields[1]
+			rankField = currentEmbed.Fields[2]
 		}
 
-		rankField.Value = fmt.Sprintf(
-			"%s\n%d",
-			rankField.Value,
-			rankedUser.Rank,
-		)
+		userField.Value += userLine
+		quantifierField.Value += quantifierLine
+		rankField.Value += rankLine
 	}
 
 	if removeRank {
@@ -179,10 +192,17 @@ func FormatEmbeds(activity string, userHiscores map[model.Users]types.Hiscores,
 	return messageEmbeds, nil
 }
 
+// FetchFailure is a user we couldn't fetch hiscores for and the reason why
+type FetchFailure struct {
+	User model.Users
+	Err  error
+}
+
 // GetUserHiscores takes a list of users and returns a map populated with all of the
-// hiscores for each user
-func GetUserHiscores(allUsers []model.Users, leaderboardOverride string) (map[model.Users]types.Hiscores, error) {
+// hiscores for each user. Users we couldn't fetch are returned separately
+func GetUserHiscores(allUsers []model.Users, leaderboardOverride string) (map[model.Users]types.Hiscores, []FetchFailure, error) {
 	var userHiscores map[model.Users]types.Hiscores = make(map[model.Users]types.Hiscores)
+	failures := []FetchFailure{}
 
 	var wg sync.WaitGroup
 	lock := sync.Mutex{}
@@ -202,17 +222,28 @@ func GetUserHiscores(allUsers []model.Users, leaderboardOverride string) (map[mo
 
 			// If a user changes their RSN we don't want to break the entire process.
 			// We'll just exclude them from the results.
-			if err == nil {
+			if err != nil {
+				log.Printf("Excluding user %s from results: %s\n", user.OsrsUsername, err)
 				lock.Lock()
-				userHiscores[user] = userHS
+				failures = append(failures, FetchFailure{User: user, Err: err})
 				lock.Unlock()
+				return
 			}
+
+			lock.Lock()
+			userHiscores[user] = userHS
+			lock.Unlock()
 		}(user)
 	}
 
 	wg.Wait()
 
-	return userHiscores, nil
+	// Keep reports stable no matter which request finished first
+	slices.SortFunc(failures, func(a, b FetchFailure) int {
+		return strings.Compare(strings.ToLower(a.User.OsrsUsername), strings.ToLower(b.User.OsrsUsername))
+	})
+
+	return userHiscores, failures, nil
 }
 
 // ContainsCaseInsensitive checks if a string slice contains a specific string, ignoring case.
@@ -248,7 +279,7 @@ func IsActivityOrSkill(name string) (string, error) {
 	case ContainsCaseInsensitive(allSkills, name):
 		return "skill", nil
 	default:
-		return "", fmt.Errorf("Unable to associate %s with any known skill or activity", name)
+		return "", fmt.Errorf("%s is %w", name, ErrUnknownActivity)
 	}
 
 }
@@ -0,0 +1,52 @@
+package hiscores
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/michohl/osrs-clan-leaderboard/types"
+)
+
+// ParseRankRequirements takes a comma separated list of requirements
+// in the form "Activity>=Threshold" e.g. "Overall>=1500, Zulrah>=100".
+// An empty string is valid and means the rank has no requirements
+func ParseRankRequirements(requirements string) ([]types.RankRequirement, error) {
+	parsed := []types.RankRequirement{}
+
+	for requirement := range strings.SplitSeq(requirements, ",") {
+		requirement = strings.Trim(requirement, " ")
+		if requirement == "" {
+			continue
+		}
+
+		activity, threshold, found := strings.Cut(requirement, ">=")
+		if !found {
+			return nil, fmt.Errorf("Requirement '%s' must look like Activity>=Threshold", requirement)
+		}
+
+		value, err := strconv.Atoi(strings.Trim(threshold, " "))
+		if err != nil {
+			return nil, fmt.Errorf("Requirement '%s' has an invalid threshold", requirement)
+		}
+
+		parsed = append(parsed, types.RankRequirement{
+			Activity:  strings.Trim(activity, " "),
+			Threshold: value,
+		})
+	}
+
+	return parsed, nil
+}
+
+// MeetsRankRequirements checks if a player's hiscores meet every requirement
+func MeetsRankRequirements(hs types.Hiscores, requirements []types.RankRequirement) bool {
+	for _, requirement := range requirements {
+		value, ok := hs.GetLevelOrScore(requirement.Activity)
+		if !ok || value < requirement.Threshold {
+			return false
+		}
+	}
+
+	return true
+}
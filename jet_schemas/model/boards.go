@@ -0,0 +1,19 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package model
+
+type Boards struct {
+	ServerID          string `sql:"primary_key"`
+	BoardName         string `sql:"primary_key"`
+	ChannelName       string
+	Schedule          string
+	ShouldEditMessage bool
+	HideUnranked      bool
+	ShowRank          bool
+	ChannelID         string
+}
@@ -0,0 +1,20 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package model
+
+import (
+	"time"
+)
+
+type HiscoresSnapshots struct {
+	OsrsUsername    string `sql:"primary_key"`
+	OsrsAccountType string `sql:"primary_key"`
+	Leaderboard     string `sql:"primary_key"`
+	FetchedAt       time.Time
+	Hiscores        string
+}
@@ -10,6 +10,7 @@ package model
 type Messages struct {
 	MessageID string `sql:"primary_key"`
 	ServerID  string `sql:"primary_key"`
+	BoardName string `sql:"primary_key"`
 	Activity  string `sql:"primary_key"`
 	Position  int32
 }
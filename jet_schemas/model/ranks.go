@@ -0,0 +1,15 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package model
+
+type Ranks struct {
+	ServerID     string `sql:"primary_key"`
+	RankName     string `sql:"primary_key"`
+	Position     int32
+	Requirements string
+}
@@ -0,0 +1,15 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package model
+
+type RoleRules struct {
+	ServerID  string `sql:"primary_key"`
+	RoleID    string `sql:"primary_key"`
+	Activity  string `sql:"primary_key"`
+	Threshold int32
+}
@@ -0,0 +1,21 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package model
+
+import (
+	"time"
+)
+
+type RunFetchFailures struct {
+	ServerID        string    `sql:"primary_key"`
+	BoardName       string    `sql:"primary_key"`
+	StartedAt       time.Time `sql:"primary_key"`
+	OsrsUsername    string    `sql:"primary_key"`
+	OsrsAccountType string    `sql:"primary_key"`
+	Reason          string
+}
@@ -0,0 +1,20 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package model
+
+import (
+	"time"
+)
+
+type RunLocks struct {
+	ServerID   string `sql:"primary_key"`
+	BoardName  string `sql:"primary_key"`
+	Owner      string
+	AcquiredAt time.Time
+	ExpiresAt  time.Time
+}
@@ -0,0 +1,22 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package model
+
+import (
+	"time"
+)
+
+type ScheduleRuns struct {
+	ServerID     string    `sql:"primary_key"`
+	BoardName    string    `sql:"primary_key"`
+	StartedAt    time.Time `sql:"primary_key"`
+	DurationMs   int32
+	TriggeredBy  string
+	Outcome      string
+	ErrorMessage string
+}
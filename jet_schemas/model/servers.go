@@ -8,10 +8,19 @@
 package model
 
 type Servers struct {
-	ID                string `sql:"primary_key"`
-	ServerName        string
-	ChannelName       string
-	Schedule          string
-	ShouldEditMessage bool
-	IsEnabled         bool
+	ID                        string `sql:"primary_key"`
+	ServerName                string
+	IsEnabled                 bool
+	DisabledReason            string
+	MemberLeaveAction         string
+	RoleSyncEnabled           bool
+	NicknameSyncEnabled       bool
+	NicknameAccountTypePrefix bool
+	RankReportSchedule        string
+	Timezone                  string
+	CatchUpEnabled            bool
+	CatchUpMaxLatenessHours   int32
+	OrphanedMessageAction     string
+	AdminChannelID            string
+	StaleDataMaxAgeHours      int32
 }
@@ -14,4 +14,8 @@ type Users struct {
 	OsrsAccountType string
 	DiscordUsername string
 	DiscordUserID   string
+	IsArchived      bool
+	PossiblyRenamed bool
+	IsPrimary       bool
+	ClanRank        string
 }
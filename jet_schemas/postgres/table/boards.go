@@ -0,0 +1,99 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/postgres"
+)
+
+var Boards = newBoardsTable("public", "boards", "")
+
+type boardsTable struct {
+	postgres.Table
+
+	// Columns
+	ServerID          postgres.ColumnString
+	BoardName         postgres.ColumnString
+	ChannelName       postgres.ColumnString
+	Schedule          postgres.ColumnString
+	ShouldEditMessage postgres.ColumnBool
+	HideUnranked      postgres.ColumnBool
+	ShowRank          postgres.ColumnBool
+	ChannelID         postgres.ColumnString
+
+	AllColumns     postgres.ColumnList
+	MutableColumns postgres.ColumnList
+	DefaultColumns postgres.ColumnList
+}
+
+type BoardsTable struct {
+	boardsTable
+
+	EXCLUDED boardsTable
+}
+
+// AS creates new BoardsTable with assigned alias
+func (a BoardsTable) AS(alias string) *BoardsTable {
+	return newBoardsTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new BoardsTable with assigned schema name
+func (a BoardsTable) FromSchema(schemaName string) *BoardsTable {
+	return newBoardsTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new BoardsTable with assigned table prefix
+func (a BoardsTable) WithPrefix(prefix string) *BoardsTable {
+	return newBoardsTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new BoardsTable with assigned table suffix
+func (a BoardsTable) WithSuffix(suffix string) *BoardsTable {
+	return newBoardsTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newBoardsTable(schemaName, tableName, alias string) *BoardsTable {
+	return &BoardsTable{
+		boardsTable: newBoardsTableImpl(schemaName, tableName, alias),
+		EXCLUDED:    newBoardsTableImpl("", "excluded", ""),
+	}
+}
+
+func newBoardsTableImpl(schemaName, tableName, alias string) boardsTable {
+	var (
+		ServerIDColumn          = postgres.StringColumn("server_id")
+		BoardNameColumn         = postgres.StringColumn("board_name")
+		ChannelNameColumn       = postgres.StringColumn("channel_name")
+		ScheduleColumn          = postgres.StringColumn("schedule")
+		ShouldEditMessageColumn = postgres.BoolColumn("should_edit_message")
+		HideUnrankedColumn      = postgres.BoolColumn("hide_unranked")
+		ShowRankColumn          = postgres.BoolColumn("show_rank")
+		ChannelIDColumn         = postgres.StringColumn("channel_id")
+		allColumns              = postgres.ColumnList{ServerIDColumn, BoardNameColumn, ChannelNameColumn, ScheduleColumn, ShouldEditMessageColumn, HideUnrankedColumn, ShowRankColumn, ChannelIDColumn}
+		mutableColumns          = postgres.ColumnList{ChannelNameColumn, ScheduleColumn, ShouldEditMessageColumn, HideUnrankedColumn, ShowRankColumn, ChannelIDColumn}
+		defaultColumns          = postgres.ColumnList{ServerIDColumn, BoardNameColumn, ChannelNameColumn, ScheduleColumn, ShouldEditMessageColumn, HideUnrankedColumn, ShowRankColumn, ChannelIDColumn}
+	)
+
+	return boardsTable{
+		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		ServerID:          ServerIDColumn,
+		BoardName:         BoardNameColumn,
+		ChannelName:       ChannelNameColumn,
+		Schedule:          ScheduleColumn,
+		ShouldEditMessage: ShouldEditMessageColumn,
+		HideUnranked:      HideUnrankedColumn,
+		ShowRank:          ShowRankColumn,
+		ChannelID:         ChannelIDColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+		DefaultColumns: defaultColumns,
+	}
+}
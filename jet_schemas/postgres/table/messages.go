@@ -0,0 +1,90 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/postgres"
+)
+
+var Messages = newMessagesTable("public", "messages", "")
+
+type messagesTable struct {
+	postgres.Table
+
+	// Columns
+	MessageID postgres.ColumnString
+	ServerID  postgres.ColumnString
+	BoardName postgres.ColumnString
+	Activity  postgres.ColumnString
+	Position  postgres.ColumnInteger
+
+	AllColumns     postgres.ColumnList
+	MutableColumns postgres.ColumnList
+	DefaultColumns postgres.ColumnList
+}
+
+type MessagesTable struct {
+	messagesTable
+
+	EXCLUDED messagesTable
+}
+
+// AS creates new MessagesTable with assigned alias
+func (a MessagesTable) AS(alias string) *MessagesTable {
+	return newMessagesTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new MessagesTable with assigned schema name
+func (a MessagesTable) FromSchema(schemaName string) *MessagesTable {
+	return newMessagesTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new MessagesTable with assigned table prefix
+func (a MessagesTable) WithPrefix(prefix string) *MessagesTable {
+	return newMessagesTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new MessagesTable with assigned table suffix
+func (a MessagesTable) WithSuffix(suffix string) *MessagesTable {
+	return newMessagesTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newMessagesTable(schemaName, tableName, alias string) *MessagesTable {
+	return &MessagesTable{
+		messagesTable: newMessagesTableImpl(schemaName, tableName, alias),
+		EXCLUDED:      newMessagesTableImpl("", "excluded", ""),
+	}
+}
+
+func newMessagesTableImpl(schemaName, tableName, alias string) messagesTable {
+	var (
+		MessageIDColumn = postgres.StringColumn("message_id")
+		ServerIDColumn  = postgres.StringColumn("server_id")
+		BoardNameColumn = postgres.StringColumn("board_name")
+		ActivityColumn  = postgres.StringColumn("activity")
+		PositionColumn  = postgres.IntegerColumn("position")
+		allColumns      = postgres.ColumnList{MessageIDColumn, ServerIDColumn, BoardNameColumn, ActivityColumn, PositionColumn}
+		mutableColumns  = postgres.ColumnList{PositionColumn}
+		defaultColumns  = postgres.ColumnList{MessageIDColumn, ServerIDColumn, BoardNameColumn, ActivityColumn, PositionColumn}
+	)
+
+	return messagesTable{
+		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		MessageID: MessageIDColumn,
+		ServerID:  ServerIDColumn,
+		BoardName: BoardNameColumn,
+		Activity:  ActivityColumn,
+		Position:  PositionColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+		DefaultColumns: defaultColumns,
+	}
+}
@@ -0,0 +1,87 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/postgres"
+)
+
+var Ranks = newRanksTable("public", "ranks", "")
+
+type ranksTable struct {
+	postgres.Table
+
+	// Columns
+	ServerID     postgres.ColumnString
+	RankName     postgres.ColumnString
+	Position     postgres.ColumnInteger
+	Requirements postgres.ColumnString
+
+	AllColumns     postgres.ColumnList
+	MutableColumns postgres.ColumnList
+	DefaultColumns postgres.ColumnList
+}
+
+type RanksTable struct {
+	ranksTable
+
+	EXCLUDED ranksTable
+}
+
+// AS creates new RanksTable with assigned alias
+func (a RanksTable) AS(alias string) *RanksTable {
+	return newRanksTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new RanksTable with assigned schema name
+func (a RanksTable) FromSchema(schemaName string) *RanksTable {
+	return newRanksTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new RanksTable with assigned table prefix
+func (a RanksTable) WithPrefix(prefix string) *RanksTable {
+	return newRanksTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new RanksTable with assigned table suffix
+func (a RanksTable) WithSuffix(suffix string) *RanksTable {
+	return newRanksTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newRanksTable(schemaName, tableName, alias string) *RanksTable {
+	return &RanksTable{
+		ranksTable: newRanksTableImpl(schemaName, tableName, alias),
+		EXCLUDED:   newRanksTableImpl("", "excluded", ""),
+	}
+}
+
+func newRanksTableImpl(schemaName, tableName, alias string) ranksTable {
+	var (
+		ServerIDColumn     = postgres.StringColumn("server_id")
+		RankNameColumn     = postgres.StringColumn("rank_name")
+		PositionColumn     = postgres.IntegerColumn("position")
+		RequirementsColumn = postgres.StringColumn("requirements")
+		allColumns         = postgres.ColumnList{ServerIDColumn, RankNameColumn, PositionColumn, RequirementsColumn}
+		mutableColumns     = postgres.ColumnList{PositionColumn, RequirementsColumn}
+		defaultColumns     = postgres.ColumnList{ServerIDColumn, RankNameColumn, PositionColumn, RequirementsColumn}
+	)
+
+	return ranksTable{
+		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		ServerID:     ServerIDColumn,
+		RankName:     RankNameColumn,
+		Position:     PositionColumn,
+		Requirements: RequirementsColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+		DefaultColumns: defaultColumns,
+	}
+}
@@ -0,0 +1,87 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/postgres"
+)
+
+var RoleRules = newRoleRulesTable("public", "role_rules", "")
+
+type roleRulesTable struct {
+	postgres.Table
+
+	// Columns
+	ServerID  postgres.ColumnString
+	RoleID    postgres.ColumnString
+	Activity  postgres.ColumnString
+	Threshold postgres.ColumnInteger
+
+	AllColumns     postgres.ColumnList
+	MutableColumns postgres.ColumnList
+	DefaultColumns postgres.ColumnList
+}
+
+type RoleRulesTable struct {
+	roleRulesTable
+
+	EXCLUDED roleRulesTable
+}
+
+// AS creates new RoleRulesTable with assigned alias
+func (a RoleRulesTable) AS(alias string) *RoleRulesTable {
+	return newRoleRulesTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new RoleRulesTable with assigned schema name
+func (a RoleRulesTable) FromSchema(schemaName string) *RoleRulesTable {
+	return newRoleRulesTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new RoleRulesTable with assigned table prefix
+func (a RoleRulesTable) WithPrefix(prefix string) *RoleRulesTable {
+	return newRoleRulesTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new RoleRulesTable with assigned table suffix
+func (a RoleRulesTable) WithSuffix(suffix string) *RoleRulesTable {
+	return newRoleRulesTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newRoleRulesTable(schemaName, tableName, alias string) *RoleRulesTable {
+	return &RoleRulesTable{
+		roleRulesTable: newRoleRulesTableImpl(schemaName, tableName, alias),
+		EXCLUDED:       newRoleRulesTableImpl("", "excluded", ""),
+	}
+}
+
+func newRoleRulesTableImpl(schemaName, tableName, alias string) roleRulesTable {
+	var (
+		ServerIDColumn  = postgres.StringColumn("server_id")
+		RoleIDColumn    = postgres.StringColumn("role_id")
+		ActivityColumn  = postgres.StringColumn("activity")
+		ThresholdColumn = postgres.IntegerColumn("threshold")
+		allColumns      = postgres.ColumnList{ServerIDColumn, RoleIDColumn, ActivityColumn, ThresholdColumn}
+		mutableColumns  = postgres.ColumnList{ThresholdColumn}
+		defaultColumns  = postgres.ColumnList{ServerIDColumn, RoleIDColumn, ActivityColumn, ThresholdColumn}
+	)
+
+	return roleRulesTable{
+		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		ServerID:  ServerIDColumn,
+		RoleID:    RoleIDColumn,
+		Activity:  ActivityColumn,
+		Threshold: ThresholdColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+		DefaultColumns: defaultColumns,
+	}
+}
@@ -0,0 +1,93 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/postgres"
+)
+
+var RunFetchFailures = newRunFetchFailuresTable("public", "run_fetch_failures", "")
+
+type runFetchFailuresTable struct {
+	postgres.Table
+
+	// Columns
+	ServerID        postgres.ColumnString
+	BoardName       postgres.ColumnString
+	StartedAt       postgres.ColumnTimestamp
+	OsrsUsername    postgres.ColumnString
+	OsrsAccountType postgres.ColumnString
+	Reason          postgres.ColumnString
+
+	AllColumns     postgres.ColumnList
+	MutableColumns postgres.ColumnList
+	DefaultColumns postgres.ColumnList
+}
+
+type RunFetchFailuresTable struct {
+	runFetchFailuresTable
+
+	EXCLUDED runFetchFailuresTable
+}
+
+// AS creates new RunFetchFailuresTable with assigned alias
+func (a RunFetchFailuresTable) AS(alias string) *RunFetchFailuresTable {
+	return newRunFetchFailuresTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new RunFetchFailuresTable with assigned schema name
+func (a RunFetchFailuresTable) FromSchema(schemaName string) *RunFetchFailuresTable {
+	return newRunFetchFailuresTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new RunFetchFailuresTable with assigned table prefix
+func (a RunFetchFailuresTable) WithPrefix(prefix string) *RunFetchFailuresTable {
+	return newRunFetchFailuresTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new RunFetchFailuresTable with assigned table suffix
+func (a RunFetchFailuresTable) WithSuffix(suffix string) *RunFetchFailuresTable {
+	return newRunFetchFailuresTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newRunFetchFailuresTable(schemaName, tableName, alias string) *RunFetchFailuresTable {
+	return &RunFetchFailuresTable{
+		runFetchFailuresTable: newRunFetchFailuresTableImpl(schemaName, tableName, alias),
+		EXCLUDED:              newRunFetchFailuresTableImpl("", "excluded", ""),
+	}
+}
+
+func newRunFetchFailuresTableImpl(schemaName, tableName, alias string) runFetchFailuresTable {
+	var (
+		ServerIDColumn        = postgres.StringColumn("server_id")
+		BoardNameColumn       = postgres.StringColumn("board_name")
+		StartedAtColumn       = postgres.TimestampColumn("started_at")
+		OsrsUsernameColumn    = postgres.StringColumn("osrs_username")
+		OsrsAccountTypeColumn = postgres.StringColumn("osrs_account_type")
+		ReasonColumn          = postgres.StringColumn("reason")
+		allColumns            = postgres.ColumnList{ServerIDColumn, BoardNameColumn, StartedAtColumn, OsrsUsernameColumn, OsrsAccountTypeColumn, ReasonColumn}
+		mutableColumns        = postgres.ColumnList{ReasonColumn}
+		defaultColumns        = postgres.ColumnList{ServerIDColumn, BoardNameColumn, OsrsUsernameColumn, OsrsAccountTypeColumn, ReasonColumn}
+	)
+
+	return runFetchFailuresTable{
+		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		ServerID:        ServerIDColumn,
+		BoardName:       BoardNameColumn,
+		StartedAt:       StartedAtColumn,
+		OsrsUsername:    OsrsUsernameColumn,
+		OsrsAccountType: OsrsAccountTypeColumn,
+		Reason:          ReasonColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+		DefaultColumns: defaultColumns,
+	}
+}
@@ -0,0 +1,90 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/postgres"
+)
+
+var RunLocks = newRunLocksTable("public", "run_locks", "")
+
+type runLocksTable struct {
+	postgres.Table
+
+	// Columns
+	ServerID   postgres.ColumnString
+	BoardName  postgres.ColumnString
+	Owner      postgres.ColumnString
+	AcquiredAt postgres.ColumnTimestamp
+	ExpiresAt  postgres.ColumnTimestamp
+
+	AllColumns     postgres.ColumnList
+	MutableColumns postgres.ColumnList
+	DefaultColumns postgres.ColumnList
+}
+
+type RunLocksTable struct {
+	runLocksTable
+
+	EXCLUDED runLocksTable
+}
+
+// AS creates new RunLocksTable with assigned alias
+func (a RunLocksTable) AS(alias string) *RunLocksTable {
+	return newRunLocksTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new RunLocksTable with assigned schema name
+func (a RunLocksTable) FromSchema(schemaName string) *RunLocksTable {
+	return newRunLocksTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new RunLocksTable with assigned table prefix
+func (a RunLocksTable) WithPrefix(prefix string) *RunLocksTable {
+	return newRunLocksTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new RunLocksTable with assigned table suffix
+func (a RunLocksTable) WithSuffix(suffix string) *RunLocksTable {
+	return newRunLocksTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newRunLocksTable(schemaName, tableName, alias string) *RunLocksTable {
+	return &RunLocksTable{
+		runLocksTable: newRunLocksTableImpl(schemaName, tableName, alias),
+		EXCLUDED:      newRunLocksTableImpl("", "excluded", ""),
+	}
+}
+
+func newRunLocksTableImpl(schemaName, tableName, alias string) runLocksTable {
+	var (
+		ServerIDColumn   = postgres.StringColumn("server_id")
+		BoardNameColumn  = postgres.StringColumn("board_name")
+		OwnerColumn      = postgres.StringColumn("owner")
+		AcquiredAtColumn = postgres.TimestampColumn("acquired_at")
+		ExpiresAtColumn  = postgres.TimestampColumn("expires_at")
+		allColumns       = postgres.ColumnList{ServerIDColumn, BoardNameColumn, OwnerColumn, AcquiredAtColumn, ExpiresAtColumn}
+		mutableColumns   = postgres.ColumnList{OwnerColumn, AcquiredAtColumn, ExpiresAtColumn}
+		defaultColumns   = postgres.ColumnList{ServerIDColumn, BoardNameColumn, OwnerColumn}
+	)
+
+	return runLocksTable{
+		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		ServerID:   ServerIDColumn,
+		BoardName:  BoardNameColumn,
+		Owner:      OwnerColumn,
+		AcquiredAt: AcquiredAtColumn,
+		ExpiresAt:  ExpiresAtColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+		DefaultColumns: defaultColumns,
+	}
+}
@@ -0,0 +1,96 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/postgres"
+)
+
+var ScheduleRuns = newScheduleRunsTable("public", "schedule_runs", "")
+
+type scheduleRunsTable struct {
+	postgres.Table
+
+	// Columns
+	ServerID     postgres.ColumnString
+	BoardName    postgres.ColumnString
+	StartedAt    postgres.ColumnTimestamp
+	DurationMs   postgres.ColumnInteger
+	TriggeredBy  postgres.ColumnString
+	Outcome      postgres.ColumnString
+	ErrorMessage postgres.ColumnString
+
+	AllColumns     postgres.ColumnList
+	MutableColumns postgres.ColumnList
+	DefaultColumns postgres.ColumnList
+}
+
+type ScheduleRunsTable struct {
+	scheduleRunsTable
+
+	EXCLUDED scheduleRunsTable
+}
+
+// AS creates new ScheduleRunsTable with assigned alias
+func (a ScheduleRunsTable) AS(alias string) *ScheduleRunsTable {
+	return newScheduleRunsTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new ScheduleRunsTable with assigned schema name
+func (a ScheduleRunsTable) FromSchema(schemaName string) *ScheduleRunsTable {
+	return newScheduleRunsTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new ScheduleRunsTable with assigned table prefix
+func (a ScheduleRunsTable) WithPrefix(prefix string) *ScheduleRunsTable {
+	return newScheduleRunsTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new ScheduleRunsTable with assigned table suffix
+func (a ScheduleRunsTable) WithSuffix(suffix string) *ScheduleRunsTable {
+	return newScheduleRunsTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newScheduleRunsTable(schemaName, tableName, alias string) *ScheduleRunsTable {
+	return &ScheduleRunsTable{
+		scheduleRunsTable: newScheduleRunsTableImpl(schemaName, tableName, alias),
+		EXCLUDED:          newScheduleRunsTableImpl("", "excluded", ""),
+	}
+}
+
+func newScheduleRunsTableImpl(schemaName, tableName, alias string) scheduleRunsTable {
+	var (
+		ServerIDColumn     = postgres.StringColumn("server_id")
+		BoardNameColumn    = postgres.StringColumn("board_name")
+		StartedAtColumn    = postgres.TimestampColumn("started_at")
+		DurationMsColumn   = postgres.IntegerColumn("duration_ms")
+		TriggeredByColumn  = postgres.StringColumn("triggered_by")
+		OutcomeColumn      = postgres.StringColumn("outcome")
+		ErrorMessageColumn = postgres.StringColumn("error_message")
+		allColumns         = postgres.ColumnList{ServerIDColumn, BoardNameColumn, StartedAtColumn, DurationMsColumn, TriggeredByColumn, OutcomeColumn, ErrorMessageColumn}
+		mutableColumns     = postgres.ColumnList{DurationMsColumn, TriggeredByColumn, OutcomeColumn, ErrorMessageColumn}
+		defaultColumns     = postgres.ColumnList{ServerIDColumn, BoardNameColumn, DurationMsColumn, TriggeredByColumn, OutcomeColumn, ErrorMessageColumn}
+	)
+
+	return scheduleRunsTable{
+		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		ServerID:     ServerIDColumn,
+		BoardName:    BoardNameColumn,
+		StartedAt:    StartedAtColumn,
+		DurationMs:   DurationMsColumn,
+		TriggeredBy:  TriggeredByColumn,
+		Outcome:      OutcomeColumn,
+		ErrorMessage: ErrorMessageColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+		DefaultColumns: defaultColumns,
+	}
+}
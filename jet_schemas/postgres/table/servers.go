@@ -0,0 +1,120 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/postgres"
+)
+
+var Servers = newServersTable("public", "servers", "")
+
+type serversTable struct {
+	postgres.Table
+
+	// Columns
+	ID                        postgres.ColumnString
+	ServerName                postgres.ColumnString
+	IsEnabled                 postgres.ColumnBool
+	DisabledReason            postgres.ColumnString
+	MemberLeaveAction         postgres.ColumnString
+	RoleSyncEnabled           postgres.ColumnBool
+	NicknameSyncEnabled       postgres.ColumnBool
+	NicknameAccountTypePrefix postgres.ColumnBool
+	RankReportSchedule        postgres.ColumnString
+	Timezone                  postgres.ColumnString
+	CatchUpEnabled            postgres.ColumnBool
+	CatchUpMaxLatenessHours   postgres.ColumnInteger
+	OrphanedMessageAction     postgres.ColumnString
+	AdminChannelID            postgres.ColumnString
+	StaleDataMaxAgeHours      postgres.ColumnInteger
+
+	AllColumns     postgres.ColumnList
+	MutableColumns postgres.ColumnList
+	DefaultColumns postgres.ColumnList
+}
+
+type ServersTable struct {
+	serversTable
+
+	EXCLUDED serversTable
+}
+
+// AS creates new ServersTable with assigned alias
+func (a ServersTable) AS(alias string) *ServersTable {
+	return newServersTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new ServersTable with assigned schema name
+func (a ServersTable) FromSchema(schemaName string) *ServersTable {
+	return newServersTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new ServersTable with assigned table prefix
+func (a ServersTable) WithPrefix(prefix string) *ServersTable {
+	return newServersTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new ServersTable with assigned table suffix
+func (a ServersTable) WithSuffix(suffix string) *ServersTable {
+	return newServersTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newServersTable(schemaName, tableName, alias string) *ServersTable {
+	return &ServersTable{
+		serversTable: newServersTableImpl(schemaName, tableName, alias),
+		EXCLUDED:     newServersTableImpl("", "excluded", ""),
+	}
+}
+
+func newServersTableImpl(schemaName, tableName, alias string) serversTable {
+	var (
+		IDColumn                        = postgres.StringColumn("id")
+		ServerNameColumn                = postgres.StringColumn("server_name")
+		IsEnabledColumn                 = postgres.BoolColumn("is_enabled")
+		DisabledReasonColumn            = postgres.StringColumn("disabled_reason")
+		MemberLeaveActionColumn         = postgres.StringColumn("member_leave_action")
+		RoleSyncEnabledColumn           = postgres.BoolColumn("role_sync_enabled")
+		NicknameSyncEnabledColumn       = postgres.BoolColumn("nickname_sync_enabled")
+		NicknameAccountTypePrefixColumn = postgres.BoolColumn("nickname_account_type_prefix")
+		RankReportScheduleColumn        = postgres.StringColumn("rank_report_schedule")
+		TimezoneColumn                  = postgres.StringColumn("timezone")
+		CatchUpEnabledColumn            = postgres.BoolColumn("catch_up_enabled")
+		CatchUpMaxLatenessHoursColumn   = postgres.IntegerColumn("catch_up_max_lateness_hours")
+		OrphanedMessageActionColumn     = postgres.StringColumn("orphaned_message_action")
+		AdminChannelIDColumn            = postgres.StringColumn("admin_channel_id")
+		StaleDataMaxAgeHoursColumn      = postgres.IntegerColumn("stale_data_max_age_hours")
+		allColumns                      = postgres.ColumnList{IDColumn, ServerNameColumn, IsEnabledColumn, DisabledReasonColumn, MemberLeaveActionColumn, RoleSyncEnabledColumn, NicknameSyncEnabledColumn, NicknameAccountTypePrefixColumn, RankReportScheduleColumn, TimezoneColumn, CatchUpEnabledColumn, CatchUpMaxLatenessHoursColumn, OrphanedMessageActionColumn, AdminChannelIDColumn, StaleDataMaxAgeHoursColumn}
+		mutableColumns                  = postgres.ColumnList{ServerNameColumn, IsEnabledColumn, DisabledReasonColumn, MemberLeaveActionColumn, RoleSyncEnabledColumn, NicknameSyncEnabledColumn, NicknameAccountTypePrefixColumn, RankReportScheduleColumn, TimezoneColumn, CatchUpEnabledColumn, CatchUpMaxLatenessHoursColumn, OrphanedMessageActionColumn, AdminChannelIDColumn, StaleDataMaxAgeHoursColumn}
+		defaultColumns                  = postgres.ColumnList{ServerNameColumn, IsEnabledColumn, DisabledReasonColumn, MemberLeaveActionColumn, RoleSyncEnabledColumn, NicknameSyncEnabledColumn, NicknameAccountTypePrefixColumn, RankReportScheduleColumn, TimezoneColumn, CatchUpEnabledColumn, CatchUpMaxLatenessHoursColumn, OrphanedMessageActionColumn, AdminChannelIDColumn, StaleDataMaxAgeHoursColumn}
+	)
+
+	return serversTable{
+		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		ID:                        IDColumn,
+		ServerName:                ServerNameColumn,
+		IsEnabled:                 IsEnabledColumn,
+		DisabledReason:            DisabledReasonColumn,
+		MemberLeaveAction:         MemberLeaveActionColumn,
+		RoleSyncEnabled:           RoleSyncEnabledColumn,
+		NicknameSyncEnabled:       NicknameSyncEnabledColumn,
+		NicknameAccountTypePrefix: NicknameAccountTypePrefixColumn,
+		RankReportSchedule:        RankReportScheduleColumn,
+		Timezone:                  TimezoneColumn,
+		CatchUpEnabled:            CatchUpEnabledColumn,
+		CatchUpMaxLatenessHours:   CatchUpMaxLatenessHoursColumn,
+		OrphanedMessageAction:     OrphanedMessageActionColumn,
+		AdminChannelID:            AdminChannelIDColumn,
+		StaleDataMaxAgeHours:      StaleDataMaxAgeHoursColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+		DefaultColumns: defaultColumns,
+	}
+}
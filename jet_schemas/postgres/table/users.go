@@ -0,0 +1,105 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/postgres"
+)
+
+var Users = newUsersTable("public", "users", "")
+
+type usersTable struct {
+	postgres.Table
+
+	// Columns
+	OsrsUsernameKey postgres.ColumnString
+	ServerID        postgres.ColumnString
+	OsrsUsername    postgres.ColumnString
+	OsrsAccountType postgres.ColumnString
+	DiscordUsername postgres.ColumnString
+	DiscordUserID   postgres.ColumnString
+	IsArchived      postgres.ColumnBool
+	PossiblyRenamed postgres.ColumnBool
+	IsPrimary       postgres.ColumnBool
+	ClanRank        postgres.ColumnString
+
+	AllColumns     postgres.ColumnList
+	MutableColumns postgres.ColumnList
+	DefaultColumns postgres.ColumnList
+}
+
+type UsersTable struct {
+	usersTable
+
+	EXCLUDED usersTable
+}
+
+// AS creates new UsersTable with assigned alias
+func (a UsersTable) AS(alias string) *UsersTable {
+	return newUsersTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new UsersTable with assigned schema name
+func (a UsersTable) FromSchema(schemaName string) *UsersTable {
+	return newUsersTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new UsersTable with assigned table prefix
+func (a UsersTable) WithPrefix(prefix string) *UsersTable {
+	return newUsersTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new UsersTable with assigned table suffix
+func (a UsersTable) WithSuffix(suffix string) *UsersTable {
+	return newUsersTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newUsersTable(schemaName, tableName, alias string) *UsersTable {
+	return &UsersTable{
+		usersTable: newUsersTableImpl(schemaName, tableName, alias),
+		EXCLUDED:   newUsersTableImpl("", "excluded", ""),
+	}
+}
+
+func newUsersTableImpl(schemaName, tableName, alias string) usersTable {
+	var (
+		OsrsUsernameKeyColumn = postgres.StringColumn("osrs_username_key")
+		ServerIDColumn        = postgres.StringColumn("server_id")
+		OsrsUsernameColumn    = postgres.StringColumn("osrs_username")
+		OsrsAccountTypeColumn = postgres.StringColumn("osrs_account_type")
+		DiscordUsernameColumn = postgres.StringColumn("discord_username")
+		DiscordUserIDColumn   = postgres.StringColumn("discord_user_id")
+		IsArchivedColumn      = postgres.BoolColumn("is_archived")
+		PossiblyRenamedColumn = postgres.BoolColumn("possibly_renamed")
+		IsPrimaryColumn       = postgres.BoolColumn("is_primary")
+		ClanRankColumn        = postgres.StringColumn("clan_rank")
+		allColumns            = postgres.ColumnList{OsrsUsernameKeyColumn, ServerIDColumn, OsrsUsernameColumn, OsrsAccountTypeColumn, DiscordUsernameColumn, DiscordUserIDColumn, IsArchivedColumn, PossiblyRenamedColumn, IsPrimaryColumn, ClanRankColumn}
+		mutableColumns        = postgres.ColumnList{OsrsUsernameColumn, OsrsAccountTypeColumn, DiscordUsernameColumn, DiscordUserIDColumn, IsArchivedColumn, PossiblyRenamedColumn, IsPrimaryColumn, ClanRankColumn}
+		defaultColumns        = postgres.ColumnList{OsrsUsernameKeyColumn, ServerIDColumn, OsrsUsernameColumn, OsrsAccountTypeColumn, DiscordUsernameColumn, DiscordUserIDColumn, IsArchivedColumn, PossiblyRenamedColumn, IsPrimaryColumn, ClanRankColumn}
+	)
+
+	return usersTable{
+		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		OsrsUsernameKey: OsrsUsernameKeyColumn,
+		ServerID:        ServerIDColumn,
+		OsrsUsername:    OsrsUsernameColumn,
+		OsrsAccountType: OsrsAccountTypeColumn,
+		DiscordUsername: DiscordUsernameColumn,
+		DiscordUserID:   DiscordUserIDColumn,
+		IsArchived:      IsArchivedColumn,
+		PossiblyRenamed: PossiblyRenamedColumn,
+		IsPrimary:       IsPrimaryColumn,
+		ClanRank:        ClanRankColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+		DefaultColumns: defaultColumns,
+	}
+}
@@ -0,0 +1,99 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/sqlite"
+)
+
+var Boards = newBoardsTable("", "boards", "")
+
+type boardsTable struct {
+	sqlite.Table
+
+	// Columns
+	ServerID          sqlite.ColumnString
+	BoardName         sqlite.ColumnString
+	ChannelName       sqlite.ColumnString
+	Schedule          sqlite.ColumnString
+	ShouldEditMessage sqlite.ColumnBool
+	HideUnranked      sqlite.ColumnBool
+	ShowRank          sqlite.ColumnBool
+	ChannelID         sqlite.ColumnString
+
+	AllColumns     sqlite.ColumnList
+	MutableColumns sqlite.ColumnList
+	DefaultColumns sqlite.ColumnList
+}
+
+type BoardsTable struct {
+	boardsTable
+
+	EXCLUDED boardsTable
+}
+
+// AS creates new BoardsTable with assigned alias
+func (a BoardsTable) AS(alias string) *BoardsTable {
+	return newBoardsTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new BoardsTable with assigned schema name
+func (a BoardsTable) FromSchema(schemaName string) *BoardsTable {
+	return newBoardsTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new BoardsTable with assigned table prefix
+func (a BoardsTable) WithPrefix(prefix string) *BoardsTable {
+	return newBoardsTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new BoardsTable with assigned table suffix
+func (a BoardsTable) WithSuffix(suffix string) *BoardsTable {
+	return newBoardsTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newBoardsTable(schemaName, tableName, alias string) *BoardsTable {
+	return &BoardsTable{
+		boardsTable: newBoardsTableImpl(schemaName, tableName, alias),
+		EXCLUDED:    newBoardsTableImpl("", "excluded", ""),
+	}
+}
+
+func newBoardsTableImpl(schemaName, tableName, alias string) boardsTable {
+	var (
+		ServerIDColumn          = sqlite.StringColumn("server_id")
+		BoardNameColumn         = sqlite.StringColumn("board_name")
+		ChannelNameColumn       = sqlite.StringColumn("channel_name")
+		ScheduleColumn          = sqlite.StringColumn("schedule")
+		ShouldEditMessageColumn = sqlite.BoolColumn("should_edit_message")
+		HideUnrankedColumn      = sqlite.BoolColumn("hide_unranked")
+		ShowRankColumn          = sqlite.BoolColumn("show_rank")
+		ChannelIDColumn         = sqlite.StringColumn("channel_id")
+		allColumns              = sqlite.ColumnList{ServerIDColumn, BoardNameColumn, ChannelNameColumn, ScheduleColumn, ShouldEditMessageColumn, HideUnrankedColumn, ShowRankColumn, ChannelIDColumn}
+		mutableColumns          = sqlite.ColumnList{ChannelNameColumn, ScheduleColumn, ShouldEditMessageColumn, HideUnrankedColumn, ShowRankColumn, ChannelIDColumn}
+		defaultColumns          = sqlite.ColumnList{ServerIDColumn, BoardNameColumn, ChannelNameColumn, ScheduleColumn, ShouldEditMessageColumn, HideUnrankedColumn, ShowRankColumn, ChannelIDColumn}
+	)
+
+	return boardsTable{
+		Table: sqlite.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		ServerID:          ServerIDColumn,
+		BoardName:         BoardNameColumn,
+		ChannelName:       ChannelNameColumn,
+		Schedule:          ScheduleColumn,
+		ShouldEditMessage: ShouldEditMessageColumn,
+		HideUnranked:      HideUnrankedColumn,
+		ShowRank:          ShowRankColumn,
+		ChannelID:         ChannelIDColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+		DefaultColumns: defaultColumns,
+	}
+}
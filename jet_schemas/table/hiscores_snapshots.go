@@ -0,0 +1,90 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/sqlite"
+)
+
+var HiscoresSnapshots = newHiscoresSnapshotsTable("", "hiscores_snapshots", "")
+
+type hiscoresSnapshotsTable struct {
+	sqlite.Table
+
+	// Columns
+	OsrsUsername    sqlite.ColumnString
+	OsrsAccountType sqlite.ColumnString
+	Leaderboard     sqlite.ColumnString
+	FetchedAt       sqlite.ColumnTimestamp
+	Hiscores        sqlite.ColumnString
+
+	AllColumns     sqlite.ColumnList
+	MutableColumns sqlite.ColumnList
+	DefaultColumns sqlite.ColumnList
+}
+
+type HiscoresSnapshotsTable struct {
+	hiscoresSnapshotsTable
+
+	EXCLUDED hiscoresSnapshotsTable
+}
+
+// AS creates new HiscoresSnapshotsTable with assigned alias
+func (a HiscoresSnapshotsTable) AS(alias string) *HiscoresSnapshotsTable {
+	return newHiscoresSnapshotsTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new HiscoresSnapshotsTable with assigned schema name
+func (a HiscoresSnapshotsTable) FromSchema(schemaName string) *HiscoresSnapshotsTable {
+	return newHiscoresSnapshotsTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new HiscoresSnapshotsTable with assigned table prefix
+func (a HiscoresSnapshotsTable) WithPrefix(prefix string) *HiscoresSnapshotsTable {
+	return newHiscoresSnapshotsTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new HiscoresSnapshotsTable with assigned table suffix
+func (a HiscoresSnapshotsTable) WithSuffix(suffix string) *HiscoresSnapshotsTable {
+	return newHiscoresSnapshotsTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newHiscoresSnapshotsTable(schemaName, tableName, alias string) *HiscoresSnapshotsTable {
+	return &HiscoresSnapshotsTable{
+		hiscoresSnapshotsTable: newHiscoresSnapshotsTableImpl(schemaName, tableName, alias),
+		EXCLUDED:               newHiscoresSnapshotsTableImpl("", "excluded", ""),
+	}
+}
+
+func newHiscoresSnapshotsTableImpl(schemaName, tableName, alias string) hiscoresSnapshotsTable {
+	var (
+		OsrsUsernameColumn    = sqlite.StringColumn("osrs_username")
+		OsrsAccountTypeColumn = sqlite.StringColumn("osrs_account_type")
+		LeaderboardColumn     = sqlite.StringColumn("leaderboard")
+		FetchedAtColumn       = sqlite.TimestampColumn("fetched_at")
+		HiscoresColumn        = sqlite.StringColumn("hiscores")
+		allColumns            = sqlite.ColumnList{OsrsUsernameColumn, OsrsAccountTypeColumn, LeaderboardColumn, FetchedAtColumn, HiscoresColumn}
+		mutableColumns        = sqlite.ColumnList{FetchedAtColumn, HiscoresColumn}
+		defaultColumns        = sqlite.ColumnList{OsrsUsernameColumn, OsrsAccountTypeColumn, LeaderboardColumn, HiscoresColumn}
+	)
+
+	return hiscoresSnapshotsTable{
+		Table: sqlite.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		OsrsUsername:    OsrsUsernameColumn,
+		OsrsAccountType: OsrsAccountTypeColumn,
+		Leaderboard:     LeaderboardColumn,
+		FetchedAt:       FetchedAtColumn,
+		Hiscores:        HiscoresColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+		DefaultColumns: defaultColumns,
+	}
+}
@@ -19,6 +19,7 @@ type messagesTable struct {
 	// Columns
 	MessageID sqlite.ColumnString
 	ServerID  sqlite.ColumnString
+	BoardName sqlite.ColumnString
 	Activity  sqlite.ColumnString
 	Position  sqlite.ColumnInteger
 
@@ -64,11 +65,12 @@ func newMessagesTableImpl(schemaName, tableName, alias string) messagesTable {
 	var (
 		MessageIDColumn = sqlite.StringColumn("message_id")
 		ServerIDColumn  = sqlite.StringColumn("server_id")
+		BoardNameColumn = sqlite.StringColumn("board_name")
 		ActivityColumn  = sqlite.StringColumn("activity")
 		PositionColumn  = sqlite.IntegerColumn("position")
-		allColumns      = sqlite.ColumnList{MessageIDColumn, ServerIDColumn, ActivityColumn, PositionColumn}
+		allColumns      = sqlite.ColumnList{MessageIDColumn, ServerIDColumn, BoardNameColumn, ActivityColumn, PositionColumn}
 		mutableColumns  = sqlite.ColumnList{PositionColumn}
-		defaultColumns  = sqlite.ColumnList{MessageIDColumn, ServerIDColumn, ActivityColumn, PositionColumn}
+		defaultColumns  = sqlite.ColumnList{MessageIDColumn, ServerIDColumn, BoardNameColumn, ActivityColumn, PositionColumn}
 	)
 
 	return messagesTable{
@@ -77,6 +79,7 @@ func newMessagesTableImpl(schemaName, tableName, alias string) messagesTable {
 		//Columns
 		MessageID: MessageIDColumn,
 		ServerID:  ServerIDColumn,
+		BoardName: BoardNameColumn,
 		Activity:  ActivityColumn,
 		Position:  PositionColumn,
 
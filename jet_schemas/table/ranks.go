@@ -0,0 +1,87 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/sqlite"
+)
+
+var Ranks = newRanksTable("", "ranks", "")
+
+type ranksTable struct {
+	sqlite.Table
+
+	// Columns
+	ServerID     sqlite.ColumnString
+	RankName     sqlite.ColumnString
+	Position     sqlite.ColumnInteger
+	Requirements sqlite.ColumnString
+
+	AllColumns     sqlite.ColumnList
+	MutableColumns sqlite.ColumnList
+	DefaultColumns sqlite.ColumnList
+}
+
+type RanksTable struct {
+	ranksTable
+
+	EXCLUDED ranksTable
+}
+
+// AS creates new RanksTable with assigned alias
+func (a RanksTable) AS(alias string) *RanksTable {
+	return newRanksTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new RanksTable with assigned schema name
+func (a RanksTable) FromSchema(schemaName string) *RanksTable {
+	return newRanksTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new RanksTable with assigned table prefix
+func (a RanksTable) WithPrefix(prefix string) *RanksTable {
+	return newRanksTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new RanksTable with assigned table suffix
+func (a RanksTable) WithSuffix(suffix string) *RanksTable {
+	return newRanksTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newRanksTable(schemaName, tableName, alias string) *RanksTable {
+	return &RanksTable{
+		ranksTable: newRanksTableImpl(schemaName, tableName, alias),
+		EXCLUDED:   newRanksTableImpl("", "excluded", ""),
+	}
+}
+
+func newRanksTableImpl(schemaName, tableName, alias string) ranksTable {
+	var (
+		ServerIDColumn     = sqlite.StringColumn("server_id")
+		RankNameColumn     = sqlite.StringColumn("rank_name")
+		PositionColumn     = sqlite.IntegerColumn("position")
+		RequirementsColumn = sqlite.StringColumn("requirements")
+		allColumns         = sqlite.ColumnList{ServerIDColumn, RankNameColumn, PositionColumn, RequirementsColumn}
+		mutableColumns     = sqlite.ColumnList{PositionColumn, RequirementsColumn}
+		defaultColumns     = sqlite.ColumnList{ServerIDColumn, RankNameColumn, PositionColumn, RequirementsColumn}
+	)
+
+	return ranksTable{
+		Table: sqlite.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		ServerID:     ServerIDColumn,
+		RankName:     RankNameColumn,
+		Position:     PositionColumn,
+		Requirements: RequirementsColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+		DefaultColumns: defaultColumns,
+	}
+}
@@ -0,0 +1,93 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/sqlite"
+)
+
+var RunFetchFailures = newRunFetchFailuresTable("", "run_fetch_failures", "")
+
+type runFetchFailuresTable struct {
+	sqlite.Table
+
+	// Columns
+	ServerID        sqlite.ColumnString
+	BoardName       sqlite.ColumnString
+	StartedAt       sqlite.ColumnTimestamp
+	OsrsUsername    sqlite.ColumnString
+	OsrsAccountType sqlite.ColumnString
+	Reason          sqlite.ColumnString
+
+	AllColumns     sqlite.ColumnList
+	MutableColumns sqlite.ColumnList
+	DefaultColumns sqlite.ColumnList
+}
+
+type RunFetchFailuresTable struct {
+	runFetchFailuresTable
+
+	EXCLUDED runFetchFailuresTable
+}
+
+// AS creates new RunFetchFailuresTable with assigned alias
+func (a RunFetchFailuresTable) AS(alias string) *RunFetchFailuresTable {
+	return newRunFetchFailuresTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new RunFetchFailuresTable with assigned schema name
+func (a RunFetchFailuresTable) FromSchema(schemaName string) *RunFetchFailuresTable {
+	return newRunFetchFailuresTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new RunFetchFailuresTable with assigned table prefix
+func (a RunFetchFailuresTable) WithPrefix(prefix string) *RunFetchFailuresTable {
+	return newRunFetchFailuresTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new RunFetchFailuresTable with assigned table suffix
+func (a RunFetchFailuresTable) WithSuffix(suffix string) *RunFetchFailuresTable {
+	return newRunFetchFailuresTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newRunFetchFailuresTable(schemaName, tableName, alias string) *RunFetchFailuresTable {
+	return &RunFetchFailuresTable{
+		runFetchFailuresTable: newRunFetchFailuresTableImpl(schemaName, tableName, alias),
+		EXCLUDED:              newRunFetchFailuresTableImpl("", "excluded", ""),
+	}
+}
+
+func newRunFetchFailuresTableImpl(schemaName, tableName, alias string) runFetchFailuresTable {
+	var (
+		ServerIDColumn        = sqlite.StringColumn("server_id")
+		BoardNameColumn       = sqlite.StringColumn("board_name")
+		StartedAtColumn       = sqlite.TimestampColumn("started_at")
+		OsrsUsernameColumn    = sqlite.StringColumn("osrs_username")
+		OsrsAccountTypeColumn = sqlite.StringColumn("osrs_account_type")
+		ReasonColumn          = sqlite.StringColumn("reason")
+		allColumns            = sqlite.ColumnList{ServerIDColumn, BoardNameColumn, StartedAtColumn, OsrsUsernameColumn, OsrsAccountTypeColumn, ReasonColumn}
+		mutableColumns        = sqlite.ColumnList{ReasonColumn}
+		defaultColumns        = sqlite.ColumnList{ServerIDColumn, BoardNameColumn, OsrsUsernameColumn, OsrsAccountTypeColumn, ReasonColumn}
+	)
+
+	return runFetchFailuresTable{
+		Table: sqlite.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		ServerID:        ServerIDColumn,
+		BoardName:       BoardNameColumn,
+		StartedAt:       StartedAtColumn,
+		OsrsUsername:    OsrsUsernameColumn,
+		OsrsAccountType: OsrsAccountTypeColumn,
+		Reason:          ReasonColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+		DefaultColumns: defaultColumns,
+	}
+}
@@ -0,0 +1,90 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/sqlite"
+)
+
+var RunLocks = newRunLocksTable("", "run_locks", "")
+
+type runLocksTable struct {
+	sqlite.Table
+
+	// Columns
+	ServerID   sqlite.ColumnString
+	BoardName  sqlite.ColumnString
+	Owner      sqlite.ColumnString
+	AcquiredAt sqlite.ColumnTimestamp
+	ExpiresAt  sqlite.ColumnTimestamp
+
+	AllColumns     sqlite.ColumnList
+	MutableColumns sqlite.ColumnList
+	DefaultColumns sqlite.ColumnList
+}
+
+type RunLocksTable struct {
+	runLocksTable
+
+	EXCLUDED runLocksTable
+}
+
+// AS creates new RunLocksTable with assigned alias
+func (a RunLocksTable) AS(alias string) *RunLocksTable {
+	return newRunLocksTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new RunLocksTable with assigned schema name
+func (a RunLocksTable) FromSchema(schemaName string) *RunLocksTable {
+	return newRunLocksTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new RunLocksTable with assigned table prefix
+func (a RunLocksTable) WithPrefix(prefix string) *RunLocksTable {
+	return newRunLocksTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new RunLocksTable with assigned table suffix
+func (a RunLocksTable) WithSuffix(suffix string) *RunLocksTable {
+	return newRunLocksTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newRunLocksTable(schemaName, tableName, alias string) *RunLocksTable {
+	return &RunLocksTable{
+		runLocksTable: newRunLocksTableImpl(schemaName, tableName, alias),
+		EXCLUDED:      newRunLocksTableImpl("", "excluded", ""),
+	}
+}
+
+func newRunLocksTableImpl(schemaName, tableName, alias string) runLocksTable {
+	var (
+		ServerIDColumn   = sqlite.StringColumn("server_id")
+		BoardNameColumn  = sqlite.StringColumn("board_name")
+		OwnerColumn      = sqlite.StringColumn("owner")
+		AcquiredAtColumn = sqlite.TimestampColumn("acquired_at")
+		ExpiresAtColumn  = sqlite.TimestampColumn("expires_at")
+		allColumns       = sqlite.ColumnList{ServerIDColumn, BoardNameColumn, OwnerColumn, AcquiredAtColumn, ExpiresAtColumn}
+		mutableColumns   = sqlite.ColumnList{OwnerColumn, AcquiredAtColumn, ExpiresAtColumn}
+		defaultColumns   = sqlite.ColumnList{ServerIDColumn, BoardNameColumn, OwnerColumn}
+	)
+
+	return runLocksTable{
+		Table: sqlite.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		ServerID:   ServerIDColumn,
+		BoardName:  BoardNameColumn,
+		Owner:      OwnerColumn,
+		AcquiredAt: AcquiredAtColumn,
+		ExpiresAt:  ExpiresAtColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+		DefaultColumns: defaultColumns,
+	}
+}
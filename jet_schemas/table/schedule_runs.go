@@ -0,0 +1,96 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/sqlite"
+)
+
+var ScheduleRuns = newScheduleRunsTable("", "schedule_runs", "")
+
+type scheduleRunsTable struct {
+	sqlite.Table
+
+	// Columns
+	ServerID     sqlite.ColumnString
+	BoardName    sqlite.ColumnString
+	StartedAt    sqlite.ColumnTimestamp
+	DurationMs   sqlite.ColumnInteger
+	TriggeredBy  sqlite.ColumnString
+	Outcome      sqlite.ColumnString
+	ErrorMessage sqlite.ColumnString
+
+	AllColumns     sqlite.ColumnList
+	MutableColumns sqlite.ColumnList
+	DefaultColumns sqlite.ColumnList
+}
+
+type ScheduleRunsTable struct {
+	scheduleRunsTable
+
+	EXCLUDED scheduleRunsTable
+}
+
+// AS creates new ScheduleRunsTable with assigned alias
+func (a ScheduleRunsTable) AS(alias string) *ScheduleRunsTable {
+	return newScheduleRunsTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new ScheduleRunsTable with assigned schema name
+func (a ScheduleRunsTable) FromSchema(schemaName string) *ScheduleRunsTable {
+	return newScheduleRunsTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new ScheduleRunsTable with assigned table prefix
+func (a ScheduleRunsTable) WithPrefix(prefix string) *ScheduleRunsTable {
+	return newScheduleRunsTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new ScheduleRunsTable with assigned table suffix
+func (a ScheduleRunsTable) WithSuffix(suffix string) *ScheduleRunsTable {
+	return newScheduleRunsTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newScheduleRunsTable(schemaName, tableName, alias string) *ScheduleRunsTable {
+	return &ScheduleRunsTable{
+		scheduleRunsTable: newScheduleRunsTableImpl(schemaName, tableName, alias),
+		EXCLUDED:          newScheduleRunsTableImpl("", "excluded", ""),
+	}
+}
+
+func newScheduleRunsTableImpl(schemaName, tableName, alias string) scheduleRunsTable {
+	var (
+		ServerIDColumn     = sqlite.StringColumn("server_id")
+		BoardNameColumn    = sqlite.StringColumn("board_name")
+		StartedAtColumn    = sqlite.TimestampColumn("started_at")
+		DurationMsColumn   = sqlite.IntegerColumn("duration_ms")
+		TriggeredByColumn  = sqlite.StringColumn("triggered_by")
+		OutcomeColumn      = sqlite.StringColumn("outcome")
+		ErrorMessageColumn = sqlite.StringColumn("error_message")
+		allColumns         = sqlite.ColumnList{ServerIDColumn, BoardNameColumn, StartedAtColumn, DurationMsColumn, TriggeredByColumn, OutcomeColumn, ErrorMessageColumn}
+		mutableColumns     = sqlite.ColumnList{DurationMsColumn, TriggeredByColumn, OutcomeColumn, ErrorMessageColumn}
+		defaultColumns     = sqlite.ColumnList{ServerIDColumn, BoardNameColumn, DurationMsColumn, TriggeredByColumn, OutcomeColumn, ErrorMessageColumn}
+	)
+
+	return scheduleRunsTable{
+		Table: sqlite.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		ServerID:     ServerIDColumn,
+		BoardName:    BoardNameColumn,
+		StartedAt:    StartedAtColumn,
+		DurationMs:   DurationMsColumn,
+		TriggeredBy:  TriggeredByColumn,
+		Outcome:      OutcomeColumn,
+		ErrorMessage: ErrorMessageColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+		DefaultColumns: defaultColumns,
+	}
+}
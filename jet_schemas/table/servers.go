@@ -17,12 +17,21 @@ type serversTable struct {
 	sqlite.Table
 
 	// Columns
-	ID                sqlite.ColumnString
-	ServerName        sqlite.ColumnString
-	ChannelName       sqlite.ColumnString
-	Schedule          sqlite.ColumnString
-	ShouldEditMessage sqlite.ColumnBool
-	IsEnabled         sqlite.ColumnBool
+	ID                        sqlite.ColumnString
+	ServerName                sqlite.ColumnString
+	IsEnabled                 sqlite.ColumnBool
+	DisabledReason            sqlite.ColumnString
+	MemberLeaveAction         sqlite.ColumnString
+	RoleSyncEnabled           sqlite.ColumnBool
+	NicknameSyncEnabled       sqlite.ColumnBool
+	NicknameAccountTypePrefix sqlite.ColumnBool
+	RankReportSchedule        sqlite.ColumnString
+	Timezone                  sqlite.ColumnString
+	CatchUpEnabled            sqlite.ColumnBool
+	CatchUpMaxLatenessHours   sqlite.ColumnInteger
+	OrphanedMessageAction     sqlite.ColumnString
+	AdminChannelID            sqlite.ColumnString
+	StaleDataMaxAgeHours      sqlite.ColumnInteger
 
 	AllColumns     sqlite.ColumnList
 	MutableColumns sqlite.ColumnList
@@ -64,27 +73,45 @@ func newServersTable(schemaName, tableName, alias string) *ServersTable {
 
 func newServersTableImpl(schemaName, tableName, alias string) serversTable {
 	var (
-		IDColumn                = sqlite.StringColumn("id")
-		ServerNameColumn        = sqlite.StringColumn("server_name")
-		ChannelNameColumn       = sqlite.StringColumn("channel_name")
-		ScheduleColumn          = sqlite.StringColumn("schedule")
-		ShouldEditMessageColumn = sqlite.BoolColumn("should_edit_message")
-		IsEnabledColumn         = sqlite.BoolColumn("is_enabled")
-		allColumns              = sqlite.ColumnList{IDColumn, ServerNameColumn, ChannelNameColumn, ScheduleColumn, ShouldEditMessageColumn, IsEnabledColumn}
-		mutableColumns          = sqlite.ColumnList{ServerNameColumn, ChannelNameColumn, ScheduleColumn, ShouldEditMessageColumn, IsEnabledColumn}
-		defaultColumns          = sqlite.ColumnList{ServerNameColumn, ChannelNameColumn, ScheduleColumn, ShouldEditMessageColumn, IsEnabledColumn}
+		IDColumn                        = sqlite.StringColumn("id")
+		ServerNameColumn                = sqlite.StringColumn("server_name")
+		IsEnabledColumn                 = sqlite.BoolColumn("is_enabled")
+		DisabledReasonColumn            = sqlite.StringColumn("disabled_reason")
+		MemberLeaveActionColumn         = sqlite.StringColumn("member_leave_action")
+		RoleSyncEnabledColumn           = sqlite.BoolColumn("role_sync_enabled")
+		NicknameSyncEnabledColumn       = sqlite.BoolColumn("nickname_sync_enabled")
+		NicknameAccountTypePrefixColumn = sqlite.BoolColumn("nickname_account_type_prefix")
+		RankReportScheduleColumn        = sqlite.StringColumn("rank_report_schedule")
+		TimezoneColumn                  = sqlite.StringColumn("timezone")
+		CatchUpEnabledColumn            = sqlite.BoolColumn("catch_up_enabled")
+		CatchUpMaxLatenessHoursColumn   = sqlite.IntegerColumn("catch_up_max_lateness_hours")
+		OrphanedMessageActionColumn     = sqlite.StringColumn("orphaned_message_action")
+		AdminChannelIDColumn            = sqlite.StringColumn("admin_channel_id")
+		StaleDataMaxAgeHoursColumn      = sqlite.IntegerColumn("stale_data_max_age_hours")
+		allColumns                      = sqlite.ColumnList{IDColumn, ServerNameColumn, IsEnabledColumn, DisabledReasonColumn, MemberLeaveActionColumn, RoleSyncEnabledColumn, NicknameSyncEnabledColumn, NicknameAccountTypePrefixColumn, RankReportScheduleColumn, TimezoneColumn, CatchUpEnabledColumn, CatchUpMaxLatenessHoursColumn, OrphanedMessageActionColumn, AdminChannelIDColumn, StaleDataMaxAgeHoursColumn}
+		mutableColumns                  = sqlite.ColumnList{ServerNameColumn, IsEnabledColumn, DisabledReasonColumn, MemberLeaveActionColumn, RoleSyncEnabledColumn, NicknameSyncEnabledColumn, NicknameAccountTypePrefixColumn, RankReportScheduleColumn, TimezoneColumn, CatchUpEnabledColumn, CatchUpMaxLatenessHoursColumn, OrphanedMessageActionColumn, AdminChannelIDColumn, StaleDataMaxAgeHoursColumn}
+		defaultColumns                  = sqlite.ColumnList{ServerNameColumn, IsEnabledColumn, DisabledReasonColumn, MemberLeaveActionColumn, RoleSyncEnabledColumn, NicknameSyncEnabledColumn, NicknameAccountTypePrefixColumn, RankReportScheduleColumn, TimezoneColumn, CatchUpEnabledColumn, CatchUpMaxLatenessHoursColumn, OrphanedMessageActionColumn, AdminChannelIDColumn, StaleDataMaxAgeHoursColumn}
 	)
 
 	return serversTable{
 		Table: sqlite.NewTable(schemaName, tableName, alias, allColumns...),
 
 		//Columns
-		ID:                IDColumn,
-		ServerName:        ServerNameColumn,
-		ChannelName:       ChannelNameColumn,
-		Schedule:          ScheduleColumn,
-		ShouldEditMessage: ShouldEditMessageColumn,
-		IsEnabled:         IsEnabledColumn,
+		ID:                        IDColumn,
+		ServerName:                ServerNameColumn,
+		IsEnabled:                 IsEnabledColumn,
+		DisabledReason:            DisabledReasonColumn,
+		MemberLeaveAction:         MemberLeaveActionColumn,
+		RoleSyncEnabled:           RoleSyncEnabledColumn,
+		NicknameSyncEnabled:       NicknameSyncEnabledColumn,
+		NicknameAccountTypePrefix: NicknameAccountTypePrefixColumn,
+		RankReportSchedule:        RankReportScheduleColumn,
+		Timezone:                  TimezoneColumn,
+		CatchUpEnabled:            CatchUpEnabledColumn,
+		CatchUpMaxLatenessHours:   CatchUpMaxLatenessHoursColumn,
+		OrphanedMessageAction:     OrphanedMessageActionColumn,
+		AdminChannelID:            AdminChannelIDColumn,
+		StaleDataMaxAgeHours:      StaleDataMaxAgeHoursColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
@@ -10,7 +10,15 @@ package table
 // UseSchema sets a new schema name for all generated table SQL builder types. It is recommended to invoke
 // this method only once at the beginning of the program.
 func UseSchema(schema string) {
+	Boards = Boards.FromSchema(schema)
+	HiscoresSnapshots = HiscoresSnapshots.FromSchema(schema)
 	Messages = Messages.FromSchema(schema)
+	Ranks = Ranks.FromSchema(schema)
+	RoleRules = RoleRules.FromSchema(schema)
+	RunFetchFailures = RunFetchFailures.FromSchema(schema)
+	RunLocks = RunLocks.FromSchema(schema)
+	ScheduleRuns = ScheduleRuns.FromSchema(schema)
+	SchemaMigrations = SchemaMigrations.FromSchema(schema)
 	Servers = Servers.FromSchema(schema)
 	Users = Users.FromSchema(schema)
 }
@@ -23,6 +23,10 @@ type usersTable struct {
 	OsrsAccountType sqlite.ColumnString
 	DiscordUsername sqlite.ColumnString
 	DiscordUserID   sqlite.ColumnString
+	IsArchived      sqlite.ColumnBool
+	PossiblyRenamed sqlite.ColumnBool
+	IsPrimary       sqlite.ColumnBool
+	ClanRank        sqlite.ColumnString
 
 	AllColumns     sqlite.ColumnList
 	MutableColumns sqlite.ColumnList
@@ -70,9 +74,13 @@ func newUsersTableImpl(schemaName, tableName, alias string) usersTable {
 		OsrsAccountTypeColumn = sqlite.StringColumn("osrs_account_type")
 		DiscordUsernameColumn = sqlite.StringColumn("discord_username")
 		DiscordUserIDColumn   = sqlite.StringColumn("discord_user_id")
-		allColumns            = sqlite.ColumnList{OsrsUsernameKeyColumn, ServerIDColumn, OsrsUsernameColumn, OsrsAccountTypeColumn, DiscordUsernameColumn, DiscordUserIDColumn}
-		mutableColumns        = sqlite.ColumnList{OsrsUsernameColumn, OsrsAccountTypeColumn, DiscordUsernameColumn, DiscordUserIDColumn}
-		defaultColumns        = sqlite.ColumnList{OsrsUsernameKeyColumn, ServerIDColumn, OsrsUsernameColumn, OsrsAccountTypeColumn, DiscordUsernameColumn, DiscordUserIDColumn}
+		IsArchivedColumn      = sqlite.BoolColumn("is_archived")
+		PossiblyRenamedColumn = sqlite.BoolColumn("possibly_renamed")
+		IsPrimaryColumn       = sqlite.BoolColumn("is_primary")
+		ClanRankColumn        = sqlite.StringColumn("clan_rank")
+		allColumns            = sqlite.ColumnList{OsrsUsernameKeyColumn, ServerIDColumn, OsrsUsernameColumn, OsrsAccountTypeColumn, DiscordUsernameColumn, DiscordUserIDColumn, IsArchivedColumn, PossiblyRenamedColumn, IsPrimaryColumn, ClanRankColumn}
+		mutableColumns        = sqlite.ColumnList{OsrsUsernameColumn, OsrsAccountTypeColumn, DiscordUsernameColumn, DiscordUserIDColumn, IsArchivedColumn, PossiblyRenamedColumn, IsPrimaryColumn, ClanRankColumn}
+		defaultColumns        = sqlite.ColumnList{OsrsUsernameKeyColumn, ServerIDColumn, OsrsUsernameColumn, OsrsAccountTypeColumn, DiscordUsernameColumn, DiscordUserIDColumn, IsArchivedColumn, PossiblyRenamedColumn, IsPrimaryColumn, ClanRankColumn}
 	)
 
 	return usersTable{
@@ -85,6 +93,10 @@ func newUsersTableImpl(schemaName, tableName, alias string) usersTable {
 		OsrsAccountType: OsrsAccountTypeColumn,
 		DiscordUsername: DiscordUsernameColumn,
 		DiscordUserID:   DiscordUserIDColumn,
+		IsArchived:      IsArchivedColumn,
+		PossiblyRenamed: PossiblyRenamedColumn,
+		IsPrimary:       IsPrimaryColumn,
+		ClanRank:        ClanRankColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
@@ -2,13 +2,129 @@
 package main
 
 import (
+	"fmt"
+	"log"
+	"os"
+	// Our container image doesn't ship timezone data and servers can pick any timezone
+	_ "time/tzdata"
+
 	"github.com/michohl/osrs-clan-leaderboard/discord"
 	"github.com/michohl/osrs-clan-leaderboard/schedule"
+	"github.com/michohl/osrs-clan-leaderboard/storage"
 )
 
 func main() {
+	// Copying needs both databases open at once so it manages its own stores
+	if len(os.Args) > 1 && os.Args[1] == "copy-sqlite" {
+		copySQLiteCommand(os.Args[2:])
+		return
+	}
+
+	store, err := openStore()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer store.Close()
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			migrateCommand(store, os.Args[2:])
+		default:
+			log.Fatalf("Unknown command %s. Available commands: migrate, copy-sqlite", os.Args[1])
+		}
+		return
+	}
+
+	// Make sure the schema is up to date before anything touches the database
+	err = store.Migrate()
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	schedule.Cron.Start()
 
 	// Listen for requests from Discord
-	discord.StartBotListener()
+	discord.StartBotListener(store)
+}
+
+// openStore connects to Postgres when DATABASE_URL is set and
+// otherwise falls back to the SQLite file at DB_FILE_PATH
+func openStore() (storage.Store, error) {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn != "" {
+		return storage.NewPostgresStore(dsn)
+	}
+
+	return storage.NewSQLiteStore(os.Getenv("DB_FILE_PATH"))
+}
+
+// migrateCommand lets an operator inspect and apply schema migrations
+// without starting the bot e.g. `osrs-clan-leaderboard migrate status`
+func migrateCommand(store storage.Store, args []string) {
+	action := "status"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	switch action {
+	case "status":
+		states, err := store.MigrationStatus()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		for _, state := range states {
+			appliedAt := "pending"
+			if state.AppliedAt != nil {
+				appliedAt = "applied " + state.AppliedAt.Format("2006-01-02 15:04:05 MST")
+			}
+			fmt.Printf("%04d_%s\t%s\n", state.Version, state.Name, appliedAt)
+		}
+	case "up":
+		err := store.Migrate()
+		if err != nil {
+			log.Fatal(err)
+		}
+	default:
+		log.Fatalf("Unknown migrate action %s. Available actions: status, up", action)
+	}
+}
+
+// copySQLiteCommand copies an existing SQLite database into the Postgres
+// database at DATABASE_URL e.g. `osrs-clan-leaderboard copy-sqlite ./bot.db`
+func copySQLiteCommand(args []string) {
+	if len(args) != 1 {
+		log.Fatal("Usage: osrs-clan-leaderboard copy-sqlite <path to sqlite database>")
+	}
+
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		log.Fatal("DATABASE_URL must be set to the Postgres database to copy into")
+	}
+
+	// Opening a SQLite file that doesn't exist would silently create an empty one
+	_, err := os.Stat(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src, err := storage.NewSQLiteStore(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer src.Close()
+
+	dst, err := storage.NewPostgresStore(dsn)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer dst.Close()
+
+	err = storage.CopySQLiteToPostgres(src, dst)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("Copied %s into Postgres\n", args[0])
 }
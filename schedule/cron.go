@@ -1,6 +1,10 @@
 package schedule
 
 import (
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/michohl/osrs-clan-leaderboard/types"
 	"github.com/robfig/cron/v3"
 )
@@ -11,11 +15,166 @@ var (
 	// for their scheduled hiscore updates
 	Cron *cron.Cron
 
-	// ScheduledJobs is how we keep track of all of our scheduled jobs
-	// in our running process so we can manage their lifecycle
-	ScheduledJobs map[string]types.CronSchedule = make(map[string]types.CronSchedule)
+	// jobs is how we keep track of all of our scheduled jobs in our running
+	// process so we can manage their lifecycle. Discord handlers run
+	// concurrently so the maps are only ever used through the functions below
+	jobs = struct {
+		sync.Mutex
+
+		// boards has each board's hiscores job keyed by BoardJobKey
+		boards map[string]types.CronSchedule
+
+		// rankReports has each server's rank promotion report job keyed by server ID
+		rankReports map[string]types.CronSchedule
+	}{
+		boards:      map[string]types.CronSchedule{},
+		rankReports: map[string]types.CronSchedule{},
+	}
 )
 
+// BoardJobKey is the key of a board's hiscores job
+func BoardJobKey(serverID string, boardName string) string {
+	return serverID + "/" + boardName
+}
+
+// SetBoardJob keeps track of a board's hiscores job. A job the board
+// already had is removed from Cron so the board isn't posted twice
+func SetBoardJob(serverID string, boardName string, job types.CronSchedule) {
+	jobs.Lock()
+	defer jobs.Unlock()
+
+	key := BoardJobKey(serverID, boardName)
+	if previous, ok := jobs.boards[key]; ok {
+		Cron.Remove(previous.JobID)
+	}
+	jobs.boards[key] = job
+}
+
+// BoardJob returns a board's hiscores job if it has one
+func BoardJob(serverID string, boardName string) (types.CronSchedule, bool) {
+	jobs.Lock()
+	defer jobs.Unlock()
+
+	job, ok := jobs.boards[BoardJobKey(serverID, boardName)]
+	return job, ok
+}
+
+// RemoveBoardJob removes a board's hiscores job from Cron and
+// returns it. ok is false if the board wasn't scheduled
+func RemoveBoardJob(serverID string, boardName string) (job types.CronSchedule, ok bool) {
+	jobs.Lock()
+	defer jobs.Unlock()
+
+	key := BoardJobKey(serverID, boardName)
+	job, ok = jobs.boards[key]
+	if !ok {
+		return job, false
+	}
+
+	Cron.Remove(job.JobID)
+	delete(jobs.boards, key)
+
+	return job, true
+}
+
+// RemoveServerJobs removes the hiscores job of every board in a server
+// from Cron and returns the removed jobs keyed by board name
+func RemoveServerJobs(serverID string) map[string]types.CronSchedule {
+	jobs.Lock()
+	defer jobs.Unlock()
+
+	removed := map[string]types.CronSchedule{}
+	for key, job := range jobs.boards {
+		boardServerID, boardName, _ := strings.Cut(key, "/")
+		if boardServerID != serverID {
+			continue
+		}
+
+		Cron.Remove(job.JobID)
+		delete(jobs.boards, key)
+		removed[boardName] = job
+	}
+
+	return removed
+}
+
+// SetRankReportJob keeps track of a server's rank report job. A job the
+// server already had is removed from Cron so the report isn't posted twice
+func SetRankReportJob(serverID string, job types.CronSchedule) {
+	jobs.Lock()
+	defer jobs.Unlock()
+
+	if previous, ok := jobs.rankReports[serverID]; ok {
+		Cron.Remove(previous.JobID)
+	}
+	jobs.rankReports[serverID] = job
+}
+
+// RemoveRankReportJob removes a server's rank report job from Cron and
+// returns it. ok is false if the server didn't have one
+func RemoveRankReportJob(serverID string) (job types.CronSchedule, ok bool) {
+	jobs.Lock()
+	defer jobs.Unlock()
+
+	job, ok = jobs.rankReports[serverID]
+	if !ok {
+		return job, false
+	}
+
+	Cron.Remove(job.JobID)
+	delete(jobs.rankReports, serverID)
+
+	return job, true
+}
+
+// WithTimezone prefixes a cron expression with the timezone it should
+// run in. Cron takes care of daylight saving time transitions for us.
+// Expressions that already name a timezone are left alone
+func WithTimezone(expression string, timezone string) string {
+	if timezone == "" || strings.HasPrefix(expression, "TZ=") || strings.HasPrefix(expression, "CRON_TZ=") {
+		return expression
+	}
+
+	return "CRON_TZ=" + timezone + " " + expression
+}
+
+// NextRuns returns the next n times a cron expression will run in a timezone
+func NextRuns(expression string, timezone string, n int) ([]time.Time, error) {
+	sched, err := cron.ParseStandard(WithTimezone(expression, timezone))
+	if err != nil {
+		return nil, err
+	}
+
+	runs := []time.Time{}
+	next := time.Now()
+	for range n {
+		next = sched.Next(next)
+		if next.IsZero() {
+			break
+		}
+		runs = append(runs, next)
+	}
+
+	return runs, nil
+}
+
+// LastRunBetween returns the latest time a cron expression should have run
+// after since and up to until. It returns the zero time if it shouldn't have
+// run at all in that window
+func LastRunBetween(expression string, timezone string, since time.Time, until time.Time) (time.Time, error) {
+	sched, err := cron.ParseStandard(WithTimezone(expression, timezone))
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	last := time.Time{}
+	for next := sched.Next(since); !next.IsZero() && !next.After(until); next = sched.Next(next) {
+		last = next
+	}
+
+	return last, nil
+}
+
 func init() {
 	Cron = cron.New()
 
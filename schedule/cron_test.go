@@ -0,0 +1,202 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithTimezone(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		timezone   string
+		want       string
+	}{
+		{name: "no timezone", expression: "0 19 * * SUN", timezone: "", want: "0 19 * * SUN"},
+		{name: "timezone added", expression: "0 19 * * SUN", timezone: "Europe/London", want: "CRON_TZ=Europe/London 0 19 * * SUN"},
+		{name: "CRON_TZ kept", expression: "CRON_TZ=America/New_York 0 19 * * SUN", timezone: "Europe/London", want: "CRON_TZ=America/New_York 0 19 * * SUN"},
+		{name: "TZ kept", expression: "TZ=America/New_York 0 19 * * SUN", timezone: "Europe/London", want: "TZ=America/New_York 0 19 * * SUN"},
+		{name: "descriptor", expression: "@daily", timezone: "Europe/London", want: "CRON_TZ=Europe/London @daily"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WithTimezone(tt.expression, tt.timezone); got != tt.want {
+				t.Errorf("WithTimezone() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextRuns(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		timezone   string
+		location   *time.Location
+	}{
+		{name: "server timezone", expression: "0 19 * * *", timezone: "Europe/London", location: london},
+		{name: "CRON_TZ beats the server timezone", expression: "CRON_TZ=America/New_York 0 19 * * *", timezone: "Europe/London", location: newYork},
+		{name: "no timezone is local time", expression: "0 19 * * *", timezone: "", location: time.Local},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs, err := NextRuns(tt.expression, tt.timezone, 3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(runs) != 3 {
+				t.Fatalf("got %d runs, want 3", len(runs))
+			}
+
+			for i, run := range runs {
+				// Whatever the offset is at the time, the run is at 19:00 local time
+				if local := run.In(tt.location); local.Hour() != 19 || local.Minute() != 0 {
+					t.Errorf("run %d is at %s", i, local)
+				}
+				if i > 0 && run.Sub(runs[i-1]) > 25*time.Hour {
+					t.Errorf("runs %d and %d are %s apart", i-1, i, run.Sub(runs[i-1]))
+				}
+			}
+		})
+	}
+
+	_, err = NextRuns("not a schedule", "Europe/London", 3)
+	if err == nil {
+		t.Error("an invalid expression wasn't rejected")
+	}
+}
+
+func TestLastRunBetween(t *testing.T) {
+	at := func(value string) time.Time {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		timezone   string
+		since      string
+		until      string
+		want       string
+	}{
+		{
+			name:       "no run in the window",
+			expression: "0 19 * * SUN",
+			timezone:   "UTC",
+			since:      "2026-01-05T00:00:00Z",
+			until:      "2026-01-10T00:00:00Z",
+		},
+		{
+			name:       "latest of several runs",
+			expression: "0 19 * * *",
+			timezone:   "UTC",
+			since:      "2026-01-05T00:00:00Z",
+			until:      "2026-01-10T00:00:00Z",
+			want:       "2026-01-09T19:00:00Z",
+		},
+		{
+			name:       "a run at since isn't counted",
+			expression: "0 19 * * *",
+			timezone:   "UTC",
+			since:      "2026-01-05T19:00:00Z",
+			until:      "2026-01-06T12:00:00Z",
+		},
+		{
+			name:       "a run at until is counted",
+			expression: "0 19 * * *",
+			timezone:   "UTC",
+			since:      "2026-01-05T12:00:00Z",
+			until:      "2026-01-05T19:00:00Z",
+			want:       "2026-01-05T19:00:00Z",
+		},
+		{
+			name:       "runs in the server timezone",
+			expression: "0 19 * * SUN",
+			timezone:   "America/New_York",
+			since:      "2026-01-10T00:00:00Z",
+			until:      "2026-01-13T00:00:00Z",
+			want:       "2026-01-12T00:00:00Z",
+		},
+		{
+			name:       "CRON_TZ beats the server timezone",
+			expression: "CRON_TZ=America/New_York 0 19 * * SUN",
+			timezone:   "Europe/London",
+			since:      "2026-01-10T00:00:00Z",
+			until:      "2026-01-13T00:00:00Z",
+			want:       "2026-01-12T00:00:00Z",
+		},
+		{
+			name:       "same local time after the clocks go forward",
+			expression: "0 19 * * SUN",
+			timezone:   "Europe/London",
+			since:      "2026-03-28T00:00:00Z",
+			until:      "2026-03-30T00:00:00Z",
+			want:       "2026-03-29T18:00:00Z",
+		},
+		{
+			name:       "same local time after the clocks go back",
+			expression: "0 19 * * SUN",
+			timezone:   "Europe/London",
+			since:      "2026-10-24T00:00:00Z",
+			until:      "2026-10-26T00:00:00Z",
+			want:       "2026-10-25T19:00:00Z",
+		},
+		{
+			name:       "a time skipped when the clocks go forward doesn't run",
+			expression: "30 1 * * *",
+			timezone:   "Europe/London",
+			since:      "2026-03-28T12:00:00Z",
+			until:      "2026-03-29T12:00:00Z",
+		},
+		{
+			name:       "a time repeated when the clocks go back runs the first time",
+			expression: "30 1 * * *",
+			timezone:   "Europe/London",
+			since:      "2026-10-24T12:00:00Z",
+			until:      "2026-10-25T01:00:00Z",
+			want:       "2026-10-25T00:30:00Z",
+		},
+		{
+			name:       "and again the second time",
+			expression: "30 1 * * *",
+			timezone:   "Europe/London",
+			since:      "2026-10-25T01:00:00Z",
+			until:      "2026-10-25T12:00:00Z",
+			want:       "2026-10-25T01:30:00Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LastRunBetween(tt.expression, tt.timezone, at(tt.since), at(tt.until))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.want == "" {
+				if !got.IsZero() {
+					t.Errorf("LastRunBetween() = %s, want no run", got.UTC())
+				}
+				return
+			}
+			if !got.Equal(at(tt.want)) {
+				t.Errorf("LastRunBetween() = %s, want %s", got.UTC(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+	pgtable "github.com/michohl/osrs-clan-leaderboard/jet_schemas/postgres/table"
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/table"
+)
+
+// postgresMaxParameters is the most bind parameters Postgres allows in one statement
+const postgresMaxParameters = 65535
+
+// CopySQLiteToPostgres copies every row of an existing SQLite database into
+// a Postgres database. Both databases are migrated to the latest schema first
+// and every row is copied in a single transaction so a failure copies nothing.
+// Run locks are left out since they only matter to runs that are in progress.
+// The Postgres database is expected to be empty
+func CopySQLiteToPostgres(src *SQLiteStore, dst *PostgresStore) error {
+	err := src.Migrate()
+	if err != nil {
+		return fmt.Errorf("Unable to migrate SQLite database: %w", err)
+	}
+
+	err = dst.Migrate()
+	if err != nil {
+		return fmt.Errorf("Unable to migrate Postgres database: %w", err)
+	}
+
+	var servers []model.Servers
+	err = table.Servers.SELECT(table.Servers.AllColumns).Query(src.conn, &servers)
+	if err != nil {
+		return err
+	}
+
+	var boards []model.Boards
+	err = table.Boards.SELECT(table.Boards.AllColumns).Query(src.conn, &boards)
+	if err != nil {
+		return err
+	}
+
+	// FetchAllUsers leaves out archived users so we read the table directly
+	var users []model.Users
+	err = table.Users.SELECT(table.Users.AllColumns).Query(src.conn, &users)
+	if err != nil {
+		return err
+	}
+
+	var messages []model.Messages
+	err = table.Messages.SELECT(table.Messages.AllColumns).Query(src.conn, &messages)
+	if err != nil {
+		return err
+	}
+
+	var roleRules []model.RoleRules
+	err = table.RoleRules.SELECT(table.RoleRules.AllColumns).Query(src.conn, &roleRules)
+	if err != nil {
+		return err
+	}
+
+	var ranks []model.Ranks
+	err = table.Ranks.SELECT(table.Ranks.AllColumns).Query(src.conn, &ranks)
+	if err != nil {
+		return err
+	}
+
+	var scheduleRuns []model.ScheduleRuns
+	err = table.ScheduleRuns.SELECT(table.ScheduleRuns.AllColumns).Query(src.conn, &scheduleRuns)
+	if err != nil {
+		return err
+	}
+
+	var runFetchFailures []model.RunFetchFailures
+	err = table.RunFetchFailures.SELECT(table.RunFetchFailures.AllColumns).Query(src.conn, &runFetchFailures)
+	if err != nil {
+		return err
+	}
+
+	var snapshots []model.HiscoresSnapshots
+	err = table.HiscoresSnapshots.SELECT(table.HiscoresSnapshots.AllColumns).Query(src.conn, &snapshots)
+	if err != nil {
+		return err
+	}
+
+	return dst.inTransaction(func(txStore *PostgresStore) error {
+		err := copyRows(txStore, "servers", pgtable.Servers, pgtable.Servers.AllColumns, servers)
+		if err != nil {
+			return err
+		}
+
+		err = copyRows(txStore, "boards", pgtable.Boards, pgtable.Boards.AllColumns, boards)
+		if err != nil {
+			return err
+		}
+
+		err = copyRows(txStore, "users", pgtable.Users, pgtable.Users.AllColumns, users)
+		if err != nil {
+			return err
+		}
+
+		err = copyRows(txStore, "messages", pgtable.Messages, pgtable.Messages.AllColumns, messages)
+		if err != nil {
+			return err
+		}
+
+		err = copyRows(txStore, "role rules", pgtable.RoleRules, pgtable.RoleRules.AllColumns, roleRules)
+		if err != nil {
+			return err
+		}
+
+		err = copyRows(txStore, "ranks", pgtable.Ranks, pgtable.Ranks.AllColumns, ranks)
+		if err != nil {
+			return err
+		}
+
+		err = copyRows(txStore, "schedule runs", pgtable.ScheduleRuns, pgtable.ScheduleRuns.AllColumns, scheduleRuns)
+		if err != nil {
+			return err
+		}
+
+		err = copyRows(txStore, "fetch failures", pgtable.RunFetchFailures, pgtable.RunFetchFailures.AllColumns, runFetchFailures)
+		if err != nil {
+			return err
+		}
+
+		return copyRows(txStore, "hiscores snapshots", pgtable.HiscoresSnapshots, pgtable.HiscoresSnapshots.AllColumns, snapshots)
+	})
+}
+
+// copyRows inserts rows into a Postgres table. Postgres allows at most
+// postgresMaxParameters bind parameters in a statement so big tables
+// are inserted a batch at a time
+func copyRows[T any](txStore *PostgresStore, name string, table postgres.Table, columns postgres.ColumnList, rows []T) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	log.Printf("Copying %d %s\n", len(rows), name)
+	for batch := range slices.Chunk(rows, postgresMaxParameters/len(columns)) {
+		_, err := table.INSERT(columns).MODELS(batch).Exec(txStore.conn)
+		if err != nil {
+			return fmt.Errorf("Unable to copy %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
@@ -2,127 +2,252 @@ package storage
 
 import (
 	"database/sql"
+	"fmt"
 	"log"
-	"os"
-	"slices"
-	"strings"
+	"time"
 
 	// https://github.com/mattn/go-sqlite3/issues/335
 	_ "github.com/mattn/go-sqlite3"
 
+	"github.com/go-jet/jet/v2/qrm"
 	"github.com/go-jet/jet/v2/sqlite"
 	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
 	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/table"
+	"github.com/michohl/osrs-clan-leaderboard/types"
 )
 
-var (
-	// DBFilePath is the user configured path to the SQLite DB file on disk
-	DBFilePath = os.Getenv("DB_FILE_PATH")
-)
+// SQLiteStore is a Store backed by a SQLite database file on disk. It keeps
+// a single long lived connection pool open for the lifetime of the process
+type SQLiteStore struct {
+	db *sql.DB
 
-func init() {
-	db, err := sql.Open("sqlite3", DBFilePath)
-	if err != nil {
-		panic(err)
-	}
-	defer db.Close()
-
-	sqlStmt := `
-    CREATE TABLE IF NOT EXISTS servers (
-        id                  TEXT    NOT NULL PRIMARY KEY,
-        server_name         TEXT    NOT NULL DEFAULT "",
-		channel_name        TEXT    NOT NULL DEFAULT "",
-		schedule            TEXT    NOT NULL DEFAULT "",
-		should_edit_message BOOLEAN NOT NULL DEFAULT true,
-		is_enabled          BOOLEAN NOT NULL DEFAULT true
-    );
-    CREATE TABLE IF NOT EXISTS users (
-		osrs_username_key TEXT NOT NULL DEFAULT "",
-        server_id         TEXT NOT NULL DEFAULT "",
-		osrs_username     TEXT NOT NULL DEFAULT "",
-		osrs_account_type TEXT NOT NULL DEFAULT "",
-		discord_username  TEXT NOT NULL DEFAULT "",
-		discord_user_id   TEXT NOT NULL DEFAULT "",
-		PRIMARY KEY (osrs_username_key, server_id)
-    );
-	CREATE TABLE IF NOT EXISTS messages (
-		message_id        TEXT    NOT NULL DEFAULT "",
-		server_id         TEXT    NOT NULL DEFAULT "",
-		activity          TEXT    NOT NULL DEFAULT "",
-		position          INTEGER NOT NULL DEFAULT 0,
-		PRIMARY KEY (message_id, server_id, activity)
-	);
-    `
-	_, err = db.Exec(sqlStmt)
-	if err != nil {
-		log.Fatal(err)
-	}
-	log.Println("All tables created successfully")
-}
-
-// EnrollServer takes form data from our enrollment survey and
-// commits that data to our database
-func EnrollServer(server model.Servers, activities string) error {
-	log.Printf("Request received to enroll server: %s (ID: %s)\n", server.ServerName, server.ID)
+	// conn is what every query runs against. It is db itself
+	// except inside of inTransaction where it is the open transaction
+	conn qrm.DB
+}
+
+// NewSQLiteStore opens (or creates) the SQLite database at dbFilePath.
+// The database is opened in WAL mode so readers don't block the writer,
+// with a busy timeout so concurrent writers wait instead of failing and
+// with foreign keys enforced
+func NewSQLiteStore(dbFilePath string) (*SQLiteStore, error) {
+	dsn := fmt.Sprintf(
+		"file:%s?_journal_mode=WAL&_busy_timeout=5000&_foreign_keys=on&_txlock=immediate",
+		dbFilePath,
+	)
+
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	// sql.Open doesn't actually connect so make sure the file is usable now
+	// rather than on the first command someone runs
+	err = db.Ping()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &SQLiteStore{db: db, conn: db}, nil
+}
+
+// Close closes the underlying database
+func (store *SQLiteStore) Close() error {
+	return store.db.Close()
+}
 
-	db, err := sql.Open("sqlite3", DBFilePath)
+// inTransaction runs fn with a copy of the store whose queries all run in
+// a single transaction. The transaction is committed if fn succeeds and
+// rolled back if it returns an error so nothing is ever half applied
+func (store *SQLiteStore) inTransaction(fn func(txStore *SQLiteStore) error) error {
+	tx, err := store.db.Begin()
 	if err != nil {
 		return err
 	}
-	defer db.Close()
+	defer tx.Rollback()
 
-	// This design makes it so each server (guild) can only have one
-	// channel configured. If we want to allow multiple channels per server
-	// then the server ID needs to be a separate column from id
-	sqlStmt := table.Servers.
-		INSERT(table.Servers.AllColumns).
+	err = fn(&SQLiteStore{db: store.db, conn: tx})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// EnrollBoard takes form data from our enrollment survey and commits
+// that data to our database. The server is enrolled along with the
+// board so the first board configured in a server enrolls it
+func (store *SQLiteStore) EnrollBoard(server model.Servers, board model.Boards, activities string) error {
+	log.Printf("Request received to enroll board %s for server: %s (ID: %s)\n", board.BoardName, server.ServerName, server.ID)
+
+	// Only the columns managed by /configure are written here so settings
+	// managed elsewhere (e.g. /settings) keep their values or defaults
+	serverStmt := table.Servers.
+		INSERT(
+			table.Servers.ID,
+			table.Servers.ServerName,
+			table.Servers.IsEnabled,
+			table.Servers.DisabledReason,
+			table.Servers.Timezone,
+		).
 		MODEL(server).
 		ON_CONFLICT(table.Servers.ID).
 		DO_UPDATE(
 			sqlite.SET(
-				table.Servers.ID.SET(sqlite.String(server.ID)),
 				table.Servers.ServerName.SET(sqlite.String(server.ServerName)),
-				table.Servers.ChannelName.SET(sqlite.String(server.ChannelName)),
-				table.Servers.Schedule.SET(sqlite.String(server.Schedule)),
-				table.Servers.ShouldEditMessage.SET(sqlite.Bool(server.ShouldEditMessage)),
 				table.Servers.IsEnabled.SET(sqlite.Bool(server.IsEnabled)),
+				table.Servers.DisabledReason.SET(sqlite.String(server.DisabledReason)),
+				table.Servers.Timezone.SET(sqlite.String(server.Timezone)),
 			),
 		)
 
-	_, err = sqlStmt.Exec(db)
+	boardStmt := table.Boards.
+		INSERT(table.Boards.AllColumns).
+		MODEL(board).
+		ON_CONFLICT(table.Boards.ServerID, table.Boards.BoardName).
+		DO_UPDATE(
+			sqlite.SET(
+				table.Boards.ChannelID.SET(sqlite.String(board.ChannelID)),
+				table.Boards.ChannelName.SET(sqlite.String(board.ChannelName)),
+				table.Boards.Schedule.SET(sqlite.String(board.Schedule)),
+				table.Boards.ShouldEditMessage.SET(sqlite.Bool(board.ShouldEditMessage)),
+				table.Boards.HideUnranked.SET(sqlite.Bool(board.HideUnranked)),
+				table.Boards.ShowRank.SET(sqlite.Bool(board.ShowRank)),
+			),
+		)
+
+	// The server, the board and its activity list are written in one
+	// transaction so a failure part way through changes nothing
+	return store.inTransaction(func(txStore *SQLiteStore) error {
+		_, err := serverStmt.Exec(txStore.conn)
+		if err != nil {
+			return err
+		}
+
+		_, err = boardStmt.Exec(txStore.conn)
+		if err != nil {
+			return err
+		}
+
+		return syncActivityMessages(txStore, board, activities)
+	})
+}
+
+// FetchBoards returns every board configured in a server ordered by name
+func (store *SQLiteStore) FetchBoards(serverID string) ([]model.Boards, error) {
+	sqlStmt := table.Boards.
+		SELECT(table.Boards.AllColumns).
+		WHERE(table.Boards.ServerID.EQ(sqlite.String(serverID))).
+		ORDER_BY(table.Boards.BoardName)
+
+	var b []model.Boards
+	err := sqlStmt.Query(store.conn, &b)
 	if err != nil {
-		return err
+		return []model.Boards{}, err
 	}
 
-	newActivities := strings.Split(activities, ",")
+	return b, nil
+}
 
-	existingActivityMessages, err := FetchAllMessages(server.ID)
+// FetchBoard returns a single board from a server
+func (store *SQLiteStore) FetchBoard(serverID string, boardName string) (model.Boards, error) {
+	sqlStmt := table.Boards.
+		SELECT(table.Boards.AllColumns).
+		WHERE(table.Boards.ServerID.
+			EQ(sqlite.String(serverID)).
+			AND(table.Boards.BoardName.EQ(sqlite.String(boardName))),
+		)
+
+	var b model.Boards
+	err := sqlStmt.Query(store.conn, &b)
 	if err != nil {
-		return err
+		return model.Boards{}, err
 	}
 
-	for _, m := range existingActivityMessages {
-		if !slices.Contains(newActivities, m.Activity) {
-			RemoveMessage(m)
+	return b, nil
+}
+
+// RemoveBoard removes a board along with every message we posted for it
+// and its run history
+func (store *SQLiteStore) RemoveBoard(serverID string, boardName string) error {
+	log.Printf("Request received to remove board %s from server %s\n", boardName, serverID)
+
+	return store.inTransaction(func(txStore *SQLiteStore) error {
+		_, err := table.Messages.
+			DELETE().
+			WHERE(table.Messages.ServerID.
+				EQ(sqlite.String(serverID)).
+				AND(table.Messages.BoardName.EQ(sqlite.String(boardName))),
+			).
+			Exec(txStore.conn)
+		if err != nil {
+			return err
 		}
-	}
 
-	for position, activity := range newActivities {
+		_, err = table.ScheduleRuns.
+			DELETE().
+			WHERE(table.ScheduleRuns.ServerID.
+				EQ(sqlite.String(serverID)).
+				AND(table.ScheduleRuns.BoardName.EQ(sqlite.String(boardName))),
+			).
+			Exec(txStore.conn)
+		if err != nil {
+			return err
+		}
 
-		currentPosition, err := FetchActivityPosition(server.ID, activity)
+		_, err = table.RunFetchFailures.
+			DELETE().
+			WHERE(table.RunFetchFailures.ServerID.
+				EQ(sqlite.String(serverID)).
+				AND(table.RunFetchFailures.BoardName.EQ(sqlite.String(boardName))),
+			).
+			Exec(txStore.conn)
+		if err != nil {
+			return err
+		}
 
-		// If we can't find a message or if the position changed create/update the message row
-		if err != nil || currentPosition == -1 || int32(position) != currentPosition {
-			err = EnrollMessage(server, model.Messages{
-				MessageID: "",
-				ServerID:  server.ID,
-				Activity:  activity,
-				Position:  int32(position),
-			})
-			if err != nil {
-				return err
-			}
+		// A board created with the same name later on shouldn't be locked out
+		_, err = table.RunLocks.
+			DELETE().
+			WHERE(table.RunLocks.ServerID.
+				EQ(sqlite.String(serverID)).
+				AND(table.RunLocks.BoardName.EQ(sqlite.String(boardName))),
+			).
+			Exec(txStore.conn)
+		if err != nil {
+			return err
 		}
+
+		_, err = table.Boards.
+			DELETE().
+			WHERE(table.Boards.ServerID.
+				EQ(sqlite.String(serverID)).
+				AND(table.Boards.BoardName.EQ(sqlite.String(boardName))),
+			).
+			Exec(txStore.conn)
+
+		return err
+	})
+}
+
+// SetBoardChannel stores the channel a board posts to. It is used to fill in
+// the channel ID of boards configured before we stored IDs and to keep the
+// channel name up to date when the channel is renamed
+func (store *SQLiteStore) SetBoardChannel(serverID string, boardName string, channelID string, channelName string) error {
+	log.Printf("Request received to set channel of board %s in server %s to %s (ID: %s)\n", boardName, serverID, channelName, channelID)
+
+	sqlStmt := table.Boards.
+		UPDATE(table.Boards.ChannelID, table.Boards.ChannelName).
+		SET(sqlite.String(channelID), sqlite.String(channelName)).
+		WHERE(table.Boards.ServerID.
+			EQ(sqlite.String(serverID)).
+			AND(table.Boards.BoardName.EQ(sqlite.String(boardName))),
+		)
+
+	_, err := sqlStmt.Exec(store.conn)
+	if err != nil {
+		return err
 	}
 
 	return nil
@@ -130,15 +255,9 @@ func EnrollServer(server model.Servers, activities string) error {
 
 // EnrollUser takes form data from our enrollment survey and
 // commits that data to our database
-func EnrollUser(user model.Users) error {
+func (store *SQLiteStore) EnrollUser(user model.Users) error {
 	log.Printf("Request received to attach discord user %s to OSRS user %s\n", user.DiscordUsername, user.OsrsUsername)
 
-	db, err := sql.Open("sqlite3", DBFilePath)
-	if err != nil {
-		return err
-	}
-	defer db.Close()
-
 	sqlStmt := table.Users.
 		INSERT(table.Users.AllColumns).
 		MODEL(user).
@@ -149,10 +268,11 @@ func EnrollUser(user model.Users) error {
 				table.Users.OsrsAccountType.SET(sqlite.String(user.OsrsAccountType)),
 				table.Users.DiscordUsername.SET(sqlite.String(user.DiscordUsername)),
 				table.Users.DiscordUserID.SET(sqlite.String(user.DiscordUserID)),
+				table.Users.IsArchived.SET(sqlite.Bool(user.IsArchived)),
 			),
 		)
 
-	_, err = sqlStmt.Exec(db)
+	_, err := sqlStmt.Exec(store.conn)
 	if err != nil {
 		return err
 	}
@@ -161,23 +281,125 @@ func EnrollUser(user model.Users) error {
 }
 
 // RemoveUser removes a user from a specific server
-func RemoveUser(user model.Users) error {
+func (store *SQLiteStore) RemoveUser(user model.Users) error {
 	log.Printf("Request received to remove OSRS user %s from server %s\n", user.OsrsUsername, user.ServerID)
 
-	db, err := sql.Open("sqlite3", DBFilePath)
+	sqlStmt := table.Users.
+		DELETE().
+		WHERE(table.Users.ServerID.
+			EQ(sqlite.String(user.ServerID)).
+			AND(table.Users.OsrsUsernameKey.EQ(sqlite.String(user.OsrsUsernameKey))),
+		)
+
+	_, err := sqlStmt.Exec(store.conn)
 	if err != nil {
 		return err
 	}
-	defer db.Close()
+
+	return nil
+}
+
+// RemoveUsersByDiscordID removes every OSRS user linked to a
+// specific Discord member from a server
+func (store *SQLiteStore) RemoveUsersByDiscordID(serverID string, discordUserID string) error {
+	log.Printf("Request received to remove all OSRS users linked to discord user %s from server %s\n", discordUserID, serverID)
 
 	sqlStmt := table.Users.
 		DELETE().
+		WHERE(table.Users.ServerID.
+			EQ(sqlite.String(serverID)).
+			AND(table.Users.DiscordUserID.EQ(sqlite.String(discordUserID))),
+		)
+
+	_, err := sqlStmt.Exec(store.conn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetUsersArchived archives (or restores) every OSRS user linked to
+// a specific Discord member. Archived users are kept in the database
+// but are hidden from leaderboards
+func (store *SQLiteStore) SetUsersArchived(serverID string, discordUserID string, archived bool) error {
+	log.Printf("Request received to set archived=%t for OSRS users linked to discord user %s in server %s\n", archived, discordUserID, serverID)
+
+	sqlStmt := table.Users.
+		UPDATE(table.Users.IsArchived).
+		SET(sqlite.Bool(archived)).
+		WHERE(table.Users.ServerID.
+			EQ(sqlite.String(serverID)).
+			AND(table.Users.DiscordUserID.EQ(sqlite.String(discordUserID))),
+		)
+
+	_, err := sqlStmt.Exec(store.conn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RenameUser changes the RSN of an already enrolled user while keeping
+// everything else about them (account type, Discord link) intact
+func (store *SQLiteStore) RenameUser(user model.Users, newOsrsUsernameKey string, newOsrsUsername string) error {
+	log.Printf("Request received to rename OSRS user %s to %s in server %s\n", user.OsrsUsername, newOsrsUsername, user.ServerID)
+
+	return store.inTransaction(func(txStore *SQLiteStore) error {
+		_, err := table.Users.
+			UPDATE(table.Users.OsrsUsernameKey, table.Users.OsrsUsername, table.Users.PossiblyRenamed).
+			SET(sqlite.String(newOsrsUsernameKey), sqlite.String(newOsrsUsername), sqlite.Bool(false)).
+			WHERE(table.Users.ServerID.
+				EQ(sqlite.String(user.ServerID)).
+				AND(table.Users.OsrsUsernameKey.EQ(sqlite.String(user.OsrsUsernameKey))),
+			).
+			Exec(txStore.conn)
+		if err != nil {
+			return err
+		}
+
+		if newOsrsUsernameKey == user.OsrsUsernameKey {
+			return nil
+		}
+
+		// The player keeps their last hiscores under the new name. Other servers
+		// may still track the old name so its snapshots are left to expire
+		_, err = table.HiscoresSnapshots.
+			INSERT(table.HiscoresSnapshots.AllColumns).
+			QUERY(
+				sqlite.SELECT(
+					sqlite.String(newOsrsUsernameKey),
+					table.HiscoresSnapshots.OsrsAccountType,
+					table.HiscoresSnapshots.Leaderboard,
+					table.HiscoresSnapshots.FetchedAt,
+					table.HiscoresSnapshots.Hiscores,
+				).
+					FROM(table.HiscoresSnapshots).
+					WHERE(table.HiscoresSnapshots.OsrsUsername.EQ(sqlite.String(user.OsrsUsernameKey))),
+			).
+			ON_CONFLICT(table.HiscoresSnapshots.OsrsUsername, table.HiscoresSnapshots.OsrsAccountType, table.HiscoresSnapshots.Leaderboard).
+			DO_NOTHING().
+			Exec(txStore.conn)
+
+		return err
+	})
+}
+
+// SetUserPossiblyRenamed flags (or unflags) a user whose RSN
+// can no longer be found on the hiscores
+func (store *SQLiteStore) SetUserPossiblyRenamed(user model.Users, possiblyRenamed bool) error {
+	log.Printf("Request received to set possibly_renamed=%t for OSRS user %s in server %s\n", possiblyRenamed, user.OsrsUsername, user.ServerID)
+
+	sqlStmt := table.Users.
+		UPDATE(table.Users.PossiblyRenamed).
+		SET(sqlite.Bool(possiblyRenamed)).
 		WHERE(table.Users.ServerID.
 			EQ(sqlite.String(user.ServerID)).
 			AND(table.Users.OsrsUsernameKey.EQ(sqlite.String(user.OsrsUsernameKey))),
 		)
 
-	_, err = sqlStmt.Exec(db)
+	_, err := sqlStmt.Exec(store.conn)
 	if err != nil {
 		return err
 	}
@@ -185,18 +407,95 @@ func RemoveUser(user model.Users) error {
 	return nil
 }
 
-// FetchAllServers gets all enrolled servers from the database
-func FetchAllServers() ([]model.Servers, error) {
-	db, err := sql.Open("sqlite3", DBFilePath)
+// SetPrimaryUser marks an OSRS user as the primary account of the
+// Discord member it is linked to. Any other account linked to the
+// same member stops being primary
+func (store *SQLiteStore) SetPrimaryUser(user model.Users) error {
+	log.Printf("Request received to make OSRS user %s the primary account of discord user %s\n", user.OsrsUsername, user.DiscordUserID)
+
+	sqlStmt := table.Users.
+		UPDATE(table.Users.IsPrimary).
+		SET(table.Users.OsrsUsernameKey.EQ(sqlite.String(user.OsrsUsernameKey))).
+		WHERE(table.Users.ServerID.
+			EQ(sqlite.String(user.ServerID)).
+			AND(table.Users.DiscordUserID.EQ(sqlite.String(user.DiscordUserID))),
+		)
+
+	_, err := sqlStmt.Exec(store.conn)
 	if err != nil {
-		return []model.Servers{}, err
+		return err
 	}
-	defer db.Close()
 
+	return nil
+}
+
+// FetchPrimaryUser returns the primary OSRS user linked to a Discord member.
+// If the member never picked a primary account we fall back to any of them
+func (store *SQLiteStore) FetchPrimaryUser(serverID string, discordUserID string) (model.Users, error) {
+	sqlStmt := table.Users.
+		SELECT(table.Users.AllColumns).
+		WHERE(table.Users.ServerID.
+			EQ(sqlite.String(serverID)).
+			AND(table.Users.DiscordUserID.EQ(sqlite.String(discordUserID))).
+			AND(table.Users.IsArchived.IS_FALSE()),
+		).
+		ORDER_BY(table.Users.IsPrimary.DESC(), table.Users.OsrsUsernameKey).
+		LIMIT(1)
+
+	u := model.Users{}
+	err := sqlStmt.Query(store.conn, &u)
+	if err != nil {
+		return model.Users{}, err
+	}
+
+	return u, nil
+}
+
+// SetUserClanRank records the in-game clan rank a user currently holds
+func (store *SQLiteStore) SetUserClanRank(user model.Users, clanRank string) error {
+	log.Printf("Request received to set clan rank of OSRS user %s in server %s to %s\n", user.OsrsUsername, user.ServerID, clanRank)
+
+	sqlStmt := table.Users.
+		UPDATE(table.Users.ClanRank).
+		SET(sqlite.String(clanRank)).
+		WHERE(table.Users.ServerID.
+			EQ(sqlite.String(user.ServerID)).
+			AND(table.Users.OsrsUsernameKey.EQ(sqlite.String(user.OsrsUsernameKey))),
+		)
+
+	_, err := sqlStmt.Exec(store.conn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateDiscordUsername refreshes the stored Discord username
+// for every OSRS user linked to a Discord member
+func (store *SQLiteStore) UpdateDiscordUsername(serverID string, discordUserID string, discordUsername string) error {
+	sqlStmt := table.Users.
+		UPDATE(table.Users.DiscordUsername).
+		SET(sqlite.String(discordUsername)).
+		WHERE(table.Users.ServerID.
+			EQ(sqlite.String(serverID)).
+			AND(table.Users.DiscordUserID.EQ(sqlite.String(discordUserID))),
+		)
+
+	_, err := sqlStmt.Exec(store.conn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FetchAllServers gets all enrolled servers from the database
+func (store *SQLiteStore) FetchAllServers() ([]model.Servers, error) {
 	sqlStmt := table.Servers.SELECT(table.Servers.AllColumns)
 
 	var allServers []model.Servers
-	err = sqlStmt.Query(db, &allServers)
+	err := sqlStmt.Query(store.conn, &allServers)
 	if err != nil {
 		return allServers, err
 	}
@@ -206,20 +505,14 @@ func FetchAllServers() ([]model.Servers, error) {
 
 // FetchServer takes a Guild ID and returns the relevant
 // row from our database with the users existing config
-func FetchServer(serverID string) (model.Servers, error) {
-
-	db, err := sql.Open("sqlite3", DBFilePath)
-	if err != nil {
-		return model.Servers{}, err
-	}
-	defer db.Close()
+func (store *SQLiteStore) FetchServer(serverID string) (model.Servers, error) {
 
 	sqlStmt := table.Servers.
 		SELECT(table.Servers.AllColumns).
 		WHERE(table.Servers.ID.EQ(sqlite.String(serverID)))
 
 	var s model.Servers
-	err = sqlStmt.Query(db, &s)
+	err := sqlStmt.Query(store.conn, &s)
 	if err != nil {
 		return model.Servers{}, err
 	}
@@ -227,33 +520,99 @@ func FetchServer(serverID string) (model.Servers, error) {
 	return s, nil
 }
 
-// FetchAllUsers gets all enrolled servers from the database
-func FetchAllUsers(serverID string) ([]model.Users, error) {
-	db, err := sql.Open("sqlite3", DBFilePath)
+// SetServerEnabled flips the is_enabled flag for a server and records
+// why it was disabled so we can tell automatic and manual disables apart
+func (store *SQLiteStore) SetServerEnabled(serverID string, enabled bool, disabledReason string) error {
+	log.Printf("Request received to set enabled=%t for server %s (reason: '%s')\n", enabled, serverID, disabledReason)
+
+	sqlStmt := table.Servers.
+		UPDATE(table.Servers.IsEnabled, table.Servers.DisabledReason).
+		SET(sqlite.Bool(enabled), sqlite.String(disabledReason)).
+		WHERE(table.Servers.ID.EQ(sqlite.String(serverID)))
+
+	_, err := sqlStmt.Exec(store.conn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateServerSettings stores the optional settings managed by /settings
+// without touching anything that is managed by /configure
+func (store *SQLiteStore) UpdateServerSettings(server model.Servers) error {
+	log.Printf("Request received to update settings for server: %s (ID: %s)\n", server.ServerName, server.ID)
+
+	sqlStmt := table.Servers.
+		UPDATE(
+			table.Servers.MemberLeaveAction,
+			table.Servers.RoleSyncEnabled,
+			table.Servers.NicknameSyncEnabled,
+			table.Servers.NicknameAccountTypePrefix,
+			table.Servers.CatchUpEnabled,
+			table.Servers.CatchUpMaxLatenessHours,
+			table.Servers.OrphanedMessageAction,
+			table.Servers.AdminChannelID,
+			table.Servers.StaleDataMaxAgeHours,
+		).
+		SET(
+			sqlite.String(server.MemberLeaveAction),
+			sqlite.Bool(server.RoleSyncEnabled),
+			sqlite.Bool(server.NicknameSyncEnabled),
+			sqlite.Bool(server.NicknameAccountTypePrefix),
+			sqlite.Bool(server.CatchUpEnabled),
+			sqlite.Int32(server.CatchUpMaxLatenessHours),
+			sqlite.String(server.OrphanedMessageAction),
+			sqlite.String(server.AdminChannelID),
+			sqlite.Int32(server.StaleDataMaxAgeHours),
+		).
+		WHERE(table.Servers.ID.EQ(sqlite.String(server.ID)))
+
+	_, err := sqlStmt.Exec(store.conn)
 	if err != nil {
-		return []model.Users{}, err
+		return err
+	}
+
+	return nil
+}
+
+// UpdateRankReportSchedule stores how often a server wants its rank
+// promotion report posted. An empty schedule turns the report off
+func (store *SQLiteStore) UpdateRankReportSchedule(serverID string, schedule string) error {
+	log.Printf("Request received to set rank report schedule for server %s to '%s'\n", serverID, schedule)
+
+	sqlStmt := table.Servers.
+		UPDATE(table.Servers.RankReportSchedule).
+		SET(sqlite.String(schedule)).
+		WHERE(table.Servers.ID.EQ(sqlite.String(serverID)))
+
+	_, err := sqlStmt.Exec(store.conn)
+	if err != nil {
+		return err
 	}
-	defer db.Close()
 
+	return nil
+}
+
+// FetchAllUsers gets all enrolled users for a server from the database.
+// Archived users are left out since they shouldn't appear on leaderboards
+func (store *SQLiteStore) FetchAllUsers(serverID string) ([]model.Users, error) {
 	sqlStmt := table.Users.
 		SELECT(table.Users.AllColumns).
-		WHERE(table.Users.ServerID.EQ(sqlite.String(serverID)))
+		WHERE(table.Users.ServerID.
+			EQ(sqlite.String(serverID)).
+			AND(table.Users.IsArchived.IS_FALSE()),
+		)
 
 	var allUsers []model.Users
-	sqlStmt.Query(db, &allUsers)
+	sqlStmt.Query(store.conn, &allUsers)
 
 	return allUsers, nil
 }
 
 // FetchUser takes a Guild ID and returns the relevant
 // row from our database with the users existing config
-func FetchUser(serverID string, osrsUsername string) (model.Users, error) {
-
-	db, err := sql.Open("sqlite3", DBFilePath)
-	if err != nil {
-		return model.Users{}, err
-	}
-	defer db.Close()
+func (store *SQLiteStore) FetchUser(serverID string, osrsUsername string) (model.Users, error) {
 
 	sqlStmt := table.Users.
 		SELECT(table.Users.AllColumns).
@@ -263,7 +622,7 @@ func FetchUser(serverID string, osrsUsername string) (model.Users, error) {
 		)
 
 	u := model.Users{}
-	err = sqlStmt.Query(db, &u)
+	err := sqlStmt.Query(store.conn, &u)
 	if err != nil {
 		return model.Users{}, err
 	}
@@ -271,22 +630,20 @@ func FetchUser(serverID string, osrsUsername string) (model.Users, error) {
 	return u, nil
 }
 
-// FetchAllActivitiesAndSkills takes a Guild ID and returns all the unique
-func FetchAllActivitiesAndSkills(serverID string) ([]string, error) {
-	db, err := sql.Open("sqlite3", DBFilePath)
-	if err != nil {
-		return []string{}, err
-	}
-	defer db.Close()
-
+// FetchAllActivitiesAndSkills takes a Guild ID and board name and
+// returns all the unique activities on the board in order of position
+func (store *SQLiteStore) FetchAllActivitiesAndSkills(serverID string, boardName string) ([]string, error) {
 	sqlStmt := table.Messages.
 		SELECT(table.Messages.Activity).
 		DISTINCT().
-		WHERE(table.Messages.ServerID.EQ(sqlite.String(serverID))).
+		WHERE(table.Messages.ServerID.
+			EQ(sqlite.String(serverID)).
+			AND(table.Messages.BoardName.EQ(sqlite.String(boardName))),
+		).
 		ORDER_BY(table.Messages.Position)
 
 	var m []string
-	err = sqlStmt.Query(db, &m)
+	err := sqlStmt.Query(store.conn, &m)
 	if err != nil {
 		return []string{}, err
 	}
@@ -295,24 +652,20 @@ func FetchAllActivitiesAndSkills(serverID string) ([]string, error) {
 
 }
 
-// FetchActivityPosition takes a Guild ID and returns all the unique
-func FetchActivityPosition(serverID string, activity string) (int32, error) {
-	db, err := sql.Open("sqlite3", DBFilePath)
-	if err != nil {
-		return -1, err
-	}
-	defer db.Close()
-
+// FetchActivityPosition returns the position of an activity on a
+// board or -1 if the activity isn't on the board
+func (store *SQLiteStore) FetchActivityPosition(serverID string, boardName string, activity string) (int32, error) {
 	sqlStmt := table.Messages.
 		SELECT(table.Messages.Position).
 		DISTINCT().
 		WHERE(table.Messages.ServerID.
 			EQ(sqlite.String(serverID)).
+			AND(table.Messages.BoardName.EQ(sqlite.String(boardName))).
 			AND(table.Messages.Activity.EQ(sqlite.String(activity))),
 		)
 
 	var m []int32
-	err = sqlStmt.Query(db, &m)
+	err := sqlStmt.Query(store.conn, &m)
 	if err != nil {
 		return -1, err
 	}
@@ -324,23 +677,20 @@ func FetchActivityPosition(serverID string, activity string) (int32, error) {
 	}
 }
 
-// FetchAllMessages takes a Guild ID and returns the relevant
-// rows from our database that match the Guid ID
-func FetchAllMessages(serverID string) ([]model.Messages, error) {
-
-	db, err := sql.Open("sqlite3", DBFilePath)
-	if err != nil {
-		return []model.Messages{}, err
-	}
-	defer db.Close()
+// FetchAllMessages takes a Guild ID and board name and returns
+// the relevant rows from our database for that board
+func (store *SQLiteStore) FetchAllMessages(serverID string, boardName string) ([]model.Messages, error) {
 
 	sqlStmt := table.Messages.
 		SELECT(table.Messages.AllColumns).
-		WHERE(table.Messages.ServerID.EQ(sqlite.String(serverID))).
+		WHERE(table.Messages.ServerID.
+			EQ(sqlite.String(serverID)).
+			AND(table.Messages.BoardName.EQ(sqlite.String(boardName))),
+		).
 		ORDER_BY(table.Messages.Position)
 
 	var m []model.Messages
-	err = sqlStmt.Query(db, &m)
+	err := sqlStmt.Query(store.conn, &m)
 	if err != nil {
 		return []model.Messages{}, err
 	}
@@ -348,25 +698,20 @@ func FetchAllMessages(serverID string) ([]model.Messages, error) {
 	return m, nil
 }
 
-// FetchMessage takes a Guild ID and returns the relevant
-// row from our database that matches the specified activity
-func FetchMessage(serverID, activity string) ([]model.Messages, error) {
-
-	db, err := sql.Open("sqlite3", DBFilePath)
-	if err != nil {
-		return []model.Messages{}, err
-	}
-	defer db.Close()
+// FetchMessage takes a Guild ID and board name and returns the
+// relevant rows from our database that match the specified activity
+func (store *SQLiteStore) FetchMessage(serverID string, boardName string, activity string) ([]model.Messages, error) {
 
 	sqlStmt := table.Messages.
 		SELECT(table.Messages.AllColumns).
 		WHERE(table.Messages.ServerID.
 			EQ(sqlite.String(serverID)).
+			AND(table.Messages.BoardName.EQ(sqlite.String(boardName))).
 			AND(table.Messages.Activity.EQ(sqlite.String(activity))),
 		)
 
 	var m []model.Messages
-	err = sqlStmt.Query(db, &m)
+	err := sqlStmt.Query(store.conn, &m)
 	if err != nil {
 		return []model.Messages{}, err
 	}
@@ -376,29 +721,20 @@ func FetchMessage(serverID, activity string) ([]model.Messages, error) {
 
 // EnrollMessage takes a MessageID for a message we posted
 // and stores it so we can update that message later
-func EnrollMessage(server model.Servers, message model.Messages) error {
-	log.Printf("Request received to enroll message ID %s for server: %s (ID: %s) and activity %s\n", message.MessageID, server.ServerName, server.ID, message.Activity)
-
-	db, err := sql.Open("sqlite3", DBFilePath)
-	if err != nil {
-		return err
-	}
-	defer db.Close()
+func (store *SQLiteStore) EnrollMessage(message model.Messages) error {
+	log.Printf("Request received to enroll message ID %s for server %s, board %s and activity %s\n", message.MessageID, message.ServerID, message.BoardName, message.Activity)
 
 	sqlStmt := table.Messages.
 		INSERT(table.Messages.AllColumns).
 		MODEL(message).
-		ON_CONFLICT(table.Messages.MessageID, table.Messages.ServerID, table.Messages.Activity).
+		ON_CONFLICT(table.Messages.MessageID, table.Messages.ServerID, table.Messages.BoardName, table.Messages.Activity).
 		DO_UPDATE(
 			sqlite.SET(
-				table.Messages.MessageID.SET(sqlite.String(message.MessageID)),
-				table.Messages.ServerID.SET(sqlite.String(server.ID)),
-				table.Messages.Activity.SET(sqlite.String(message.Activity)),
 				table.Messages.Position.SET(sqlite.Int32(message.Position)),
 			),
 		)
 
-	_, err = sqlStmt.Exec(db)
+	_, err := sqlStmt.Exec(store.conn)
 	if err != nil {
 		return err
 	}
@@ -407,24 +743,395 @@ func EnrollMessage(server model.Servers, message model.Messages) error {
 }
 
 // RemoveMessage takes a message and removes the matching row from the database
-func RemoveMessage(message model.Messages) error {
-	log.Printf("Request received to remove message ID %s for server: %s and activity %s\n", message.MessageID, message.ServerID, message.Activity)
-
-	db, err := sql.Open("sqlite3", DBFilePath)
-	if err != nil {
-		return err
-	}
-	defer db.Close()
+func (store *SQLiteStore) RemoveMessage(message model.Messages) error {
+	log.Printf("Request received to remove message ID %s for server %s, board %s and activity %s\n", message.MessageID, message.ServerID, message.BoardName, message.Activity)
 
 	sqlStmt := table.Messages.
 		DELETE().
 		WHERE(table.Messages.Activity.
 			EQ(sqlite.String(message.Activity)).
 			AND(table.Messages.ServerID.EQ(sqlite.String(message.ServerID))).
+			AND(table.Messages.BoardName.EQ(sqlite.String(message.BoardName))).
 			AND(table.Messages.MessageID.EQ(sqlite.String(message.MessageID))),
 		)
 
-	_, err = sqlStmt.Exec(db)
+	_, err := sqlStmt.Exec(store.conn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ResetMessages forgets every Discord message we have posted for a server
+// while keeping one placeholder row per activity on each board so the
+// configured list of activities (and their order) survives
+func (store *SQLiteStore) ResetMessages(serverID string) error {
+	log.Printf("Request received to reset all messages for server %s\n", serverID)
+
+	boards, err := store.FetchBoards(serverID)
+	if err != nil {
+		return err
+	}
+
+	boardActivities := map[string][]string{}
+	for _, board := range boards {
+		boardActivities[board.BoardName], err = store.FetchAllActivitiesAndSkills(serverID, board.BoardName)
+		if err != nil {
+			return err
+		}
+	}
+
+	return store.inTransaction(func(txStore *SQLiteStore) error {
+		_, err := table.Messages.
+			DELETE().
+			WHERE(table.Messages.ServerID.EQ(sqlite.String(serverID))).
+			Exec(txStore.conn)
+		if err != nil {
+			return err
+		}
+
+		for boardName, activities := range boardActivities {
+			for position, activity := range activities {
+				err = txStore.EnrollMessage(model.Messages{
+					MessageID: "",
+					ServerID:  serverID,
+					BoardName: boardName,
+					Activity:  activity,
+					Position:  int32(position),
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// FetchRoleRules returns every role rule configured for a server
+func (store *SQLiteStore) FetchRoleRules(serverID string) ([]model.RoleRules, error) {
+	sqlStmt := table.RoleRules.
+		SELECT(table.RoleRules.AllColumns).
+		WHERE(table.RoleRules.ServerID.EQ(sqlite.String(serverID))).
+		ORDER_BY(table.RoleRules.RoleID, table.RoleRules.Activity)
+
+	var r []model.RoleRules
+	err := sqlStmt.Query(store.conn, &r)
+	if err != nil {
+		return []model.RoleRules{}, err
+	}
+
+	return r, nil
+}
+
+// EnrollRoleRule creates or updates the threshold a member needs
+// to reach in an activity to be granted a role
+func (store *SQLiteStore) EnrollRoleRule(rule model.RoleRules) error {
+	log.Printf("Request received to enroll role rule %s >= %d for role %s in server %s\n", rule.Activity, rule.Threshold, rule.RoleID, rule.ServerID)
+
+	sqlStmt := table.RoleRules.
+		INSERT(table.RoleRules.AllColumns).
+		MODEL(rule).
+		ON_CONFLICT(table.RoleRules.ServerID, table.RoleRules.RoleID, table.RoleRules.Activity).
+		DO_UPDATE(
+			sqlite.SET(
+				table.RoleRules.Threshold.SET(sqlite.Int32(rule.Threshold)),
+			),
+		)
+
+	_, err := sqlStmt.Exec(store.conn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RemoveRoleRules removes the rule for a role and activity. If no
+// activity is given every rule for the role is removed
+func (store *SQLiteStore) RemoveRoleRules(serverID string, roleID string, activity string) error {
+	log.Printf("Request received to remove role rules for role %s and activity '%s' in server %s\n", roleID, activity, serverID)
+
+	condition := table.RoleRules.ServerID.
+		EQ(sqlite.String(serverID)).
+		AND(table.RoleRules.RoleID.EQ(sqlite.String(roleID)))
+
+	if activity != "" {
+		condition = condition.AND(table.RoleRules.Activity.EQ(sqlite.String(activity)))
+	}
+
+	_, err := table.RoleRules.DELETE().WHERE(condition).Exec(store.conn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FetchRanks returns a server's rank ladder from the highest rank to the lowest
+func (store *SQLiteStore) FetchRanks(serverID string) ([]model.Ranks, error) {
+	sqlStmt := table.Ranks.
+		SELECT(table.Ranks.AllColumns).
+		WHERE(table.Ranks.ServerID.EQ(sqlite.String(serverID))).
+		ORDER_BY(table.Ranks.Position.DESC())
+
+	var r []model.Ranks
+	err := sqlStmt.Query(store.conn, &r)
+	if err != nil {
+		return []model.Ranks{}, err
+	}
+
+	return r, nil
+}
+
+// EnrollRank creates or updates a rank on a server's rank ladder
+func (store *SQLiteStore) EnrollRank(rank model.Ranks) error {
+	log.Printf("Request received to enroll rank %s (position %d) for server %s\n", rank.RankName, rank.Position, rank.ServerID)
+
+	sqlStmt := table.Ranks.
+		INSERT(table.Ranks.AllColumns).
+		MODEL(rank).
+		ON_CONFLICT(table.Ranks.ServerID, table.Ranks.RankName).
+		DO_UPDATE(
+			sqlite.SET(
+				table.Ranks.Position.SET(sqlite.Int32(rank.Position)),
+				table.Ranks.Requirements.SET(sqlite.String(rank.Requirements)),
+			),
+		)
+
+	_, err := sqlStmt.Exec(store.conn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RemoveRank removes a rank from a server's rank ladder
+func (store *SQLiteStore) RemoveRank(serverID string, rankName string) error {
+	log.Printf("Request received to remove rank %s from server %s\n", rankName, serverID)
+
+	sqlStmt := table.Ranks.
+		DELETE().
+		WHERE(table.Ranks.ServerID.
+			EQ(sqlite.String(serverID)).
+			AND(table.Ranks.RankName.EQ(sqlite.String(rankName))),
+		)
+
+	_, err := sqlStmt.Exec(store.conn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RecordScheduleRun stores how a board's run went along with the players it
+// couldn't fetch and forgets the board's runs that are older than scheduleRunRetention
+func (store *SQLiteStore) RecordScheduleRun(run model.ScheduleRuns, failures []model.RunFetchFailures) error {
+	cutoff := run.StartedAt.Add(-scheduleRunRetention)
+
+	return store.inTransaction(func(txStore *SQLiteStore) error {
+		_, err := table.ScheduleRuns.
+			INSERT(table.ScheduleRuns.AllColumns).
+			MODEL(run).
+			Exec(txStore.conn)
+		if err != nil {
+			return err
+		}
+
+		if len(failures) > 0 {
+			_, err = table.RunFetchFailures.
+				INSERT(table.RunFetchFailures.AllColumns).
+				MODELS(failures).
+				Exec(txStore.conn)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = table.ScheduleRuns.
+			DELETE().
+			WHERE(table.ScheduleRuns.ServerID.
+				EQ(sqlite.String(run.ServerID)).
+				AND(table.ScheduleRuns.BoardName.EQ(sqlite.String(run.BoardName))).
+				AND(table.ScheduleRuns.StartedAt.LT(sqlite.RawTimestamp("#cutoff", sqlite.RawArgs{"#cutoff": cutoff}))),
+			).
+			Exec(txStore.conn)
+		if err != nil {
+			return err
+		}
+
+		_, err = table.RunFetchFailures.
+			DELETE().
+			WHERE(table.RunFetchFailures.ServerID.
+				EQ(sqlite.String(run.ServerID)).
+				AND(table.RunFetchFailures.BoardName.EQ(sqlite.String(run.BoardName))).
+				AND(table.RunFetchFailures.StartedAt.LT(sqlite.RawTimestamp("#cutoff", sqlite.RawArgs{"#cutoff": cutoff}))),
+			).
+			Exec(txStore.conn)
+
+		return err
+	})
+}
+
+// FetchRunFetchFailures returns the players a run of a board couldn't fetch
+func (store *SQLiteStore) FetchRunFetchFailures(run model.ScheduleRuns) ([]model.RunFetchFailures, error) {
+	sqlStmt := table.RunFetchFailures.
+		SELECT(table.RunFetchFailures.AllColumns).
+		WHERE(table.RunFetchFailures.ServerID.
+			EQ(sqlite.String(run.ServerID)).
+			AND(table.RunFetchFailures.BoardName.EQ(sqlite.String(run.BoardName))).
+			AND(table.RunFetchFailures.StartedAt.EQ(sqlite.RawTimestamp("#startedAt", sqlite.RawArgs{"#startedAt": run.StartedAt}))),
+		).
+		ORDER_BY(table.RunFetchFailures.OsrsUsername.ASC())
+
+	failures := []model.RunFetchFailures{}
+	err := sqlStmt.Query(store.conn, &failures)
+	if err != nil {
+		return nil, err
+	}
+
+	return failures, nil
+}
+
+// FetchLastScheduleRun returns the most recent run of a board
+func (store *SQLiteStore) FetchLastScheduleRun(serverID string, boardName string) (model.ScheduleRuns, error) {
+	sqlStmt := table.ScheduleRuns.
+		SELECT(table.ScheduleRuns.AllColumns).
+		WHERE(table.ScheduleRuns.ServerID.
+			EQ(sqlite.String(serverID)).
+			AND(table.ScheduleRuns.BoardName.EQ(sqlite.String(boardName))),
+		).
+		ORDER_BY(table.ScheduleRuns.StartedAt.DESC()).
+		LIMIT(1)
+
+	var r model.ScheduleRuns
+	err := sqlStmt.Query(store.conn, &r)
+	if err != nil {
+		return model.ScheduleRuns{}, err
+	}
+
+	return r, nil
+}
+
+// FetchLastSuccessfulScheduleRun returns the most recent run of a
+// board that posted every message
+func (store *SQLiteStore) FetchLastSuccessfulScheduleRun(serverID string, boardName string) (model.ScheduleRuns, error) {
+	sqlStmt := table.ScheduleRuns.
+		SELECT(table.ScheduleRuns.AllColumns).
+		WHERE(table.ScheduleRuns.ServerID.
+			EQ(sqlite.String(serverID)).
+			AND(table.ScheduleRuns.BoardName.EQ(sqlite.String(boardName))).
+			AND(table.ScheduleRuns.Outcome.EQ(sqlite.String(types.RunOutcomeSuccess))),
+		).
+		ORDER_BY(table.ScheduleRuns.StartedAt.DESC()).
+		LIMIT(1)
+
+	var r model.ScheduleRuns
+	err := sqlStmt.Query(store.conn, &r)
+	if err != nil {
+		return model.ScheduleRuns{}, err
+	}
+
+	return r, nil
+}
+
+// SaveHiscoresSnapshots stores the latest hiscores we fetched for some players
+// and forgets snapshots that are older than hiscoresSnapshotRetention
+func (store *SQLiteStore) SaveHiscoresSnapshots(snapshots []model.HiscoresSnapshots) error {
+	cutoff := time.Now().UTC().Add(-hiscoresSnapshotRetention)
+
+	return store.inTransaction(func(txStore *SQLiteStore) error {
+		for _, snapshot := range snapshots {
+			_, err := table.HiscoresSnapshots.
+				INSERT(table.HiscoresSnapshots.AllColumns).
+				MODEL(snapshot).
+				ON_CONFLICT(table.HiscoresSnapshots.OsrsUsername, table.HiscoresSnapshots.OsrsAccountType, table.HiscoresSnapshots.Leaderboard).
+				DO_UPDATE(
+					sqlite.SET(
+						table.HiscoresSnapshots.FetchedAt.SET(sqlite.RawTimestamp("#fetchedAt", sqlite.RawArgs{"#fetchedAt": snapshot.FetchedAt})),
+						table.HiscoresSnapshots.Hiscores.SET(sqlite.String(snapshot.Hiscores)),
+					),
+				).
+				Exec(txStore.conn)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err := table.HiscoresSnapshots.
+			DELETE().
+			WHERE(table.HiscoresSnapshots.FetchedAt.LT(sqlite.RawTimestamp("#cutoff", sqlite.RawArgs{"#cutoff": cutoff}))).
+			Exec(txStore.conn)
+
+		return err
+	})
+}
+
+// FetchHiscoresSnapshot returns the last hiscores we fetched for a player on a
+// leaderboard. An empty leaderboard is the hiscores of the player's account type
+func (store *SQLiteStore) FetchHiscoresSnapshot(osrsUsername string, accountType string, leaderboard string) (model.HiscoresSnapshots, error) {
+	sqlStmt := table.HiscoresSnapshots.
+		SELECT(table.HiscoresSnapshots.AllColumns).
+		WHERE(table.HiscoresSnapshots.OsrsUsername.
+			EQ(sqlite.String(osrsUsername)).
+			AND(table.HiscoresSnapshots.OsrsAccountType.EQ(sqlite.String(accountType))).
+			AND(table.HiscoresSnapshots.Leaderboard.EQ(sqlite.String(leaderboard))),
+		)
+
+	var snapshot model.HiscoresSnapshots
+	err := sqlStmt.Query(store.conn, &snapshot)
+	if err != nil {
+		return model.HiscoresSnapshots{}, err
+	}
+
+	return snapshot, nil
+}
+
+// AcquireRunLock takes the lock that lets a single run post a board. It
+// returns false if someone else holds a lock that hasn't expired yet
+func (store *SQLiteStore) AcquireRunLock(lock model.RunLocks) (bool, error) {
+	sqlStmt := table.RunLocks.
+		INSERT(table.RunLocks.AllColumns).
+		MODEL(lock).
+		ON_CONFLICT(table.RunLocks.ServerID, table.RunLocks.BoardName).
+		DO_UPDATE(
+			sqlite.SET(
+				table.RunLocks.Owner.SET(sqlite.String(lock.Owner)),
+				table.RunLocks.AcquiredAt.SET(sqlite.RawTimestamp("#acquired", sqlite.RawArgs{"#acquired": lock.AcquiredAt})),
+				table.RunLocks.ExpiresAt.SET(sqlite.RawTimestamp("#expires", sqlite.RawArgs{"#expires": lock.ExpiresAt})),
+			).WHERE(
+				table.RunLocks.ExpiresAt.LT(sqlite.RawTimestamp("#now", sqlite.RawArgs{"#now": lock.AcquiredAt})),
+			),
+		)
+
+	result, err := sqlStmt.Exec(store.conn)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows == 1, nil
+}
+
+// ReleaseRunLock gives up a board's lock if we're the ones holding it
+func (store *SQLiteStore) ReleaseRunLock(serverID string, boardName string, owner string) error {
+	sqlStmt := table.RunLocks.
+		DELETE().
+		WHERE(table.RunLocks.ServerID.
+			EQ(sqlite.String(serverID)).
+			AND(table.RunLocks.BoardName.EQ(sqlite.String(boardName))).
+			AND(table.RunLocks.Owner.EQ(sqlite.String(owner))),
+		)
+
+	_, err := sqlStmt.Exec(store.conn)
 	if err != nil {
 		return err
 	}
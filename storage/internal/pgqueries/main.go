@@ -0,0 +1,176 @@
+// Command pgqueries generates storage/postgres_queries.go from the queries in
+// storage/database.go. The go-jet statement builders of the two dialects are
+// different types so the queries can't be shared directly. Instead every
+// SQLiteStore method is copied to PostgresStore with the sqlite builders and
+// tables swapped for the postgres ones. Methods that have to differ are written
+// by hand in storage/postgres.go and are left out of the generated file.
+//
+// Run it with `go generate ./storage` after changing database.go
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// outputFile is where the generated queries are written
+const outputFile = "postgres_queries.go"
+
+// renamed are the identifiers that differ between the two stores
+var renamed = map[string]string{
+	"sqlite":      "postgres",
+	"table":       "pgtable",
+	"SQLiteStore": "PostgresStore",
+}
+
+// importPaths are the imports of the renamed packages
+var importPaths = map[string]string{
+	"postgres": `"github.com/go-jet/jet/v2/postgres"`,
+	"pgtable":  `pgtable "github.com/michohl/osrs-clan-leaderboard/jet_schemas/postgres/table"`,
+}
+
+func main() {
+	src, err := generate(".")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = os.WriteFile(outputFile, src, 0o644)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// generate returns the contents of postgres_queries.go for the storage package in dir
+func generate(dir string) ([]byte, error) {
+	fset := token.NewFileSet()
+
+	database, err := parser.ParseFile(fset, filepath.Join(dir, "database.go"), nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	postgres, err := parser.ParseFile(fset, filepath.Join(dir, "postgres.go"), nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Methods written by hand for Postgres aren't generated
+	handWritten := map[string]bool{}
+	for _, decl := range postgres.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && receiverName(fn) == "PostgresStore" {
+			handWritten[fn.Name.Name] = true
+		}
+	}
+
+	imports := map[string]string{}
+	for _, spec := range database.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		name := filepath.Base(path)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = spec.Path.Value
+	}
+
+	var body bytes.Buffer
+	used := map[string]bool{}
+	for _, decl := range database.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || receiverName(fn) != "SQLiteStore" || handWritten[fn.Name.Name] {
+			continue
+		}
+
+		ast.Inspect(fn, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.SelectorExpr:
+				if pkg, ok := n.X.(*ast.Ident); ok {
+					used[pkg.Name] = true
+				}
+			case *ast.Ident:
+				if name, ok := renamed[n.Name]; ok {
+					n.Name = name
+				}
+			}
+			return true
+		})
+
+		body.WriteString("\n")
+		err = printer.Fprint(&body, fset, &printer.CommentedNode{Node: fn, Comments: database.Comments})
+		if err != nil {
+			return nil, err
+		}
+		body.WriteString("\n")
+	}
+
+	// The packages were renamed after we noted them as used.
+	// The standard library is imported first like everywhere else
+	var std, others []string
+	for name := range used {
+		path, ok := imports[name]
+		if renamedName, isRenamed := renamed[name]; isRenamed {
+			path, ok = importPaths[renamedName]
+		}
+		switch {
+		case !ok:
+		case strings.Contains(path, "."):
+			others = append(others, path)
+		default:
+			std = append(std, path)
+		}
+	}
+	slices.Sort(std)
+	slices.SortFunc(others, func(a, b string) int {
+		return strings.Compare(importPath(a), importPath(b))
+	})
+
+	var out bytes.Buffer
+	out.WriteString("// Code generated by go run ./internal/pgqueries; DO NOT EDIT.\n\n")
+	out.WriteString("package storage\n\nimport (\n")
+	for _, path := range std {
+		fmt.Fprintf(&out, "\t%s\n", path)
+	}
+	out.WriteString("\n")
+	for _, path := range others {
+		fmt.Fprintf(&out, "\t%s\n", path)
+	}
+	out.WriteString(")\n")
+	out.Write(body.Bytes())
+
+	return format.Source(out.Bytes())
+}
+
+// importPath is the quoted path of an import without its name
+func importPath(spec string) string {
+	return spec[strings.Index(spec, `"`):]
+}
+
+// receiverName is the type a method belongs to or "" for plain functions
+func receiverName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+
+	star, ok := fn.Recv.List[0].Type.(*ast.StarExpr)
+	if !ok {
+		return ""
+	}
+
+	ident, ok := star.X.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+
+	return ident.Name
+}
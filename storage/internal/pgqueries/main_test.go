@@ -0,0 +1,26 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGeneratedQueriesAreUpToDate(t *testing.T) {
+	dir := filepath.Join("..", "..")
+
+	want, err := generate(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, outputFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s is out of date with database.go. Run `go generate ./storage`", outputFile)
+	}
+}
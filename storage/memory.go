@@ -0,0 +1,822 @@
+package storage
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+	"github.com/michohl/osrs-clan-leaderboard/types"
+)
+
+// MemoryStore is a Store that keeps everything in memory. It behaves the
+// same way as SQLiteStore (including ordering and upserts) so it can stand
+// in for a real database in tests. Nothing is persisted
+type MemoryStore struct {
+	mu        sync.Mutex
+	createdAt time.Time
+	servers   []model.Servers
+	boards    []model.Boards
+	users     []model.Users
+	messages  []model.Messages
+	roleRules []model.RoleRules
+	ranks     []model.Ranks
+	runs      []model.ScheduleRuns
+	failures  []model.RunFetchFailures
+	snapshots []model.HiscoresSnapshots
+	runLocks  []model.RunLocks
+}
+
+// NewMemoryStore returns an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{createdAt: time.Now().UTC()}
+}
+
+// Close does nothing since there is nothing to close
+func (store *MemoryStore) Close() error {
+	return nil
+}
+
+// Migrate does nothing since a MemoryStore always has the latest schema
+func (store *MemoryStore) Migrate() error {
+	return nil
+}
+
+// MigrationStatus reports every migration as applied when the store was created
+func (store *MemoryStore) MigrationStatus() ([]MigrationState, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	states := []MigrationState{}
+	for _, m := range migrations {
+		states = append(states, MigrationState{Migration: m, AppliedAt: &store.createdAt})
+	}
+
+	return states, nil
+}
+
+// EnrollBoard creates or updates a board along with the columns of its
+// server managed by /configure and lines its messages up with the activities
+func (store *MemoryStore) EnrollBoard(server model.Servers, board model.Boards, activities string) error {
+	store.mu.Lock()
+
+	i := slices.IndexFunc(store.servers, func(s model.Servers) bool { return s.ID == server.ID })
+	if i == -1 {
+		// Match the column defaults for everything /configure doesn't manage
+		store.servers = append(store.servers, model.Servers{
+			ID:                      server.ID,
+			ServerName:              server.ServerName,
+			IsEnabled:               server.IsEnabled,
+			DisabledReason:          server.DisabledReason,
+			Timezone:                server.Timezone,
+			MemberLeaveAction:       types.MemberLeaveActionKeep,
+			CatchUpEnabled:          true,
+			CatchUpMaxLatenessHours: types.DefaultCatchUpMaxLatenessHours,
+			OrphanedMessageAction:   types.OrphanedMessageActionDelete,
+			StaleDataMaxAgeHours:    types.DefaultStaleDataMaxAgeHours,
+		})
+	} else {
+		existing := &store.servers[i]
+		existing.ServerName = server.ServerName
+		existing.IsEnabled = server.IsEnabled
+		existing.DisabledReason = server.DisabledReason
+		existing.Timezone = server.Timezone
+	}
+
+	i = slices.IndexFunc(store.boards, func(b model.Boards) bool {
+		return b.ServerID == board.ServerID && b.BoardName == board.BoardName
+	})
+	if i == -1 {
+		store.boards = append(store.boards, board)
+	} else {
+		store.boards[i] = board
+	}
+
+	store.mu.Unlock()
+
+	return syncActivityMessages(store, board, activities)
+}
+
+// FetchBoards returns every board configured in a server ordered by name
+func (store *MemoryStore) FetchBoards(serverID string) ([]model.Boards, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	var boards []model.Boards
+	for _, b := range store.boards {
+		if b.ServerID == serverID {
+			boards = append(boards, b)
+		}
+	}
+
+	slices.SortFunc(boards, func(a, b model.Boards) int {
+		return cmp.Compare(a.BoardName, b.BoardName)
+	})
+
+	return boards, nil
+}
+
+// FetchBoard returns a single board or ErrNotFound
+func (store *MemoryStore) FetchBoard(serverID string, boardName string) (model.Boards, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	for _, b := range store.boards {
+		if b.ServerID == serverID && b.BoardName == boardName {
+			return b, nil
+		}
+	}
+
+	return model.Boards{}, ErrNotFound
+}
+
+// RemoveBoard removes a board along with its messages, run history and run lock
+func (store *MemoryStore) RemoveBoard(serverID string, boardName string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.messages = slices.DeleteFunc(store.messages, func(m model.Messages) bool {
+		return m.ServerID == serverID && m.BoardName == boardName
+	})
+	store.boards = slices.DeleteFunc(store.boards, func(b model.Boards) bool {
+		return b.ServerID == serverID && b.BoardName == boardName
+	})
+	store.runs = slices.DeleteFunc(store.runs, func(r model.ScheduleRuns) bool {
+		return r.ServerID == serverID && r.BoardName == boardName
+	})
+	store.failures = slices.DeleteFunc(store.failures, func(f model.RunFetchFailures) bool {
+		return f.ServerID == serverID && f.BoardName == boardName
+	})
+	store.runLocks = slices.DeleteFunc(store.runLocks, func(l model.RunLocks) bool {
+		return l.ServerID == serverID && l.BoardName == boardName
+	})
+
+	return nil
+}
+
+// SetBoardChannel stores the channel a board posts to
+func (store *MemoryStore) SetBoardChannel(serverID string, boardName string, channelID string, channelName string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	for i, b := range store.boards {
+		if b.ServerID == serverID && b.BoardName == boardName {
+			store.boards[i].ChannelID = channelID
+			store.boards[i].ChannelName = channelName
+		}
+	}
+
+	return nil
+}
+
+// FetchAllServers returns every enrolled server
+func (store *MemoryStore) FetchAllServers() ([]model.Servers, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	return slices.Clone(store.servers), nil
+}
+
+// FetchServer returns a single server or ErrNotFound
+func (store *MemoryStore) FetchServer(serverID string) (model.Servers, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	for _, s := range store.servers {
+		if s.ID == serverID {
+			return s, nil
+		}
+	}
+
+	return model.Servers{}, ErrNotFound
+}
+
+// SetServerEnabled flips the is_enabled flag for a server
+func (store *MemoryStore) SetServerEnabled(serverID string, enabled bool, disabledReason string) error {
+	store.updateServer(serverID, func(s *model.Servers) {
+		s.IsEnabled = enabled
+		s.DisabledReason = disabledReason
+	})
+
+	return nil
+}
+
+// UpdateServerSettings stores the optional settings managed by /settings
+func (store *MemoryStore) UpdateServerSettings(server model.Servers) error {
+	store.updateServer(server.ID, func(s *model.Servers) {
+		s.MemberLeaveAction = server.MemberLeaveAction
+		s.RoleSyncEnabled = server.RoleSyncEnabled
+		s.NicknameSyncEnabled = server.NicknameSyncEnabled
+		s.NicknameAccountTypePrefix = server.NicknameAccountTypePrefix
+		s.CatchUpEnabled = server.CatchUpEnabled
+		s.CatchUpMaxLatenessHours = server.CatchUpMaxLatenessHours
+		s.OrphanedMessageAction = server.OrphanedMessageAction
+		s.AdminChannelID = server.AdminChannelID
+		s.StaleDataMaxAgeHours = server.StaleDataMaxAgeHours
+	})
+
+	return nil
+}
+
+// UpdateRankReportSchedule stores how often a server wants its rank report posted
+func (store *MemoryStore) UpdateRankReportSchedule(serverID string, schedule string) error {
+	store.updateServer(serverID, func(s *model.Servers) {
+		s.RankReportSchedule = schedule
+	})
+
+	return nil
+}
+
+func (store *MemoryStore) updateServer(serverID string, update func(*model.Servers)) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	for i := range store.servers {
+		if store.servers[i].ID == serverID {
+			update(&store.servers[i])
+		}
+	}
+}
+
+// EnrollUser creates or updates a user
+func (store *MemoryStore) EnrollUser(user model.Users) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	i := slices.IndexFunc(store.users, func(u model.Users) bool {
+		return u.ServerID == user.ServerID && u.OsrsUsernameKey == user.OsrsUsernameKey
+	})
+	if i == -1 {
+		store.users = append(store.users, user)
+		return nil
+	}
+
+	existing := &store.users[i]
+	existing.OsrsUsername = user.OsrsUsername
+	existing.OsrsAccountType = user.OsrsAccountType
+	existing.DiscordUsername = user.DiscordUsername
+	existing.DiscordUserID = user.DiscordUserID
+	existing.IsArchived = user.IsArchived
+
+	return nil
+}
+
+// RemoveUser removes a user from a specific server
+func (store *MemoryStore) RemoveUser(user model.Users) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.users = slices.DeleteFunc(store.users, func(u model.Users) bool {
+		return u.ServerID == user.ServerID && u.OsrsUsernameKey == user.OsrsUsernameKey
+	})
+
+	return nil
+}
+
+// RemoveUsersByDiscordID removes every OSRS user linked to a Discord member
+func (store *MemoryStore) RemoveUsersByDiscordID(serverID string, discordUserID string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.users = slices.DeleteFunc(store.users, func(u model.Users) bool {
+		return u.ServerID == serverID && u.DiscordUserID == discordUserID
+	})
+
+	return nil
+}
+
+// SetUsersArchived archives (or restores) every OSRS user linked to a Discord member
+func (store *MemoryStore) SetUsersArchived(serverID string, discordUserID string, archived bool) error {
+	store.updateMemberUsers(serverID, discordUserID, func(u *model.Users) {
+		u.IsArchived = archived
+	})
+
+	return nil
+}
+
+// RenameUser changes the RSN of an already enrolled user
+func (store *MemoryStore) RenameUser(user model.Users, newOsrsUsernameKey string, newOsrsUsername string) error {
+	store.updateUser(user, func(u *model.Users) {
+		u.OsrsUsernameKey = newOsrsUsernameKey
+		u.OsrsUsername = newOsrsUsername
+		u.PossiblyRenamed = false
+	})
+
+	if newOsrsUsernameKey == user.OsrsUsernameKey {
+		return nil
+	}
+
+	// The player keeps their last hiscores under the new name. Other servers
+	// may still track the old name so its snapshots are left to expire
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	for _, s := range slices.Clone(store.snapshots) {
+		if s.OsrsUsername != user.OsrsUsernameKey {
+			continue
+		}
+
+		exists := slices.ContainsFunc(store.snapshots, func(e model.HiscoresSnapshots) bool {
+			return e.OsrsUsername == newOsrsUsernameKey && e.OsrsAccountType == s.OsrsAccountType && e.Leaderboard == s.Leaderboard
+		})
+		if !exists {
+			s.OsrsUsername = newOsrsUsernameKey
+			store.snapshots = append(store.snapshots, s)
+		}
+	}
+
+	return nil
+}
+
+// SetUserPossiblyRenamed flags (or unflags) a user whose RSN can't be found
+func (store *MemoryStore) SetUserPossiblyRenamed(user model.Users, possiblyRenamed bool) error {
+	store.updateUser(user, func(u *model.Users) {
+		u.PossiblyRenamed = possiblyRenamed
+	})
+
+	return nil
+}
+
+// SetPrimaryUser marks an OSRS user as the primary account of its Discord member
+func (store *MemoryStore) SetPrimaryUser(user model.Users) error {
+	store.updateMemberUsers(user.ServerID, user.DiscordUserID, func(u *model.Users) {
+		u.IsPrimary = u.OsrsUsernameKey == user.OsrsUsernameKey
+	})
+
+	return nil
+}
+
+// FetchPrimaryUser returns the primary OSRS user linked to a Discord member
+// falling back to any of them. Returns ErrNotFound if there are none
+func (store *MemoryStore) FetchPrimaryUser(serverID string, discordUserID string) (model.Users, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	candidates := []model.Users{}
+	for _, u := range store.users {
+		if u.ServerID == serverID && u.DiscordUserID == discordUserID && !u.IsArchived {
+			candidates = append(candidates, u)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return model.Users{}, ErrNotFound
+	}
+
+	slices.SortStableFunc(candidates, func(a, b model.Users) int {
+		if a.IsPrimary != b.IsPrimary {
+			if a.IsPrimary {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(a.OsrsUsernameKey, b.OsrsUsernameKey)
+	})
+
+	return candidates[0], nil
+}
+
+// SetUserClanRank records the in-game clan rank a user currently holds
+func (store *MemoryStore) SetUserClanRank(user model.Users, clanRank string) error {
+	store.updateUser(user, func(u *model.Users) {
+		u.ClanRank = clanRank
+	})
+
+	return nil
+}
+
+// UpdateDiscordUsername refreshes the stored Discord username of a Discord member
+func (store *MemoryStore) UpdateDiscordUsername(serverID string, discordUserID string, discordUsername string) error {
+	store.updateMemberUsers(serverID, discordUserID, func(u *model.Users) {
+		u.DiscordUsername = discordUsername
+	})
+
+	return nil
+}
+
+// FetchAllUsers returns every user in a server that isn't archived
+func (store *MemoryStore) FetchAllUsers(serverID string) ([]model.Users, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	var allUsers []model.Users
+	for _, u := range store.users {
+		if u.ServerID == serverID && !u.IsArchived {
+			allUsers = append(allUsers, u)
+		}
+	}
+
+	return allUsers, nil
+}
+
+// FetchUser returns a single user or ErrNotFound
+func (store *MemoryStore) FetchUser(serverID string, osrsUsername string) (model.Users, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	for _, u := range store.users {
+		if u.ServerID == serverID && u.OsrsUsernameKey == osrsUsername {
+			return u, nil
+		}
+	}
+
+	return model.Users{}, ErrNotFound
+}
+
+func (store *MemoryStore) updateUser(user model.Users, update func(*model.Users)) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	for i := range store.users {
+		if store.users[i].ServerID == user.ServerID && store.users[i].OsrsUsernameKey == user.OsrsUsernameKey {
+			update(&store.users[i])
+		}
+	}
+}
+
+func (store *MemoryStore) updateMemberUsers(serverID string, discordUserID string, update func(*model.Users)) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	for i := range store.users {
+		if store.users[i].ServerID == serverID && store.users[i].DiscordUserID == discordUserID {
+			update(&store.users[i])
+		}
+	}
+}
+
+// FetchAllActivitiesAndSkills returns the unique activities of a board in order of position
+func (store *MemoryStore) FetchAllActivitiesAndSkills(serverID string, boardName string) ([]string, error) {
+	messages, _ := store.FetchAllMessages(serverID, boardName)
+
+	var activities []string
+	for _, m := range messages {
+		if !slices.Contains(activities, m.Activity) {
+			activities = append(activities, m.Activity)
+		}
+	}
+
+	return activities, nil
+}
+
+// FetchActivityPosition returns the position of an activity or -1 if it isn't on the board
+func (store *MemoryStore) FetchActivityPosition(serverID string, boardName string, activity string) (int32, error) {
+	messages, _ := store.FetchMessage(serverID, boardName, activity)
+	if len(messages) > 0 {
+		return messages[0].Position, nil
+	}
+
+	return -1, nil
+}
+
+// FetchAllMessages returns every message of a board in order of position
+func (store *MemoryStore) FetchAllMessages(serverID string, boardName string) ([]model.Messages, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	var messages []model.Messages
+	for _, m := range store.messages {
+		if m.ServerID == serverID && m.BoardName == boardName {
+			messages = append(messages, m)
+		}
+	}
+
+	slices.SortStableFunc(messages, func(a, b model.Messages) int {
+		return cmp.Compare(a.Position, b.Position)
+	})
+
+	return messages, nil
+}
+
+// FetchMessage returns the messages of a board for a single activity
+func (store *MemoryStore) FetchMessage(serverID string, boardName string, activity string) ([]model.Messages, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	var messages []model.Messages
+	for _, m := range store.messages {
+		if m.ServerID == serverID && m.BoardName == boardName && m.Activity == activity {
+			messages = append(messages, m)
+		}
+	}
+
+	return messages, nil
+}
+
+// EnrollMessage creates or updates a message
+func (store *MemoryStore) EnrollMessage(message model.Messages) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	i := slices.IndexFunc(store.messages, func(m model.Messages) bool {
+		return sameMessage(m, message)
+	})
+	if i == -1 {
+		store.messages = append(store.messages, message)
+	} else {
+		store.messages[i].Position = message.Position
+	}
+
+	return nil
+}
+
+// RemoveMessage removes a single message
+func (store *MemoryStore) RemoveMessage(message model.Messages) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.messages = slices.DeleteFunc(store.messages, func(m model.Messages) bool {
+		return sameMessage(m, message)
+	})
+
+	return nil
+}
+
+// ResetMessages replaces every message of a server with one placeholder per activity on each board
+func (store *MemoryStore) ResetMessages(serverID string) error {
+	boards, _ := store.FetchBoards(serverID)
+
+	placeholders := []model.Messages{}
+	for _, board := range boards {
+		activities, _ := store.FetchAllActivitiesAndSkills(serverID, board.BoardName)
+		for position, activity := range activities {
+			placeholders = append(placeholders, model.Messages{
+				MessageID: "",
+				ServerID:  serverID,
+				BoardName: board.BoardName,
+				Activity:  activity,
+				Position:  int32(position),
+			})
+		}
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.messages = slices.DeleteFunc(store.messages, func(m model.Messages) bool {
+		return m.ServerID == serverID
+	})
+	store.messages = append(store.messages, placeholders...)
+
+	return nil
+}
+
+func sameMessage(a, b model.Messages) bool {
+	return a.MessageID == b.MessageID && a.ServerID == b.ServerID && a.BoardName == b.BoardName && a.Activity == b.Activity
+}
+
+// FetchRoleRules returns every role rule of a server ordered by role and activity
+func (store *MemoryStore) FetchRoleRules(serverID string) ([]model.RoleRules, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	var rules []model.RoleRules
+	for _, r := range store.roleRules {
+		if r.ServerID == serverID {
+			rules = append(rules, r)
+		}
+	}
+
+	slices.SortFunc(rules, func(a, b model.RoleRules) int {
+		return cmp.Or(cmp.Compare(a.RoleID, b.RoleID), cmp.Compare(a.Activity, b.Activity))
+	})
+
+	return rules, nil
+}
+
+// EnrollRoleRule creates or updates a role rule
+func (store *MemoryStore) EnrollRoleRule(rule model.RoleRules) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	i := slices.IndexFunc(store.roleRules, func(r model.RoleRules) bool {
+		return r.ServerID == rule.ServerID && r.RoleID == rule.RoleID && r.Activity == rule.Activity
+	})
+	if i == -1 {
+		store.roleRules = append(store.roleRules, rule)
+	} else {
+		store.roleRules[i].Threshold = rule.Threshold
+	}
+
+	return nil
+}
+
+// RemoveRoleRules removes the rule for a role and activity or every rule
+// for the role if no activity is given
+func (store *MemoryStore) RemoveRoleRules(serverID string, roleID string, activity string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.roleRules = slices.DeleteFunc(store.roleRules, func(r model.RoleRules) bool {
+		return r.ServerID == serverID && r.RoleID == roleID && (activity == "" || r.Activity == activity)
+	})
+
+	return nil
+}
+
+// FetchRanks returns a server's rank ladder from the highest rank to the lowest
+func (store *MemoryStore) FetchRanks(serverID string) ([]model.Ranks, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	var ranks []model.Ranks
+	for _, r := range store.ranks {
+		if r.ServerID == serverID {
+			ranks = append(ranks, r)
+		}
+	}
+
+	slices.SortStableFunc(ranks, func(a, b model.Ranks) int {
+		return cmp.Compare(b.Position, a.Position)
+	})
+
+	return ranks, nil
+}
+
+// EnrollRank creates or updates a rank
+func (store *MemoryStore) EnrollRank(rank model.Ranks) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	i := slices.IndexFunc(store.ranks, func(r model.Ranks) bool {
+		return r.ServerID == rank.ServerID && r.RankName == rank.RankName
+	})
+	if i == -1 {
+		store.ranks = append(store.ranks, rank)
+	} else {
+		store.ranks[i].Position = rank.Position
+		store.ranks[i].Requirements = rank.Requirements
+	}
+
+	return nil
+}
+
+// RemoveRank removes a rank from a server's rank ladder
+func (store *MemoryStore) RemoveRank(serverID string, rankName string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.ranks = slices.DeleteFunc(store.ranks, func(r model.Ranks) bool {
+		return r.ServerID == serverID && r.RankName == rankName
+	})
+
+	return nil
+}
+
+// RecordScheduleRun stores how a board's run went along with the players it
+// couldn't fetch and forgets the board's runs that are older than scheduleRunRetention
+func (store *MemoryStore) RecordScheduleRun(run model.ScheduleRuns, failures []model.RunFetchFailures) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	cutoff := run.StartedAt.Add(-scheduleRunRetention)
+	store.runs = slices.DeleteFunc(store.runs, func(r model.ScheduleRuns) bool {
+		return r.ServerID == run.ServerID && r.BoardName == run.BoardName && r.StartedAt.Before(cutoff)
+	})
+	store.failures = slices.DeleteFunc(store.failures, func(f model.RunFetchFailures) bool {
+		return f.ServerID == run.ServerID && f.BoardName == run.BoardName && f.StartedAt.Before(cutoff)
+	})
+	store.runs = append(store.runs, run)
+	store.failures = append(store.failures, failures...)
+
+	return nil
+}
+
+// FetchRunFetchFailures returns the players a run of a board couldn't fetch
+func (store *MemoryStore) FetchRunFetchFailures(run model.ScheduleRuns) ([]model.RunFetchFailures, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	failures := []model.RunFetchFailures{}
+	for _, f := range store.failures {
+		if f.ServerID == run.ServerID && f.BoardName == run.BoardName && f.StartedAt.Equal(run.StartedAt) {
+			failures = append(failures, f)
+		}
+	}
+
+	slices.SortFunc(failures, func(a, b model.RunFetchFailures) int {
+		return cmp.Compare(a.OsrsUsername, b.OsrsUsername)
+	})
+
+	return failures, nil
+}
+
+// FetchLastScheduleRun returns the most recent run of a board or ErrNotFound
+func (store *MemoryStore) FetchLastScheduleRun(serverID string, boardName string) (model.ScheduleRuns, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	var last *model.ScheduleRuns
+	for i, r := range store.runs {
+		if r.ServerID == serverID && r.BoardName == boardName && (last == nil || r.StartedAt.After(last.StartedAt)) {
+			last = &store.runs[i]
+		}
+	}
+
+	if last == nil {
+		return model.ScheduleRuns{}, ErrNotFound
+	}
+
+	return *last, nil
+}
+
+// FetchLastSuccessfulScheduleRun returns the most recent run of a board
+// that posted every message or ErrNotFound
+func (store *MemoryStore) FetchLastSuccessfulScheduleRun(serverID string, boardName string) (model.ScheduleRuns, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	var last *model.ScheduleRuns
+	for i, r := range store.runs {
+		if r.ServerID == serverID && r.BoardName == boardName && r.Outcome == types.RunOutcomeSuccess &&
+			(last == nil || r.StartedAt.After(last.StartedAt)) {
+			last = &store.runs[i]
+		}
+	}
+
+	if last == nil {
+		return model.ScheduleRuns{}, ErrNotFound
+	}
+
+	return *last, nil
+}
+
+// SaveHiscoresSnapshots stores the latest hiscores we fetched for some players
+// and forgets snapshots that are older than hiscoresSnapshotRetention
+func (store *MemoryStore) SaveHiscoresSnapshots(snapshots []model.HiscoresSnapshots) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	for _, snapshot := range snapshots {
+		i := slices.IndexFunc(store.snapshots, func(s model.HiscoresSnapshots) bool {
+			return s.OsrsUsername == snapshot.OsrsUsername &&
+				s.OsrsAccountType == snapshot.OsrsAccountType &&
+				s.Leaderboard == snapshot.Leaderboard
+		})
+		if i == -1 {
+			store.snapshots = append(store.snapshots, snapshot)
+		} else {
+			store.snapshots[i] = snapshot
+		}
+	}
+
+	cutoff := time.Now().UTC().Add(-hiscoresSnapshotRetention)
+	store.snapshots = slices.DeleteFunc(store.snapshots, func(s model.HiscoresSnapshots) bool {
+		return s.FetchedAt.Before(cutoff)
+	})
+
+	return nil
+}
+
+// FetchHiscoresSnapshot returns the last hiscores we fetched for a player on a
+// leaderboard or ErrNotFound. An empty leaderboard is the hiscores of the
+// player's account type
+func (store *MemoryStore) FetchHiscoresSnapshot(osrsUsername string, accountType string, leaderboard string) (model.HiscoresSnapshots, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	for _, s := range store.snapshots {
+		if s.OsrsUsername == osrsUsername && s.OsrsAccountType == accountType && s.Leaderboard == leaderboard {
+			return s, nil
+		}
+	}
+
+	return model.HiscoresSnapshots{}, ErrNotFound
+}
+
+// AcquireRunLock takes the lock that lets a single run post a board. It
+// returns false if someone else holds a lock that hasn't expired yet
+func (store *MemoryStore) AcquireRunLock(lock model.RunLocks) (bool, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	i := slices.IndexFunc(store.runLocks, func(l model.RunLocks) bool {
+		return l.ServerID == lock.ServerID && l.BoardName == lock.BoardName
+	})
+	if i == -1 {
+		store.runLocks = append(store.runLocks, lock)
+		return true, nil
+	}
+
+	if !store.runLocks[i].ExpiresAt.Before(lock.AcquiredAt) {
+		return false, nil
+	}
+
+	store.runLocks[i] = lock
+	return true, nil
+}
+
+// ReleaseRunLock gives up a board's lock if we're the ones holding it
+func (store *MemoryStore) ReleaseRunLock(serverID string, boardName string, owner string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.runLocks = slices.DeleteFunc(store.runLocks, func(l model.RunLocks) bool {
+		return l.ServerID == serverID && l.BoardName == boardName && l.Owner == owner
+	})
+
+	return nil
+}
@@ -0,0 +1,278 @@
+package storage
+
+import (
+	"embed"
+	"fmt"
+	"log"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/table"
+)
+
+// migrationFiles are the SQL files that make up our schema. Each file is
+// named <version>_<name>.sql and they are applied in order of version
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// schemaMigrationsTable keeps track of which migrations have been applied.
+// It isn't a migration itself since we need it to exist before we can
+// tell what has or hasn't been applied
+const schemaMigrationsTable = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version    INTEGER   NOT NULL PRIMARY KEY,
+    name       TEXT      NOT NULL DEFAULT '',
+    applied_at TIMESTAMP NOT NULL
+);
+`
+
+// lastLegacyMigration is the last migration that adds something a database
+// created before migrations existed may already have. Those databases got the
+// tables of whichever version of the bot created them so some of the columns
+// and tables added by 0002 to 0006 can already be in place
+const lastLegacyMigration = 6
+
+var (
+	addColumnStatement   = regexp.MustCompile(`(?i)^ALTER TABLE\s+(\w+)\s+ADD COLUMN\s+(\w+)`)
+	createTableStatement = regexp.MustCompile(`(?i)^CREATE TABLE\s+(\w+)\s*\(`)
+)
+
+// Migration is a single versioned change to our schema
+type Migration struct {
+	Version int32
+	Name    string
+	SQL     string
+}
+
+// MigrationState is a migration along with when (if ever) it was applied
+type MigrationState struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// LoadMigrations reads every embedded migration and returns them sorted by version
+func LoadMigrations() ([]Migration, error) {
+	entries, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := []Migration{}
+	for _, entry := range entries {
+		fileName := entry.Name()
+
+		versionPart, name, found := strings.Cut(strings.TrimSuffix(fileName, ".sql"), "_")
+		if !found {
+			return nil, fmt.Errorf("Migration %s must be named <version>_<name>.sql", fileName)
+		}
+
+		version, err := strconv.ParseInt(versionPart, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("Migration %s has an invalid version: %w", fileName, err)
+		}
+
+		contents, err := migrationFiles.ReadFile(path.Join("migrations", fileName))
+		if err != nil {
+			return nil, err
+		}
+
+		migrations = append(migrations, Migration{
+			Version: int32(version),
+			Name:    name,
+			SQL:     string(contents),
+		})
+	}
+
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return int(a.Version - b.Version)
+	})
+
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version == migrations[i-1].Version {
+			return nil, fmt.Errorf("Found more than one migration with version %d", migrations[i].Version)
+		}
+	}
+
+	return migrations, nil
+}
+
+// migrator is implemented by every store that keeps its schema in the
+// embedded migrations. Only reading and writing the schema_migrations table
+// differs between databases so everything else is shared
+type migrator interface {
+	// fetchAppliedMigrations returns when each applied migration was applied keyed by version
+	fetchAppliedMigrations() (map[int32]time.Time, error)
+
+	// applyMigration runs a migration and records that it ran in a single transaction
+	applyMigration(m Migration) error
+}
+
+// migrationStatus returns every known migration and when it was applied.
+// Migrations that are still pending have no AppliedAt
+func migrationStatus(target migrator) ([]MigrationState, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := target.fetchAppliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	states := []MigrationState{}
+	for _, m := range migrations {
+		state := MigrationState{Migration: m}
+		if appliedAt, ok := applied[m.Version]; ok {
+			state.AppliedAt = &appliedAt
+		}
+		states = append(states, state)
+	}
+
+	return states, nil
+}
+
+// migrate applies every pending migration in order. Each migration is
+// applied in its own transaction along with the record that it ran so
+// a failure never leaves a migration half applied
+func migrate(target migrator) error {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return err
+	}
+
+	applied, err := target.fetchAppliedMigrations()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+
+		log.Printf("Applying migration %04d_%s\n", m.Version, m.Name)
+
+		err = target.applyMigration(m)
+		if err != nil {
+			return fmt.Errorf("Unable to apply migration %04d_%s: %w", m.Version, m.Name, err)
+		}
+	}
+
+	log.Println("Database schema is up to date")
+
+	return nil
+}
+
+// MigrationStatus returns every known migration and when it was applied
+func (store *SQLiteStore) MigrationStatus() ([]MigrationState, error) {
+	return migrationStatus(store)
+}
+
+// Migrate applies every pending migration in order
+func (store *SQLiteStore) Migrate() error {
+	return migrate(store)
+}
+
+func (store *SQLiteStore) fetchAppliedMigrations() (map[int32]time.Time, error) {
+	_, err := store.db.Exec(schemaMigrationsTable)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []model.SchemaMigrations
+	err = table.SchemaMigrations.
+		SELECT(table.SchemaMigrations.AllColumns).
+		Query(store.db, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := map[int32]time.Time{}
+	for _, row := range rows {
+		applied[row.Version] = row.AppliedAt
+	}
+
+	return applied, nil
+}
+
+func (store *SQLiteStore) applyMigration(m Migration) error {
+	statements, err := store.pendingStatements(m)
+	if err != nil {
+		return err
+	}
+
+	return store.inTransaction(func(txStore *SQLiteStore) error {
+		if statements != "" {
+			_, err := txStore.conn.Exec(statements)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err := table.SchemaMigrations.
+			INSERT(table.SchemaMigrations.AllColumns).
+			MODEL(model.SchemaMigrations{
+				Version:   m.Version,
+				Name:      m.Name,
+				AppliedAt: time.Now().UTC(),
+			}).
+			Exec(txStore.conn)
+
+		return err
+	})
+}
+
+// pendingStatements returns the SQL of a migration without the statements
+// that add a column or table the database already has. Only legacy
+// migrations are checked, every later migration is applied as written
+func (store *SQLiteStore) pendingStatements(m Migration) (string, error) {
+	if m.Version > lastLegacyMigration {
+		return m.SQL, nil
+	}
+
+	statements := []string{}
+	for statement := range strings.SplitSeq(m.SQL, ";") {
+		statement = strings.TrimSpace(statement)
+		if statement == "" {
+			continue
+		}
+
+		exists := false
+		var err error
+		if match := addColumnStatement.FindStringSubmatch(statement); match != nil {
+			exists, err = store.hasSchemaObject("SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", match[1], match[2])
+		} else if match := createTableStatement.FindStringSubmatch(statement); match != nil {
+			exists, err = store.hasSchemaObject("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", match[1])
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if exists {
+			log.Printf("Skipping part of migration %04d_%s that is already in place: %s\n", m.Version, m.Name, strings.SplitN(statement, "\n", 2)[0])
+			continue
+		}
+
+		statements = append(statements, statement)
+	}
+
+	return strings.Join(statements, ";\n"), nil
+}
+
+// hasSchemaObject runs a query that counts columns or tables and reports if it found any
+func (store *SQLiteStore) hasSchemaObject(query string, args ...any) (bool, error) {
+	var count int
+	err := store.db.QueryRow(query, args...).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
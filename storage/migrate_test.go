@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"testing"
+)
+
+// legacySchema is what the last version of the bot before migrations
+// existed created on start up. It already has everything 0002 to 0006 add
+const legacySchema = `
+CREATE TABLE IF NOT EXISTS servers (
+	id                           TEXT    NOT NULL PRIMARY KEY,
+	server_name                  TEXT    NOT NULL DEFAULT "",
+	channel_name                 TEXT    NOT NULL DEFAULT "",
+	schedule                     TEXT    NOT NULL DEFAULT "",
+	should_edit_message          BOOLEAN NOT NULL DEFAULT true,
+	is_enabled                   BOOLEAN NOT NULL DEFAULT true,
+	disabled_reason              TEXT    NOT NULL DEFAULT "",
+	member_leave_action          TEXT    NOT NULL DEFAULT "keep",
+	role_sync_enabled            BOOLEAN NOT NULL DEFAULT false,
+	nickname_sync_enabled        BOOLEAN NOT NULL DEFAULT false,
+	nickname_account_type_prefix BOOLEAN NOT NULL DEFAULT false,
+	rank_report_schedule         TEXT    NOT NULL DEFAULT ""
+);
+CREATE TABLE IF NOT EXISTS users (
+	osrs_username_key TEXT    NOT NULL DEFAULT "",
+	server_id         TEXT    NOT NULL DEFAULT "",
+	osrs_username     TEXT    NOT NULL DEFAULT "",
+	osrs_account_type TEXT    NOT NULL DEFAULT "",
+	discord_username  TEXT    NOT NULL DEFAULT "",
+	discord_user_id   TEXT    NOT NULL DEFAULT "",
+	is_archived       BOOLEAN NOT NULL DEFAULT false,
+	possibly_renamed  BOOLEAN NOT NULL DEFAULT false,
+	is_primary        BOOLEAN NOT NULL DEFAULT false,
+	clan_rank         TEXT    NOT NULL DEFAULT "",
+	PRIMARY KEY (osrs_username_key, server_id)
+);
+CREATE TABLE IF NOT EXISTS messages (
+	message_id TEXT    NOT NULL DEFAULT "",
+	server_id  TEXT    NOT NULL DEFAULT "",
+	activity   TEXT    NOT NULL DEFAULT "",
+	position   INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (message_id, server_id, activity)
+);
+CREATE TABLE IF NOT EXISTS role_rules (
+	server_id TEXT    NOT NULL DEFAULT "",
+	role_id   TEXT    NOT NULL DEFAULT "",
+	activity  TEXT    NOT NULL DEFAULT "",
+	threshold INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (server_id, role_id, activity)
+);
+CREATE TABLE IF NOT EXISTS ranks (
+	server_id    TEXT    NOT NULL DEFAULT "",
+	rank_name    TEXT    NOT NULL DEFAULT "",
+	position     INTEGER NOT NULL DEFAULT 0,
+	requirements TEXT    NOT NULL DEFAULT "",
+	PRIMARY KEY (server_id, rank_name)
+);
+INSERT INTO servers (id, server_name, channel_name, schedule, rank_report_schedule)
+	VALUES ('1', 'Test Server', 'hiscores', '0 19 * * SUN', '0 12 * * MON');
+INSERT INTO ranks (server_id, rank_name, position, requirements)
+	VALUES ('1', 'Sapphire', 1, 'Overall:1000');
+`
+
+func TestMigrateLegacyDatabase(t *testing.T) {
+	st := newTestSQLiteStore(t)
+
+	_, err := st.db.Exec(legacySchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = st.Migrate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	states, err := st.MigrationStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, state := range states {
+		if state.AppliedAt == nil {
+			t.Errorf("migration %04d_%s wasn't applied", state.Version, state.Name)
+		}
+	}
+
+	server, err := st.FetchServer("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server.RankReportSchedule != "0 12 * * MON" {
+		t.Errorf("server settings weren't kept: %+v", server)
+	}
+
+	ranks, err := st.FetchRanks("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ranks) != 1 {
+		t.Errorf("ranks weren't kept: %+v", ranks)
+	}
+}
@@ -0,0 +1,30 @@
+-- The original schema. Databases created before migrations existed
+-- already have these tables so everything here must be idempotent.
+-- Some of them also have columns and tables from 0002 to 0006 which
+-- the migration runner skips when it applies those migrations
+CREATE TABLE IF NOT EXISTS servers (
+    id                  TEXT    NOT NULL PRIMARY KEY,
+    server_name         TEXT    NOT NULL DEFAULT '',
+    channel_name        TEXT    NOT NULL DEFAULT '',
+    schedule            TEXT    NOT NULL DEFAULT '',
+    should_edit_message BOOLEAN NOT NULL DEFAULT true,
+    is_enabled          BOOLEAN NOT NULL DEFAULT true
+);
+
+CREATE TABLE IF NOT EXISTS users (
+    osrs_username_key TEXT NOT NULL DEFAULT '',
+    server_id         TEXT NOT NULL DEFAULT '',
+    osrs_username     TEXT NOT NULL DEFAULT '',
+    osrs_account_type TEXT NOT NULL DEFAULT '',
+    discord_username  TEXT NOT NULL DEFAULT '',
+    discord_user_id   TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (osrs_username_key, server_id)
+);
+
+CREATE TABLE IF NOT EXISTS messages (
+    message_id TEXT    NOT NULL DEFAULT '',
+    server_id  TEXT    NOT NULL DEFAULT '',
+    activity   TEXT    NOT NULL DEFAULT '',
+    position   INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (message_id, server_id, activity)
+);
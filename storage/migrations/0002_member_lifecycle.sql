@@ -0,0 +1,3 @@
+ALTER TABLE servers ADD COLUMN disabled_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE servers ADD COLUMN member_leave_action TEXT NOT NULL DEFAULT 'keep';
+ALTER TABLE users ADD COLUMN is_archived BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN possibly_renamed BOOLEAN NOT NULL DEFAULT false;
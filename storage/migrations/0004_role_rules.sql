@@ -0,0 +1,9 @@
+ALTER TABLE servers ADD COLUMN role_sync_enabled BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE role_rules (
+    server_id TEXT    NOT NULL DEFAULT '',
+    role_id   TEXT    NOT NULL DEFAULT '',
+    activity  TEXT    NOT NULL DEFAULT '',
+    threshold INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (server_id, role_id, activity)
+);
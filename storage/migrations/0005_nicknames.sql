@@ -0,0 +1,3 @@
+ALTER TABLE servers ADD COLUMN nickname_sync_enabled BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE servers ADD COLUMN nickname_account_type_prefix BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE users ADD COLUMN is_primary BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,10 @@
+ALTER TABLE servers ADD COLUMN rank_report_schedule TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN clan_rank TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE ranks (
+    server_id    TEXT    NOT NULL DEFAULT '',
+    rank_name    TEXT    NOT NULL DEFAULT '',
+    position     INTEGER NOT NULL DEFAULT 0,
+    requirements TEXT    NOT NULL DEFAULT '',
+    PRIMARY KEY (server_id, rank_name)
+);
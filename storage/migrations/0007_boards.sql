@@ -0,0 +1,37 @@
+-- A server can now have many leaderboards (boards) each with its own
+-- channel, schedule, activities and display options. The existing
+-- configuration of every server becomes a board named "default"
+CREATE TABLE boards (
+    server_id           TEXT    NOT NULL DEFAULT '',
+    board_name          TEXT    NOT NULL DEFAULT '',
+    channel_name        TEXT    NOT NULL DEFAULT '',
+    schedule            TEXT    NOT NULL DEFAULT '',
+    should_edit_message BOOLEAN NOT NULL DEFAULT true,
+    hide_unranked       BOOLEAN NOT NULL DEFAULT true,
+    show_rank           BOOLEAN NOT NULL DEFAULT false,
+    PRIMARY KEY (server_id, board_name)
+);
+
+INSERT INTO boards (server_id, board_name, channel_name, schedule, should_edit_message)
+SELECT id, 'default', channel_name, schedule, should_edit_message FROM servers;
+
+-- Messages now belong to a board. The primary key has to change so the
+-- table is rebuilt since SQLite can't alter a primary key in place
+CREATE TABLE board_messages (
+    message_id TEXT    NOT NULL DEFAULT '',
+    server_id  TEXT    NOT NULL DEFAULT '',
+    board_name TEXT    NOT NULL DEFAULT '',
+    activity   TEXT    NOT NULL DEFAULT '',
+    position   INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (message_id, server_id, board_name, activity)
+);
+
+INSERT INTO board_messages (message_id, server_id, board_name, activity, position)
+SELECT message_id, server_id, 'default', activity, position FROM messages;
+
+DROP TABLE messages;
+ALTER TABLE board_messages RENAME TO messages;
+
+ALTER TABLE servers DROP COLUMN channel_name;
+ALTER TABLE servers DROP COLUMN schedule;
+ALTER TABLE servers DROP COLUMN should_edit_message;
@@ -0,0 +1,5 @@
+-- Boards remember the ID of their channel so renaming the channel doesn't
+-- break posting. Existing boards only know the channel name so the bot
+-- resolves their IDs from Discord the next time it connects to the server.
+-- The name is kept so we can tell admins which channel went missing
+ALTER TABLE boards ADD COLUMN channel_id TEXT NOT NULL DEFAULT '';
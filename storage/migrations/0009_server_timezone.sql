@@ -0,0 +1,3 @@
+-- Schedules run in the timezone chosen by each server. Until now they ran
+-- in the bot's local timezone which is UTC in our container image
+ALTER TABLE servers ADD COLUMN timezone TEXT NOT NULL DEFAULT 'UTC';
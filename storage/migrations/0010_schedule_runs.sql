@@ -0,0 +1,12 @@
+-- History of every time a board was posted, either on its schedule or
+-- with /post, so admins can check when it last ran and whether it worked
+CREATE TABLE schedule_runs (
+    server_id     TEXT      NOT NULL DEFAULT '',
+    board_name    TEXT      NOT NULL DEFAULT '',
+    started_at    TIMESTAMP NOT NULL,
+    duration_ms   INTEGER   NOT NULL DEFAULT 0,
+    triggered_by  TEXT      NOT NULL DEFAULT '',
+    outcome       TEXT      NOT NULL DEFAULT '',
+    error_message TEXT      NOT NULL DEFAULT '',
+    PRIMARY KEY (server_id, board_name, started_at)
+);
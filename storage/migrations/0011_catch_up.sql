@@ -0,0 +1,4 @@
+-- Scheduled posts missed while the bot was offline are posted once when it
+-- starts again, unless they are more than catch_up_max_lateness_hours late
+ALTER TABLE servers ADD COLUMN catch_up_enabled BOOLEAN NOT NULL DEFAULT true;
+ALTER TABLE servers ADD COLUMN catch_up_max_lateness_hours INTEGER NOT NULL DEFAULT 24;
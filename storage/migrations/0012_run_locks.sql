@@ -0,0 +1,11 @@
+-- A board can only be posted by one run at a time, even across several
+-- copies of the bot sharing a database. Locks expire so a bot that dies
+-- part way through a run doesn't block the board forever
+CREATE TABLE run_locks (
+    server_id   TEXT      NOT NULL DEFAULT '',
+    board_name  TEXT      NOT NULL DEFAULT '',
+    owner       TEXT      NOT NULL DEFAULT '',
+    acquired_at TIMESTAMP NOT NULL,
+    expires_at  TIMESTAMP NOT NULL,
+    PRIMARY KEY (server_id, board_name)
+);
@@ -0,0 +1,3 @@
+-- What happens to leaderboard messages the bot no longer updates because
+-- their activity was removed or the board moved to another channel
+ALTER TABLE servers ADD COLUMN orphaned_message_action TEXT NOT NULL DEFAULT 'delete';
@@ -0,0 +1,14 @@
+-- Players whose hiscores couldn't be fetched during a run of a board. They
+-- were left out of the leaderboards that run posted
+CREATE TABLE run_fetch_failures (
+    server_id         TEXT      NOT NULL DEFAULT '',
+    board_name        TEXT      NOT NULL DEFAULT '',
+    started_at        TIMESTAMP NOT NULL,
+    osrs_username     TEXT      NOT NULL DEFAULT '',
+    osrs_account_type TEXT      NOT NULL DEFAULT '',
+    reason            TEXT      NOT NULL DEFAULT '',
+    PRIMARY KEY (server_id, board_name, started_at, osrs_username, osrs_account_type)
+);
+
+-- The channel detailed reports for admins are sent to. Empty sends them nowhere
+ALTER TABLE servers ADD COLUMN admin_channel_id TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,12 @@
+-- The last hiscores we fetched for each player on each leaderboard. They are
+-- shown instead when a fresh fetch fails, e.g. during Jagex maintenance
+CREATE TABLE hiscores_snapshots (
+    osrs_username     TEXT      NOT NULL DEFAULT '',
+    osrs_account_type TEXT      NOT NULL DEFAULT '',
+    fetched_at        TIMESTAMP NOT NULL,
+    hiscores          TEXT      NOT NULL DEFAULT '',
+    PRIMARY KEY (osrs_username, osrs_account_type)
+);
+
+-- How old a snapshot can be and still be shown. 0 never shows snapshots
+ALTER TABLE servers ADD COLUMN stale_data_max_age_hours INTEGER NOT NULL DEFAULT 168;
@@ -0,0 +1,19 @@
+-- Snapshots are kept per leaderboard so seasonal hiscores never stand in
+-- for a player's main hiscores or the other way around. The primary key
+-- can't be changed in place so the table is copied into a new one
+CREATE TABLE hiscores_snapshots_by_leaderboard (
+    osrs_username     TEXT      NOT NULL DEFAULT '',
+    osrs_account_type TEXT      NOT NULL DEFAULT '',
+    leaderboard       TEXT      NOT NULL DEFAULT '',
+    fetched_at        TIMESTAMP NOT NULL,
+    hiscores          TEXT      NOT NULL DEFAULT '',
+    PRIMARY KEY (osrs_username, osrs_account_type, leaderboard)
+);
+
+INSERT INTO hiscores_snapshots_by_leaderboard (osrs_username, osrs_account_type, leaderboard, fetched_at, hiscores)
+SELECT osrs_username, osrs_account_type, CASE WHEN osrs_account_type = 'seasonal' THEN 'seasonal' ELSE '' END, fetched_at, hiscores
+FROM hiscores_snapshots;
+
+DROP TABLE hiscores_snapshots;
+
+ALTER TABLE hiscores_snapshots_by_leaderboard RENAME TO hiscores_snapshots;
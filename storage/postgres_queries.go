@@ -0,0 +1,1055 @@
+// Code generated by go run ./internal/pgqueries; DO NOT EDIT.
+
+package storage
+
+import (
+	"log"
+	"time"
+
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+	pgtable "github.com/michohl/osrs-clan-leaderboard/jet_schemas/postgres/table"
+	"github.com/michohl/osrs-clan-leaderboard/types"
+)
+
+// EnrollBoard takes form data from our enrollment survey and commits
+// that data to our database. The server is enrolled along with the
+// board so the first board configured in a server enrolls it
+func (store *PostgresStore) EnrollBoard(server model.Servers, board model.Boards, activities string) error {
+	log.Printf("Request received to enroll board %s for server: %s (ID: %s)\n", board.BoardName, server.ServerName, server.ID)
+
+	// Only the columns managed by /configure are written here so settings
+	// managed elsewhere (e.g. /settings) keep their values or defaults
+	serverStmt := pgtable.Servers.
+		INSERT(
+			pgtable.Servers.ID,
+			pgtable.Servers.ServerName,
+			pgtable.Servers.IsEnabled,
+			pgtable.Servers.DisabledReason,
+			pgtable.Servers.Timezone,
+		).
+		MODEL(server).
+		ON_CONFLICT(pgtable.Servers.ID).
+		DO_UPDATE(
+			postgres.SET(
+				pgtable.Servers.ServerName.SET(postgres.String(server.ServerName)),
+				pgtable.Servers.IsEnabled.SET(postgres.Bool(server.IsEnabled)),
+				pgtable.Servers.DisabledReason.SET(postgres.String(server.DisabledReason)),
+				pgtable.Servers.Timezone.SET(postgres.String(server.Timezone)),
+			),
+		)
+
+	boardStmt := pgtable.Boards.
+		INSERT(pgtable.Boards.AllColumns).
+		MODEL(board).
+		ON_CONFLICT(pgtable.Boards.ServerID, pgtable.Boards.BoardName).
+		DO_UPDATE(
+			postgres.SET(
+				pgtable.Boards.ChannelID.SET(postgres.String(board.ChannelID)),
+				pgtable.Boards.ChannelName.SET(postgres.String(board.ChannelName)),
+				pgtable.Boards.Schedule.SET(postgres.String(board.Schedule)),
+				pgtable.Boards.ShouldEditMessage.SET(postgres.Bool(board.ShouldEditMessage)),
+				pgtable.Boards.HideUnranked.SET(postgres.Bool(board.HideUnranked)),
+				pgtable.Boards.ShowRank.SET(postgres.Bool(board.ShowRank)),
+			),
+		)
+
+	// The server, the board and its activity list are written in one
+	// transaction so a failure part way through changes nothing
+	return store.inTransaction(func(txStore *PostgresStore) error {
+		_, err := serverStmt.Exec(txStore.conn)
+		if err != nil {
+			return err
+		}
+
+		_, err = boardStmt.Exec(txStore.conn)
+		if err != nil {
+			return err
+		}
+
+		return syncActivityMessages(txStore, board, activities)
+	})
+}
+
+// FetchBoards returns every board configured in a server ordered by name
+func (store *PostgresStore) FetchBoards(serverID string) ([]model.Boards, error) {
+	sqlStmt := pgtable.Boards.
+		SELECT(pgtable.Boards.AllColumns).
+		WHERE(pgtable.Boards.ServerID.EQ(postgres.String(serverID))).
+		ORDER_BY(pgtable.Boards.BoardName)
+
+	var b []model.Boards
+	err := sqlStmt.Query(store.conn, &b)
+	if err != nil {
+		return []model.Boards{}, err
+	}
+
+	return b, nil
+}
+
+// FetchBoard returns a single board from a server
+func (store *PostgresStore) FetchBoard(serverID string, boardName string) (model.Boards, error) {
+	sqlStmt := pgtable.Boards.
+		SELECT(pgtable.Boards.AllColumns).
+		WHERE(pgtable.Boards.ServerID.
+			EQ(postgres.String(serverID)).
+			AND(pgtable.Boards.BoardName.EQ(postgres.String(boardName))),
+		)
+
+	var b model.Boards
+	err := sqlStmt.Query(store.conn, &b)
+	if err != nil {
+		return model.Boards{}, err
+	}
+
+	return b, nil
+}
+
+// RemoveBoard removes a board along with every message we posted for it
+// and its run history
+func (store *PostgresStore) RemoveBoard(serverID string, boardName string) error {
+	log.Printf("Request received to remove board %s from server %s\n", boardName, serverID)
+
+	return store.inTransaction(func(txStore *PostgresStore) error {
+		_, err := pgtable.Messages.
+			DELETE().
+			WHERE(pgtable.Messages.ServerID.
+				EQ(postgres.String(serverID)).
+				AND(pgtable.Messages.BoardName.EQ(postgres.String(boardName))),
+			).
+			Exec(txStore.conn)
+		if err != nil {
+			return err
+		}
+
+		_, err = pgtable.ScheduleRuns.
+			DELETE().
+			WHERE(pgtable.ScheduleRuns.ServerID.
+				EQ(postgres.String(serverID)).
+				AND(pgtable.ScheduleRuns.BoardName.EQ(postgres.String(boardName))),
+			).
+			Exec(txStore.conn)
+		if err != nil {
+			return err
+		}
+
+		_, err = pgtable.RunFetchFailures.
+			DELETE().
+			WHERE(pgtable.RunFetchFailures.ServerID.
+				EQ(postgres.String(serverID)).
+				AND(pgtable.RunFetchFailures.BoardName.EQ(postgres.String(boardName))),
+			).
+			Exec(txStore.conn)
+		if err != nil {
+			return err
+		}
+
+		// A board created with the same name later on shouldn't be locked out
+		_, err = pgtable.RunLocks.
+			DELETE().
+			WHERE(pgtable.RunLocks.ServerID.
+				EQ(postgres.String(serverID)).
+				AND(pgtable.RunLocks.BoardName.EQ(postgres.String(boardName))),
+			).
+			Exec(txStore.conn)
+		if err != nil {
+			return err
+		}
+
+		_, err = pgtable.Boards.
+			DELETE().
+			WHERE(pgtable.Boards.ServerID.
+				EQ(postgres.String(serverID)).
+				AND(pgtable.Boards.BoardName.EQ(postgres.String(boardName))),
+			).
+			Exec(txStore.conn)
+
+		return err
+	})
+}
+
+// SetBoardChannel stores the channel a board posts to. It is used to fill in
+// the channel ID of boards configured before we stored IDs and to keep the
+// channel name up to date when the channel is renamed
+func (store *PostgresStore) SetBoardChannel(serverID string, boardName string, channelID string, channelName string) error {
+	log.Printf("Request received to set channel of board %s in server %s to %s (ID: %s)\n", boardName, serverID, channelName, channelID)
+
+	sqlStmt := pgtable.Boards.
+		UPDATE(pgtable.Boards.ChannelID, pgtable.Boards.ChannelName).
+		SET(postgres.String(channelID), postgres.String(channelName)).
+		WHERE(pgtable.Boards.ServerID.
+			EQ(postgres.String(serverID)).
+			AND(pgtable.Boards.BoardName.EQ(postgres.String(boardName))),
+		)
+
+	_, err := sqlStmt.Exec(store.conn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// EnrollUser takes form data from our enrollment survey and
+// commits that data to our database
+func (store *PostgresStore) EnrollUser(user model.Users) error {
+	log.Printf("Request received to attach discord user %s to OSRS user %s\n", user.DiscordUsername, user.OsrsUsername)
+
+	sqlStmt := pgtable.Users.
+		INSERT(pgtable.Users.AllColumns).
+		MODEL(user).
+		ON_CONFLICT(pgtable.Users.OsrsUsernameKey, pgtable.Users.ServerID).
+		DO_UPDATE(
+			postgres.SET(
+				pgtable.Users.OsrsUsername.SET(postgres.String(user.OsrsUsername)),
+				pgtable.Users.OsrsAccountType.SET(postgres.String(user.OsrsAccountType)),
+				pgtable.Users.DiscordUsername.SET(postgres.String(user.DiscordUsername)),
+				pgtable.Users.DiscordUserID.SET(postgres.String(user.DiscordUserID)),
+				pgtable.Users.IsArchived.SET(postgres.Bool(user.IsArchived)),
+			),
+		)
+
+	_, err := sqlStmt.Exec(store.conn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RemoveUser removes a user from a specific server
+func (store *PostgresStore) RemoveUser(user model.Users) error {
+	log.Printf("Request received to remove OSRS user %s from server %s\n", user.OsrsUsername, user.ServerID)
+
+	sqlStmt := pgtable.Users.
+		DELETE().
+		WHERE(pgtable.Users.ServerID.
+			EQ(postgres.String(user.ServerID)).
+			AND(pgtable.Users.OsrsUsernameKey.EQ(postgres.String(user.OsrsUsernameKey))),
+		)
+
+	_, err := sqlStmt.Exec(store.conn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RemoveUsersByDiscordID removes every OSRS user linked to a
+// specific Discord member from a server
+func (store *PostgresStore) RemoveUsersByDiscordID(serverID string, discordUserID string) error {
+	log.Printf("Request received to remove all OSRS users linked to discord user %s from server %s\n", discordUserID, serverID)
+
+	sqlStmt := pgtable.Users.
+		DELETE().
+		WHERE(pgtable.Users.ServerID.
+			EQ(postgres.String(serverID)).
+			AND(pgtable.Users.DiscordUserID.EQ(postgres.String(discordUserID))),
+		)
+
+	_, err := sqlStmt.Exec(store.conn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetUsersArchived archives (or restores) every OSRS user linked to
+// a specific Discord member. Archived users are kept in the database
+// but are hidden from leaderboards
+func (store *PostgresStore) SetUsersArchived(serverID string, discordUserID string, archived bool) error {
+	log.Printf("Request received to set archived=%t for OSRS users linked to discord user %s in server %s\n", archived, discordUserID, serverID)
+
+	sqlStmt := pgtable.Users.
+		UPDATE(pgtable.Users.IsArchived).
+		SET(postgres.Bool(archived)).
+		WHERE(pgtable.Users.ServerID.
+			EQ(postgres.String(serverID)).
+			AND(pgtable.Users.DiscordUserID.EQ(postgres.String(discordUserID))),
+		)
+
+	_, err := sqlStmt.Exec(store.conn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RenameUser changes the RSN of an already enrolled user while keeping
+// everything else about them (account type, Discord link) intact
+func (store *PostgresStore) RenameUser(user model.Users, newOsrsUsernameKey string, newOsrsUsername string) error {
+	log.Printf("Request received to rename OSRS user %s to %s in server %s\n", user.OsrsUsername, newOsrsUsername, user.ServerID)
+
+	return store.inTransaction(func(txStore *PostgresStore) error {
+		_, err := pgtable.Users.
+			UPDATE(pgtable.Users.OsrsUsernameKey, pgtable.Users.OsrsUsername, pgtable.Users.PossiblyRenamed).
+			SET(postgres.String(newOsrsUsernameKey), postgres.String(newOsrsUsername), postgres.Bool(false)).
+			WHERE(pgtable.Users.ServerID.
+				EQ(postgres.String(user.ServerID)).
+				AND(pgtable.Users.OsrsUsernameKey.EQ(postgres.String(user.OsrsUsernameKey))),
+			).
+			Exec(txStore.conn)
+		if err != nil {
+			return err
+		}
+
+		if newOsrsUsernameKey == user.OsrsUsernameKey {
+			return nil
+		}
+
+		// The player keeps their last hiscores under the new name. Other servers
+		// may still track the old name so its snapshots are left to expire
+		_, err = pgtable.HiscoresSnapshots.
+			INSERT(pgtable.HiscoresSnapshots.AllColumns).
+			QUERY(
+				postgres.SELECT(
+					postgres.String(newOsrsUsernameKey),
+					pgtable.HiscoresSnapshots.OsrsAccountType,
+					pgtable.HiscoresSnapshots.Leaderboard,
+					pgtable.HiscoresSnapshots.FetchedAt,
+					pgtable.HiscoresSnapshots.Hiscores,
+				).
+					FROM(pgtable.HiscoresSnapshots).
+					WHERE(pgtable.HiscoresSnapshots.OsrsUsername.EQ(postgres.String(user.OsrsUsernameKey))),
+			).
+			ON_CONFLICT(pgtable.HiscoresSnapshots.OsrsUsername, pgtable.HiscoresSnapshots.OsrsAccountType, pgtable.HiscoresSnapshots.Leaderboard).
+			DO_NOTHING().
+			Exec(txStore.conn)
+
+		return err
+	})
+}
+
+// SetUserPossiblyRenamed flags (or unflags) a user whose RSN
+// can no longer be found on the hiscores
+func (store *PostgresStore) SetUserPossiblyRenamed(user model.Users, possiblyRenamed bool) error {
+	log.Printf("Request received to set possibly_renamed=%t for OSRS user %s in server %s\n", possiblyRenamed, user.OsrsUsername, user.ServerID)
+
+	sqlStmt := pgtable.Users.
+		UPDATE(pgtable.Users.PossiblyRenamed).
+		SET(postgres.Bool(possiblyRenamed)).
+		WHERE(pgtable.Users.ServerID.
+			EQ(postgres.String(user.ServerID)).
+			AND(pgtable.Users.OsrsUsernameKey.EQ(postgres.String(user.OsrsUsernameKey))),
+		)
+
+	_, err := sqlStmt.Exec(store.conn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetPrimaryUser marks an OSRS user as the primary account of the
+// Discord member it is linked to. Any other account linked to the
+// same member stops being primary
+func (store *PostgresStore) SetPrimaryUser(user model.Users) error {
+	log.Printf("Request received to make OSRS user %s the primary account of discord user %s\n", user.OsrsUsername, user.DiscordUserID)
+
+	sqlStmt := pgtable.Users.
+		UPDATE(pgtable.Users.IsPrimary).
+		SET(pgtable.Users.OsrsUsernameKey.EQ(postgres.String(user.OsrsUsernameKey))).
+		WHERE(pgtable.Users.ServerID.
+			EQ(postgres.String(user.ServerID)).
+			AND(pgtable.Users.DiscordUserID.EQ(postgres.String(user.DiscordUserID))),
+		)
+
+	_, err := sqlStmt.Exec(store.conn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FetchPrimaryUser returns the primary OSRS user linked to a Discord member.
+// If the member never picked a primary account we fall back to any of them
+func (store *PostgresStore) FetchPrimaryUser(serverID string, discordUserID string) (model.Users, error) {
+	sqlStmt := pgtable.Users.
+		SELECT(pgtable.Users.AllColumns).
+		WHERE(pgtable.Users.ServerID.
+			EQ(postgres.String(serverID)).
+			AND(pgtable.Users.DiscordUserID.EQ(postgres.String(discordUserID))).
+			AND(pgtable.Users.IsArchived.IS_FALSE()),
+		).
+		ORDER_BY(pgtable.Users.IsPrimary.DESC(), pgtable.Users.OsrsUsernameKey).
+		LIMIT(1)
+
+	u := model.Users{}
+	err := sqlStmt.Query(store.conn, &u)
+	if err != nil {
+		return model.Users{}, err
+	}
+
+	return u, nil
+}
+
+// SetUserClanRank records the in-game clan rank a user currently holds
+func (store *PostgresStore) SetUserClanRank(user model.Users, clanRank string) error {
+	log.Printf("Request received to set clan rank of OSRS user %s in server %s to %s\n", user.OsrsUsername, user.ServerID, clanRank)
+
+	sqlStmt := pgtable.Users.
+		UPDATE(pgtable.Users.ClanRank).
+		SET(postgres.String(clanRank)).
+		WHERE(pgtable.Users.ServerID.
+			EQ(postgres.String(user.ServerID)).
+			AND(pgtable.Users.OsrsUsernameKey.EQ(postgres.String(user.OsrsUsernameKey))),
+		)
+
+	_, err := sqlStmt.Exec(store.conn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateDiscordUsername refreshes the stored Discord username
+// for every OSRS user linked to a Discord member
+func (store *PostgresStore) UpdateDiscordUsername(serverID string, discordUserID string, discordUsername string) error {
+	sqlStmt := pgtable.Users.
+		UPDATE(pgtable.Users.DiscordUsername).
+		SET(postgres.String(discordUsername)).
+		WHERE(pgtable.Users.ServerID.
+			EQ(postgres.String(serverID)).
+			AND(pgtable.Users.DiscordUserID.EQ(postgres.String(discordUserID))),
+		)
+
+	_, err := sqlStmt.Exec(store.conn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FetchAllServers gets all enrolled servers from the database
+func (store *PostgresStore) FetchAllServers() ([]model.Servers, error) {
+	sqlStmt := pgtable.Servers.SELECT(pgtable.Servers.AllColumns)
+
+	var allServers []model.Servers
+	err := sqlStmt.Query(store.conn, &allServers)
+	if err != nil {
+		return allServers, err
+	}
+
+	return allServers, nil
+}
+
+// FetchServer takes a Guild ID and returns the relevant
+// row from our database with the users existing config
+func (store *PostgresStore) FetchServer(serverID string) (model.Servers, error) {
+
+	sqlStmt := pgtable.Servers.
+		SELECT(pgtable.Servers.AllColumns).
+		WHERE(pgtable.Servers.ID.EQ(postgres.String(serverID)))
+
+	var s model.Servers
+	err := sqlStmt.Query(store.conn, &s)
+	if err != nil {
+		return model.Servers{}, err
+	}
+
+	return s, nil
+}
+
+// SetServerEnabled flips the is_enabled flag for a server and records
+// why it was disabled so we can tell automatic and manual disables apart
+func (store *PostgresStore) SetServerEnabled(serverID string, enabled bool, disabledReason string) error {
+	log.Printf("Request received to set enabled=%t for server %s (reason: '%s')\n", enabled, serverID, disabledReason)
+
+	sqlStmt := pgtable.Servers.
+		UPDATE(pgtable.Servers.IsEnabled, pgtable.Servers.DisabledReason).
+		SET(postgres.Bool(enabled), postgres.String(disabledReason)).
+		WHERE(pgtable.Servers.ID.EQ(postgres.String(serverID)))
+
+	_, err := sqlStmt.Exec(store.conn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateServerSettings stores the optional settings managed by /settings
+// without touching anything that is managed by /configure
+func (store *PostgresStore) UpdateServerSettings(server model.Servers) error {
+	log.Printf("Request received to update settings for server: %s (ID: %s)\n", server.ServerName, server.ID)
+
+	sqlStmt := pgtable.Servers.
+		UPDATE(
+			pgtable.Servers.MemberLeaveAction,
+			pgtable.Servers.RoleSyncEnabled,
+			pgtable.Servers.NicknameSyncEnabled,
+			pgtable.Servers.NicknameAccountTypePrefix,
+			pgtable.Servers.CatchUpEnabled,
+			pgtable.Servers.CatchUpMaxLatenessHours,
+			pgtable.Servers.OrphanedMessageAction,
+			pgtable.Servers.AdminChannelID,
+			pgtable.Servers.StaleDataMaxAgeHours,
+		).
+		SET(
+			postgres.String(server.MemberLeaveAction),
+			postgres.Bool(server.RoleSyncEnabled),
+			postgres.Bool(server.NicknameSyncEnabled),
+			postgres.Bool(server.NicknameAccountTypePrefix),
+			postgres.Bool(server.CatchUpEnabled),
+			postgres.Int32(server.CatchUpMaxLatenessHours),
+			postgres.String(server.OrphanedMessageAction),
+			postgres.String(server.AdminChannelID),
+			postgres.Int32(server.StaleDataMaxAgeHours),
+		).
+		WHERE(pgtable.Servers.ID.EQ(postgres.String(server.ID)))
+
+	_, err := sqlStmt.Exec(store.conn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateRankReportSchedule stores how often a server wants its rank
+// promotion report posted. An empty schedule turns the report off
+func (store *PostgresStore) UpdateRankReportSchedule(serverID string, schedule string) error {
+	log.Printf("Request received to set rank report schedule for server %s to '%s'\n", serverID, schedule)
+
+	sqlStmt := pgtable.Servers.
+		UPDATE(pgtable.Servers.RankReportSchedule).
+		SET(postgres.String(schedule)).
+		WHERE(pgtable.Servers.ID.EQ(postgres.String(serverID)))
+
+	_, err := sqlStmt.Exec(store.conn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FetchAllUsers gets all enrolled users for a server from the database.
+// Archived users are left out since they shouldn't appear on leaderboards
+func (store *PostgresStore) FetchAllUsers(serverID string) ([]model.Users, error) {
+	sqlStmt := pgtable.Users.
+		SELECT(pgtable.Users.AllColumns).
+		WHERE(pgtable.Users.ServerID.
+			EQ(postgres.String(serverID)).
+			AND(pgtable.Users.IsArchived.IS_FALSE()),
+		)
+
+	var allUsers []model.Users
+	sqlStmt.Query(store.conn, &allUsers)
+
+	return allUsers, nil
+}
+
+// FetchUser takes a Guild ID and returns the relevant
+// row from our database with the users existing config
+func (store *PostgresStore) FetchUser(serverID string, osrsUsername string) (model.Users, error) {
+
+	sqlStmt := pgtable.Users.
+		SELECT(pgtable.Users.AllColumns).
+		WHERE(pgtable.Users.ServerID.
+			EQ(postgres.String(serverID)).
+			AND(pgtable.Users.OsrsUsernameKey.EQ(postgres.String(osrsUsername))),
+		)
+
+	u := model.Users{}
+	err := sqlStmt.Query(store.conn, &u)
+	if err != nil {
+		return model.Users{}, err
+	}
+
+	return u, nil
+}
+
+// FetchActivityPosition returns the position of an activity on a
+// board or -1 if the activity isn't on the board
+func (store *PostgresStore) FetchActivityPosition(serverID string, boardName string, activity string) (int32, error) {
+	sqlStmt := pgtable.Messages.
+		SELECT(pgtable.Messages.Position).
+		DISTINCT().
+		WHERE(pgtable.Messages.ServerID.
+			EQ(postgres.String(serverID)).
+			AND(pgtable.Messages.BoardName.EQ(postgres.String(boardName))).
+			AND(pgtable.Messages.Activity.EQ(postgres.String(activity))),
+		)
+
+	var m []int32
+	err := sqlStmt.Query(store.conn, &m)
+	if err != nil {
+		return -1, err
+	}
+
+	if len(m) > 0 {
+		return m[0], nil
+	} else {
+		return -1, nil
+	}
+}
+
+// FetchAllMessages takes a Guild ID and board name and returns
+// the relevant rows from our database for that board
+func (store *PostgresStore) FetchAllMessages(serverID string, boardName string) ([]model.Messages, error) {
+
+	sqlStmt := pgtable.Messages.
+		SELECT(pgtable.Messages.AllColumns).
+		WHERE(pgtable.Messages.ServerID.
+			EQ(postgres.String(serverID)).
+			AND(pgtable.Messages.BoardName.EQ(postgres.String(boardName))),
+		).
+		ORDER_BY(pgtable.Messages.Position)
+
+	var m []model.Messages
+	err := sqlStmt.Query(store.conn, &m)
+	if err != nil {
+		return []model.Messages{}, err
+	}
+
+	return m, nil
+}
+
+// FetchMessage takes a Guild ID and board name and returns the
+// relevant rows from our database that match the specified activity
+func (store *PostgresStore) FetchMessage(serverID string, boardName string, activity string) ([]model.Messages, error) {
+
+	sqlStmt := pgtable.Messages.
+		SELECT(pgtable.Messages.AllColumns).
+		WHERE(pgtable.Messages.ServerID.
+			EQ(postgres.String(serverID)).
+			AND(pgtable.Messages.BoardName.EQ(postgres.String(boardName))).
+			AND(pgtable.Messages.Activity.EQ(postgres.String(activity))),
+		)
+
+	var m []model.Messages
+	err := sqlStmt.Query(store.conn, &m)
+	if err != nil {
+		return []model.Messages{}, err
+	}
+
+	return m, nil
+}
+
+// EnrollMessage takes a MessageID for a message we posted
+// and stores it so we can update that message later
+func (store *PostgresStore) EnrollMessage(message model.Messages) error {
+	log.Printf("Request received to enroll message ID %s for server %s, board %s and activity %s\n", message.MessageID, message.ServerID, message.BoardName, message.Activity)
+
+	sqlStmt := pgtable.Messages.
+		INSERT(pgtable.Messages.AllColumns).
+		MODEL(message).
+		ON_CONFLICT(pgtable.Messages.MessageID, pgtable.Messages.ServerID, pgtable.Messages.BoardName, pgtable.Messages.Activity).
+		DO_UPDATE(
+			postgres.SET(
+				pgtable.Messages.Position.SET(postgres.Int32(message.Position)),
+			),
+		)
+
+	_, err := sqlStmt.Exec(store.conn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RemoveMessage takes a message and removes the matching row from the database
+func (store *PostgresStore) RemoveMessage(message model.Messages) error {
+	log.Printf("Request received to remove message ID %s for server %s, board %s and activity %s\n", message.MessageID, message.ServerID, message.BoardName, message.Activity)
+
+	sqlStmt := pgtable.Messages.
+		DELETE().
+		WHERE(pgtable.Messages.Activity.
+			EQ(postgres.String(message.Activity)).
+			AND(pgtable.Messages.ServerID.EQ(postgres.String(message.ServerID))).
+			AND(pgtable.Messages.BoardName.EQ(postgres.String(message.BoardName))).
+			AND(pgtable.Messages.MessageID.EQ(postgres.String(message.MessageID))),
+		)
+
+	_, err := sqlStmt.Exec(store.conn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ResetMessages forgets every Discord message we have posted for a server
+// while keeping one placeholder row per activity on each board so the
+// configured list of activities (and their order) survives
+func (store *PostgresStore) ResetMessages(serverID string) error {
+	log.Printf("Request received to reset all messages for server %s\n", serverID)
+
+	boards, err := store.FetchBoards(serverID)
+	if err != nil {
+		return err
+	}
+
+	boardActivities := map[string][]string{}
+	for _, board := range boards {
+		boardActivities[board.BoardName], err = store.FetchAllActivitiesAndSkills(serverID, board.BoardName)
+		if err != nil {
+			return err
+		}
+	}
+
+	return store.inTransaction(func(txStore *PostgresStore) error {
+		_, err := pgtable.Messages.
+			DELETE().
+			WHERE(pgtable.Messages.ServerID.EQ(postgres.String(serverID))).
+			Exec(txStore.conn)
+		if err != nil {
+			return err
+		}
+
+		for boardName, activities := range boardActivities {
+			for position, activity := range activities {
+				err = txStore.EnrollMessage(model.Messages{
+					MessageID: "",
+					ServerID:  serverID,
+					BoardName: boardName,
+					Activity:  activity,
+					Position:  int32(position),
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// FetchRoleRules returns every role rule configured for a server
+func (store *PostgresStore) FetchRoleRules(serverID string) ([]model.RoleRules, error) {
+	sqlStmt := pgtable.RoleRules.
+		SELECT(pgtable.RoleRules.AllColumns).
+		WHERE(pgtable.RoleRules.ServerID.EQ(postgres.String(serverID))).
+		ORDER_BY(pgtable.RoleRules.RoleID, pgtable.RoleRules.Activity)
+
+	var r []model.RoleRules
+	err := sqlStmt.Query(store.conn, &r)
+	if err != nil {
+		return []model.RoleRules{}, err
+	}
+
+	return r, nil
+}
+
+// EnrollRoleRule creates or updates the threshold a member needs
+// to reach in an activity to be granted a role
+func (store *PostgresStore) EnrollRoleRule(rule model.RoleRules) error {
+	log.Printf("Request received to enroll role rule %s >= %d for role %s in server %s\n", rule.Activity, rule.Threshold, rule.RoleID, rule.ServerID)
+
+	sqlStmt := pgtable.RoleRules.
+		INSERT(pgtable.RoleRules.AllColumns).
+		MODEL(rule).
+		ON_CONFLICT(pgtable.RoleRules.ServerID, pgtable.RoleRules.RoleID, pgtable.RoleRules.Activity).
+		DO_UPDATE(
+			postgres.SET(
+				pgtable.RoleRules.Threshold.SET(postgres.Int32(rule.Threshold)),
+			),
+		)
+
+	_, err := sqlStmt.Exec(store.conn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RemoveRoleRules removes the rule for a role and activity. If no
+// activity is given every rule for the role is removed
+func (store *PostgresStore) RemoveRoleRules(serverID string, roleID string, activity string) error {
+	log.Printf("Request received to remove role rules for role %s and activity '%s' in server %s\n", roleID, activity, serverID)
+
+	condition := pgtable.RoleRules.ServerID.
+		EQ(postgres.String(serverID)).
+		AND(pgtable.RoleRules.RoleID.EQ(postgres.String(roleID)))
+
+	if activity != "" {
+		condition = condition.AND(pgtable.RoleRules.Activity.EQ(postgres.String(activity)))
+	}
+
+	_, err := pgtable.RoleRules.DELETE().WHERE(condition).Exec(store.conn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FetchRanks returns a server's rank ladder from the highest rank to the lowest
+func (store *PostgresStore) FetchRanks(serverID string) ([]model.Ranks, error) {
+	sqlStmt := pgtable.Ranks.
+		SELECT(pgtable.Ranks.AllColumns).
+		WHERE(pgtable.Ranks.ServerID.EQ(postgres.String(serverID))).
+		ORDER_BY(pgtable.Ranks.Position.DESC())
+
+	var r []model.Ranks
+	err := sqlStmt.Query(store.conn, &r)
+	if err != nil {
+		return []model.Ranks{}, err
+	}
+
+	return r, nil
+}
+
+// EnrollRank creates or updates a rank on a server's rank ladder
+func (store *PostgresStore) EnrollRank(rank model.Ranks) error {
+	log.Printf("Request received to enroll rank %s (position %d) for server %s\n", rank.RankName, rank.Position, rank.ServerID)
+
+	sqlStmt := pgtable.Ranks.
+		INSERT(pgtable.Ranks.AllColumns).
+		MODEL(rank).
+		ON_CONFLICT(pgtable.Ranks.ServerID, pgtable.Ranks.RankName).
+		DO_UPDATE(
+			postgres.SET(
+				pgtable.Ranks.Position.SET(postgres.Int32(rank.Position)),
+				pgtable.Ranks.Requirements.SET(postgres.String(rank.Requirements)),
+			),
+		)
+
+	_, err := sqlStmt.Exec(store.conn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RemoveRank removes a rank from a server's rank ladder
+func (store *PostgresStore) RemoveRank(serverID string, rankName string) error {
+	log.Printf("Request received to remove rank %s from server %s\n", rankName, serverID)
+
+	sqlStmt := pgtable.Ranks.
+		DELETE().
+		WHERE(pgtable.Ranks.ServerID.
+			EQ(postgres.String(serverID)).
+			AND(pgtable.Ranks.RankName.EQ(postgres.String(rankName))),
+		)
+
+	_, err := sqlStmt.Exec(store.conn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RecordScheduleRun stores how a board's run went along with the players it
+// couldn't fetch and forgets the board's runs that are older than scheduleRunRetention
+func (store *PostgresStore) RecordScheduleRun(run model.ScheduleRuns, failures []model.RunFetchFailures) error {
+	cutoff := run.StartedAt.Add(-scheduleRunRetention)
+
+	return store.inTransaction(func(txStore *PostgresStore) error {
+		_, err := pgtable.ScheduleRuns.
+			INSERT(pgtable.ScheduleRuns.AllColumns).
+			MODEL(run).
+			Exec(txStore.conn)
+		if err != nil {
+			return err
+		}
+
+		if len(failures) > 0 {
+			_, err = pgtable.RunFetchFailures.
+				INSERT(pgtable.RunFetchFailures.AllColumns).
+				MODELS(failures).
+				Exec(txStore.conn)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = pgtable.ScheduleRuns.
+			DELETE().
+			WHERE(pgtable.ScheduleRuns.ServerID.
+				EQ(postgres.String(run.ServerID)).
+				AND(pgtable.ScheduleRuns.BoardName.EQ(postgres.String(run.BoardName))).
+				AND(pgtable.ScheduleRuns.StartedAt.LT(postgres.RawTimestamp("#cutoff", postgres.RawArgs{"#cutoff": cutoff}))),
+			).
+			Exec(txStore.conn)
+		if err != nil {
+			return err
+		}
+
+		_, err = pgtable.RunFetchFailures.
+			DELETE().
+			WHERE(pgtable.RunFetchFailures.ServerID.
+				EQ(postgres.String(run.ServerID)).
+				AND(pgtable.RunFetchFailures.BoardName.EQ(postgres.String(run.BoardName))).
+				AND(pgtable.RunFetchFailures.StartedAt.LT(postgres.RawTimestamp("#cutoff", postgres.RawArgs{"#cutoff": cutoff}))),
+			).
+			Exec(txStore.conn)
+
+		return err
+	})
+}
+
+// FetchRunFetchFailures returns the players a run of a board couldn't fetch
+func (store *PostgresStore) FetchRunFetchFailures(run model.ScheduleRuns) ([]model.RunFetchFailures, error) {
+	sqlStmt := pgtable.RunFetchFailures.
+		SELECT(pgtable.RunFetchFailures.AllColumns).
+		WHERE(pgtable.RunFetchFailures.ServerID.
+			EQ(postgres.String(run.ServerID)).
+			AND(pgtable.RunFetchFailures.BoardName.EQ(postgres.String(run.BoardName))).
+			AND(pgtable.RunFetchFailures.StartedAt.EQ(postgres.RawTimestamp("#startedAt", postgres.RawArgs{"#startedAt": run.StartedAt}))),
+		).
+		ORDER_BY(pgtable.RunFetchFailures.OsrsUsername.ASC())
+
+	failures := []model.RunFetchFailures{}
+	err := sqlStmt.Query(store.conn, &failures)
+	if err != nil {
+		return nil, err
+	}
+
+	return failures, nil
+}
+
+// FetchLastScheduleRun returns the most recent run of a board
+func (store *PostgresStore) FetchLastScheduleRun(serverID string, boardName string) (model.ScheduleRuns, error) {
+	sqlStmt := pgtable.ScheduleRuns.
+		SELECT(pgtable.ScheduleRuns.AllColumns).
+		WHERE(pgtable.ScheduleRuns.ServerID.
+			EQ(postgres.String(serverID)).
+			AND(pgtable.ScheduleRuns.BoardName.EQ(postgres.String(boardName))),
+		).
+		ORDER_BY(pgtable.ScheduleRuns.StartedAt.DESC()).
+		LIMIT(1)
+
+	var r model.ScheduleRuns
+	err := sqlStmt.Query(store.conn, &r)
+	if err != nil {
+		return model.ScheduleRuns{}, err
+	}
+
+	return r, nil
+}
+
+// FetchLastSuccessfulScheduleRun returns the most recent run of a
+// board that posted every message
+func (store *PostgresStore) FetchLastSuccessfulScheduleRun(serverID string, boardName string) (model.ScheduleRuns, error) {
+	sqlStmt := pgtable.ScheduleRuns.
+		SELECT(pgtable.ScheduleRuns.AllColumns).
+		WHERE(pgtable.ScheduleRuns.ServerID.
+			EQ(postgres.String(serverID)).
+			AND(pgtable.ScheduleRuns.BoardName.EQ(postgres.String(boardName))).
+			AND(pgtable.ScheduleRuns.Outcome.EQ(postgres.String(types.RunOutcomeSuccess))),
+		).
+		ORDER_BY(pgtable.ScheduleRuns.StartedAt.DESC()).
+		LIMIT(1)
+
+	var r model.ScheduleRuns
+	err := sqlStmt.Query(store.conn, &r)
+	if err != nil {
+		return model.ScheduleRuns{}, err
+	}
+
+	return r, nil
+}
+
+// SaveHiscoresSnapshots stores the latest hiscores we fetched for some players
+// and forgets snapshots that are older than hiscoresSnapshotRetention
+func (store *PostgresStore) SaveHiscoresSnapshots(snapshots []model.HiscoresSnapshots) error {
+	cutoff := time.Now().UTC().Add(-hiscoresSnapshotRetention)
+
+	return store.inTransaction(func(txStore *PostgresStore) error {
+		for _, snapshot := range snapshots {
+			_, err := pgtable.HiscoresSnapshots.
+				INSERT(pgtable.HiscoresSnapshots.AllColumns).
+				MODEL(snapshot).
+				ON_CONFLICT(pgtable.HiscoresSnapshots.OsrsUsername, pgtable.HiscoresSnapshots.OsrsAccountType, pgtable.HiscoresSnapshots.Leaderboard).
+				DO_UPDATE(
+					postgres.SET(
+						pgtable.HiscoresSnapshots.FetchedAt.SET(postgres.RawTimestamp("#fetchedAt", postgres.RawArgs{"#fetchedAt": snapshot.FetchedAt})),
+						pgtable.HiscoresSnapshots.Hiscores.SET(postgres.String(snapshot.Hiscores)),
+					),
+				).
+				Exec(txStore.conn)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err := pgtable.HiscoresSnapshots.
+			DELETE().
+			WHERE(pgtable.HiscoresSnapshots.FetchedAt.LT(postgres.RawTimestamp("#cutoff", postgres.RawArgs{"#cutoff": cutoff}))).
+			Exec(txStore.conn)
+
+		return err
+	})
+}
+
+// FetchHiscoresSnapshot returns the last hiscores we fetched for a player on a
+// leaderboard. An empty leaderboard is the hiscores of the player's account type
+func (store *PostgresStore) FetchHiscoresSnapshot(osrsUsername string, accountType string, leaderboard string) (model.HiscoresSnapshots, error) {
+	sqlStmt := pgtable.HiscoresSnapshots.
+		SELECT(pgtable.HiscoresSnapshots.AllColumns).
+		WHERE(pgtable.HiscoresSnapshots.OsrsUsername.
+			EQ(postgres.String(osrsUsername)).
+			AND(pgtable.HiscoresSnapshots.OsrsAccountType.EQ(postgres.String(accountType))).
+			AND(pgtable.HiscoresSnapshots.Leaderboard.EQ(postgres.String(leaderboard))),
+		)
+
+	var snapshot model.HiscoresSnapshots
+	err := sqlStmt.Query(store.conn, &snapshot)
+	if err != nil {
+		return model.HiscoresSnapshots{}, err
+	}
+
+	return snapshot, nil
+}
+
+// AcquireRunLock takes the lock that lets a single run post a board. It
+// returns false if someone else holds a lock that hasn't expired yet
+func (store *PostgresStore) AcquireRunLock(lock model.RunLocks) (bool, error) {
+	sqlStmt := pgtable.RunLocks.
+		INSERT(pgtable.RunLocks.AllColumns).
+		MODEL(lock).
+		ON_CONFLICT(pgtable.RunLocks.ServerID, pgtable.RunLocks.BoardName).
+		DO_UPDATE(
+			postgres.SET(
+				pgtable.RunLocks.Owner.SET(postgres.String(lock.Owner)),
+				pgtable.RunLocks.AcquiredAt.SET(postgres.RawTimestamp("#acquired", postgres.RawArgs{"#acquired": lock.AcquiredAt})),
+				pgtable.RunLocks.ExpiresAt.SET(postgres.RawTimestamp("#expires", postgres.RawArgs{"#expires": lock.ExpiresAt})),
+			).WHERE(
+				pgtable.RunLocks.ExpiresAt.LT(postgres.RawTimestamp("#now", postgres.RawArgs{"#now": lock.AcquiredAt})),
+			),
+		)
+
+	result, err := sqlStmt.Exec(store.conn)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows == 1, nil
+}
+
+// ReleaseRunLock gives up a board's lock if we're the ones holding it
+func (store *PostgresStore) ReleaseRunLock(serverID string, boardName string, owner string) error {
+	sqlStmt := pgtable.RunLocks.
+		DELETE().
+		WHERE(pgtable.RunLocks.ServerID.
+			EQ(postgres.String(serverID)).
+			AND(pgtable.RunLocks.BoardName.EQ(postgres.String(boardName))).
+			AND(pgtable.RunLocks.Owner.EQ(postgres.String(owner))),
+		)
+
+	_, err := sqlStmt.Exec(store.conn)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
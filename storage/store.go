@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+)
+
+// ErrNotFound is returned when a single row was requested but doesn't exist.
+// It is the same error jet returns so callers can treat every Store the same
+var ErrNotFound = qrm.ErrNoRows
+
+// scheduleRunRetention is how long we keep the run history of a board
+const scheduleRunRetention = 90 * 24 * time.Hour
+
+// hiscoresSnapshotRetention is how long we keep the last hiscores of a player
+// we haven't been able to fetch since. It matches the most stale data we show
+const hiscoresSnapshotRetention = 30 * 24 * time.Hour
+
+// Store is everything the bot needs to persist. SQLiteStore is what we
+// run with by default and PostgresStore is used when DATABASE_URL is set.
+// MemoryStore keeps everything in memory for tests
+type Store interface {
+	// Servers
+	FetchAllServers() ([]model.Servers, error)
+	FetchServer(serverID string) (model.Servers, error)
+	SetServerEnabled(serverID string, enabled bool, disabledReason string) error
+	UpdateServerSettings(server model.Servers) error
+	UpdateRankReportSchedule(serverID string, schedule string) error
+
+	// Boards
+	EnrollBoard(server model.Servers, board model.Boards, activities string) error
+	FetchBoards(serverID string) ([]model.Boards, error)
+	FetchBoard(serverID string, boardName string) (model.Boards, error)
+	RemoveBoard(serverID string, boardName string) error
+	SetBoardChannel(serverID string, boardName string, channelID string, channelName string) error
+
+	// Users
+	EnrollUser(user model.Users) error
+	RemoveUser(user model.Users) error
+	RemoveUsersByDiscordID(serverID string, discordUserID string) error
+	SetUsersArchived(serverID string, discordUserID string, archived bool) error
+	RenameUser(user model.Users, newOsrsUsernameKey string, newOsrsUsername string) error
+	SetUserPossiblyRenamed(user model.Users, possiblyRenamed bool) error
+	SetPrimaryUser(user model.Users) error
+	FetchPrimaryUser(serverID string, discordUserID string) (model.Users, error)
+	SetUserClanRank(user model.Users, clanRank string) error
+	UpdateDiscordUsername(serverID string, discordUserID string, discordUsername string) error
+	FetchAllUsers(serverID string) ([]model.Users, error)
+	FetchUser(serverID string, osrsUsername string) (model.Users, error)
+
+	// Messages
+	FetchAllActivitiesAndSkills(serverID string, boardName string) ([]string, error)
+	FetchActivityPosition(serverID string, boardName string, activity string) (int32, error)
+	FetchAllMessages(serverID string, boardName string) ([]model.Messages, error)
+	FetchMessage(serverID string, boardName string, activity string) ([]model.Messages, error)
+	EnrollMessage(message model.Messages) error
+	RemoveMessage(message model.Messages) error
+	ResetMessages(serverID string) error
+
+	// Role rules
+	FetchRoleRules(serverID string) ([]model.RoleRules, error)
+	EnrollRoleRule(rule model.RoleRules) error
+	RemoveRoleRules(serverID string, roleID string, activity string) error
+
+	// Ranks
+	FetchRanks(serverID string) ([]model.Ranks, error)
+	EnrollRank(rank model.Ranks) error
+	RemoveRank(serverID string, rankName string) error
+
+	// Schedule runs
+	RecordScheduleRun(run model.ScheduleRuns, failures []model.RunFetchFailures) error
+	FetchRunFetchFailures(run model.ScheduleRuns) ([]model.RunFetchFailures, error)
+	SaveHiscoresSnapshots(snapshots []model.HiscoresSnapshots) error
+	FetchHiscoresSnapshot(osrsUsername string, accountType string, leaderboard string) (model.HiscoresSnapshots, error)
+	FetchLastScheduleRun(serverID string, boardName string) (model.ScheduleRuns, error)
+	FetchLastSuccessfulScheduleRun(serverID string, boardName string) (model.ScheduleRuns, error)
+
+	// Run locks
+	AcquireRunLock(lock model.RunLocks) (bool, error)
+	ReleaseRunLock(serverID string, boardName string, owner string) error
+
+	// Schema
+	Migrate() error
+	MigrationStatus() ([]MigrationState, error)
+
+	Close() error
+}
+
+var (
+	_ Store = (*SQLiteStore)(nil)
+	_ Store = (*PostgresStore)(nil)
+	_ Store = (*MemoryStore)(nil)
+)
+
+// syncActivityMessages lines up the message rows of a board with a comma
+// separated list of activities. Activities that were dropped lose their
+// messages and new or moved activities get a placeholder at their position
+func syncActivityMessages(store Store, board model.Boards, activities string) error {
+	newActivities := strings.Split(activities, ",")
+
+	existingActivityMessages, err := store.FetchAllMessages(board.ServerID, board.BoardName)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range existingActivityMessages {
+		if !slices.Contains(newActivities, m.Activity) {
+			err = store.RemoveMessage(m)
+			if err != nil {
+				return fmt.Errorf("Unable to remove activity %s: %w", m.Activity, err)
+			}
+		}
+	}
+
+	for position, activity := range newActivities {
+
+		currentPosition, err := store.FetchActivityPosition(board.ServerID, board.BoardName, activity)
+		if err != nil {
+			return fmt.Errorf("Unable to look up activity %s: %w", activity, err)
+		}
+
+		// If we can't find a message or if the position changed create/update the message row
+		if currentPosition == -1 || int32(position) != currentPosition {
+			err = store.EnrollMessage(model.Messages{
+				MessageID: "",
+				ServerID:  board.ServerID,
+				BoardName: board.BoardName,
+				Activity:  activity,
+				Position:  int32(position),
+			})
+			if err != nil {
+				return fmt.Errorf("Unable to save activity %s: %w", activity, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,451 @@
+package storage
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+	pgtable "github.com/michohl/osrs-clan-leaderboard/jet_schemas/postgres/table"
+	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/table"
+)
+
+// newTestPostgresStore connects to the database at POSTGRES_TEST_DSN and
+// gives the test a schema of its own that is dropped once the test is over.
+// The test is skipped when POSTGRES_TEST_DSN isn't set
+func newTestPostgresStore(t *testing.T) *PostgresStore {
+	t.Helper()
+
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN is not set")
+	}
+
+	admin, err := NewPostgresStore(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	_, err = admin.db.Exec("CREATE SCHEMA " + schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.db.Exec("DROP SCHEMA " + schema + " CASCADE") })
+
+	// The migrations don't name a schema so they follow the search path
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		query := u.Query()
+		query.Set("search_path", schema)
+		u.RawQuery = query.Encode()
+		dsn = u.String()
+	} else {
+		dsn += " search_path=" + schema
+	}
+
+	st, err := NewPostgresStore(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { st.Close() })
+
+	pgtable.UseSchema(schema)
+	t.Cleanup(func() { pgtable.UseSchema("public") })
+
+	return st
+}
+
+// newTestSQLiteStore creates an empty SQLite database that is removed once the test is over
+func newTestSQLiteStore(t *testing.T) *SQLiteStore {
+	t.Helper()
+
+	st, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { st.Close() })
+
+	return st
+}
+
+// testRunStartedAt is when the run saved by fillTestStore started. Postgres
+// keeps microseconds and SQLite keeps less so we leave both out
+var testRunStartedAt = time.Now().UTC().Truncate(time.Second)
+
+// fillTestStore saves a row to every table we keep
+func fillTestStore(t *testing.T, st Store) {
+	t.Helper()
+
+	server := model.Servers{
+		ID:                   "1",
+		ServerName:           "Test Server",
+		IsEnabled:            true,
+		Timezone:             "Europe/London",
+		AdminChannelID:       "20",
+		StaleDataMaxAgeHours: 24,
+	}
+	board := model.Boards{
+		ServerID:     "1",
+		BoardName:    "default",
+		ChannelName:  "hiscores",
+		ChannelID:    "10",
+		Schedule:     "0 19 * * SUN",
+		HideUnranked: true,
+	}
+	run := model.ScheduleRuns{
+		ServerID:    "1",
+		BoardName:   "default",
+		StartedAt:   testRunStartedAt,
+		DurationMs:  1500,
+		TriggeredBy: "schedule",
+		Outcome:     "success",
+	}
+	failures := []model.RunFetchFailures{{
+		ServerID:        "1",
+		BoardName:       "default",
+		StartedAt:       testRunStartedAt,
+		OsrsUsername:    "Old Name",
+		OsrsAccountType: "main",
+		Reason:          "not found",
+	}}
+
+	err := st.EnrollBoard(server, board, "Overall,Zulrah")
+	if err == nil {
+		err = st.UpdateServerSettings(server)
+	}
+	if err == nil {
+		err = st.EnrollUser(model.Users{
+			OsrsUsernameKey: "zezima",
+			ServerID:        "1",
+			OsrsUsername:    "Zezima",
+			OsrsAccountType: "main",
+			DiscordUserID:   "30",
+		})
+	}
+	if err == nil {
+		err = st.EnrollRoleRule(model.RoleRules{ServerID: "1", RoleID: "40", Activity: "Zulrah", Threshold: 100})
+	}
+	if err == nil {
+		err = st.EnrollRank(model.Ranks{ServerID: "1", RankName: "Sapphire", Position: 1, Requirements: "Overall:1000"})
+	}
+	if err == nil {
+		err = st.EnrollMessage(model.Messages{MessageID: "50", ServerID: "1", BoardName: "default", Activity: "Overall", Position: 0})
+	}
+	if err == nil {
+		err = st.RecordScheduleRun(run, failures)
+	}
+	if err == nil {
+		err = st.SaveHiscoresSnapshots([]model.HiscoresSnapshots{{
+			OsrsUsername:    "Zezima",
+			OsrsAccountType: "main",
+			FetchedAt:       testRunStartedAt,
+			Hiscores:        `{"name":"Zezima"}`,
+		}})
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// checkTestStore checks every row saved by fillTestStore can be read back
+func checkTestStore(t *testing.T, st Store) {
+	t.Helper()
+
+	server, err := st.FetchServer("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server.Timezone != "Europe/London" || server.AdminChannelID != "20" || server.StaleDataMaxAgeHours != 24 {
+		t.Errorf("server settings weren't kept: %+v", server)
+	}
+
+	board, err := st.FetchBoard("1", "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if board.ChannelID != "10" || board.Schedule != "0 19 * * SUN" || !board.HideUnranked {
+		t.Errorf("board settings weren't kept: %+v", board)
+	}
+
+	activities, err := st.FetchAllActivitiesAndSkills("1", "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(activities, ",") != "Overall,Zulrah" {
+		t.Errorf("activities weren't kept in order: %v", activities)
+	}
+
+	messages, err := st.FetchMessage("1", "default", "Overall")
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, m := range messages {
+		found = found || m.MessageID == "50"
+	}
+	if !found {
+		t.Errorf("message wasn't kept: %+v", messages)
+	}
+
+	user, err := st.FetchUser("1", "zezima")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.DiscordUserID != "30" {
+		t.Errorf("user wasn't kept: %+v", user)
+	}
+
+	rules, err := st.FetchRoleRules("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 1 || rules[0].Threshold != 100 {
+		t.Errorf("role rules weren't kept: %+v", rules)
+	}
+
+	ranks, err := st.FetchRanks("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ranks) != 1 || ranks[0].Requirements != "Overall:1000" {
+		t.Errorf("ranks weren't kept: %+v", ranks)
+	}
+
+	run, err := st.FetchLastScheduleRun("1", "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !run.StartedAt.Equal(testRunStartedAt) || run.DurationMs != 1500 || run.Outcome != "success" {
+		t.Errorf("schedule run wasn't kept: %+v", run)
+	}
+
+	failures, err := st.FetchRunFetchFailures(run)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(failures) != 1 || failures[0].OsrsUsername != "Old Name" {
+		t.Errorf("fetch failures weren't kept: %+v", failures)
+	}
+
+	snapshot, err := st.FetchHiscoresSnapshot("Zezima", "main", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Hiscores != `{"name":"Zezima"}` {
+		t.Errorf("hiscores snapshot wasn't kept: %+v", snapshot)
+	}
+}
+
+func TestPostgresStoreMigrate(t *testing.T) {
+	st := newTestPostgresStore(t)
+
+	err := st.Migrate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Running them again has nothing left to do
+	err = st.Migrate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	states, err := st.MigrationStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, state := range states {
+		if state.AppliedAt == nil {
+			t.Errorf("migration %04d_%s wasn't applied", state.Version, state.Name)
+		}
+	}
+}
+
+func TestPostgresStoreRoundTrip(t *testing.T) {
+	st := newTestPostgresStore(t)
+
+	err := st.Migrate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fillTestStore(t, st)
+	checkTestStore(t, st)
+}
+
+func TestCopySQLiteToPostgres(t *testing.T) {
+	dst := newTestPostgresStore(t)
+	src := newTestSQLiteStore(t)
+
+	err := src.Migrate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fillTestStore(t, src)
+
+	err = CopySQLiteToPostgres(src, dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkTestStore(t, dst)
+}
+
+func TestCopySQLiteToPostgresInBatches(t *testing.T) {
+	dst := newTestPostgresStore(t)
+	src := newTestSQLiteStore(t)
+
+	err := src.Migrate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fillTestStore(t, src)
+
+	// More users than fit in the bind parameters of a single insert
+	count := postgresMaxParameters/len(table.Users.AllColumns) + 10
+	err = src.inTransaction(func(txStore *SQLiteStore) error {
+		for i := range count {
+			_, err := table.Users.
+				INSERT(table.Users.AllColumns).
+				MODEL(model.Users{
+					OsrsUsernameKey: fmt.Sprintf("player %d", i),
+					ServerID:        "1",
+					OsrsUsername:    fmt.Sprintf("Player %d", i),
+					OsrsAccountType: "main",
+				}).
+				Exec(txStore.conn)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = CopySQLiteToPostgres(src, dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	users, err := dst.FetchAllUsers("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// fillTestStore adds a user of its own
+	if len(users) != count+1 {
+		t.Errorf("copied %d users, want %d", len(users), count+1)
+	}
+}
+
+// testStores create the stores that have to behave the same way. Postgres
+// has tests of its own since it needs a database to run against
+var testStores = map[string]func(t *testing.T) Store{
+	"sqlite": func(t *testing.T) Store {
+		st := newTestSQLiteStore(t)
+		err := st.Migrate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return st
+	},
+	"memory": func(t *testing.T) Store {
+		return NewMemoryStore()
+	},
+}
+
+func TestStoreRoundTrip(t *testing.T) {
+	for name, newStore := range testStores {
+		t.Run(name, func(t *testing.T) {
+			st := newStore(t)
+
+			fillTestStore(t, st)
+			checkTestStore(t, st)
+		})
+	}
+}
+
+func TestRemoveBoardReleasesRunLock(t *testing.T) {
+	for name, newStore := range testStores {
+		t.Run(name, func(t *testing.T) {
+			testRemoveBoardReleasesRunLock(t, newStore(t))
+		})
+	}
+}
+
+func testRemoveBoardReleasesRunLock(t *testing.T, st Store) {
+	fillTestStore(t, st)
+
+	lock := model.RunLocks{
+		ServerID:   "1",
+		BoardName:  "default",
+		Owner:      "first",
+		AcquiredAt: time.Now().UTC(),
+		ExpiresAt:  time.Now().UTC().Add(time.Hour),
+	}
+	acquired, err := st.AcquireRunLock(lock)
+	if err != nil || !acquired {
+		t.Fatalf("unable to take the lock: %t %v", acquired, err)
+	}
+
+	err = st.RemoveBoard("1", "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A new board with the same name can be posted straight away
+	lock.Owner = "second"
+	acquired, err = st.AcquireRunLock(lock)
+	if err != nil || !acquired {
+		t.Errorf("the removed board's lock was kept: %t %v", acquired, err)
+	}
+}
+
+func TestRenameUserKeepsHiscoresSnapshots(t *testing.T) {
+	for name, newStore := range testStores {
+		t.Run(name, func(t *testing.T) {
+			testRenameUserKeepsHiscoresSnapshots(t, newStore(t))
+		})
+	}
+}
+
+func testRenameUserKeepsHiscoresSnapshots(t *testing.T, st Store) {
+	fillTestStore(t, st)
+
+	err := st.SaveHiscoresSnapshots([]model.HiscoresSnapshots{{
+		OsrsUsername:    "zezima",
+		OsrsAccountType: "main",
+		FetchedAt:       testRunStartedAt,
+		Hiscores:        `{"name":"Zezima"}`,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := st.FetchUser("1", "zezima")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = st.RenameUser(user, "new_name", "New Name")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot, err := st.FetchHiscoresSnapshot("new_name", "main", "")
+	if err != nil {
+		t.Fatalf("the renamed player has no snapshot: %s", err)
+	}
+	if snapshot.Hiscores != `{"name":"Zezima"}` || !snapshot.FetchedAt.Equal(testRunStartedAt) {
+		t.Errorf("snapshot wasn't carried over: %+v", snapshot)
+	}
+}
@@ -0,0 +1,12 @@
+package types
+
+// AccountTypeAbbreviations are the short names the community uses for
+// each account type. Main accounts intentionally don't have one
+var AccountTypeAbbreviations = map[string]string{
+	"ironman":                "IM",
+	"hardcore_ironman":       "HCIM",
+	"ultimate_ironman":       "UIM",
+	"group_ironman":          "GIM",
+	"hardcore_group_ironman": "HCGIM",
+	"unranked_group_ironman": "UGIM",
+}
@@ -42,6 +42,21 @@ func (h *Hiscores) GetActivity(name string) *ActivityHiscore {
 	return nil
 }
 
+// GetLevelOrScore returns the level for a skill or the score for an
+// activity so callers don't need to know which kind a name refers to.
+// Unranked values are normalized to level 1 and a score of 0
+func (h *Hiscores) GetLevelOrScore(name string) (int, bool) {
+	if skill := h.GetSkill(name); skill != nil {
+		return max(skill.Level, 1), true
+	}
+
+	if activity := h.GetActivity(name); activity != nil {
+		return max(activity.Score, 0), true
+	}
+
+	return 0, false
+}
+
 // SkillHiscore is a representation for a "skilling" activity
 type SkillHiscore struct {
 	ID    int    `json:"id"`
@@ -0,0 +1,8 @@
+package types
+
+// RankRequirement is a single requirement on a rank ladder
+// e.g. "Overall >= 1500" or "TzKal-Zuk >= 1"
+type RankRequirement struct {
+	Activity  string
+	Threshold int
+}
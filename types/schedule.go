@@ -12,3 +12,28 @@ type CronSchedule struct {
 	JobID          cron.EntryID
 	DiscordSession *discordgo.Session
 }
+
+const (
+	// RunTriggerSchedule marks a board run started by the board's schedule
+	RunTriggerSchedule = "schedule"
+
+	// RunTriggerManual marks a board run started by an admin with /post
+	RunTriggerManual = "manual"
+
+	// RunTriggerCatchUp marks a board run that makes up for a scheduled
+	// post that was missed while the bot was offline
+	RunTriggerCatchUp = "catch_up"
+
+	// RunTriggerRetry marks a board run that tries a postponed post again
+	RunTriggerRetry = "retry"
+
+	// RunOutcomeSuccess marks a board run that posted every message
+	RunOutcomeSuccess = "success"
+
+	// RunOutcomeFailure marks a board run that stopped because of an error
+	RunOutcomeFailure = "failure"
+
+	// RunOutcomePostponed marks a board run that was put off
+	// because the hiscores were unavailable
+	RunOutcomePostponed = "postponed"
+)
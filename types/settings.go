@@ -0,0 +1,40 @@
+package types
+
+const (
+	// MemberLeaveActionKeep leaves a departed member's OSRS users on the leaderboards
+	MemberLeaveActionKeep = "keep"
+
+	// MemberLeaveActionUnassign removes a departed member's OSRS users entirely
+	MemberLeaveActionUnassign = "unassign"
+
+	// MemberLeaveActionArchive hides a departed member's OSRS users from the
+	// leaderboards until they rejoin the server
+	MemberLeaveActionArchive = "archive"
+
+	// OrphanedMessageActionDelete deletes leaderboard messages the bot no longer updates
+	OrphanedMessageActionDelete = "delete"
+
+	// OrphanedMessageActionArchive leaves leaderboard messages the bot no longer
+	// updates in place with a note saying they are no longer updated
+	OrphanedMessageActionArchive = "archive"
+
+	// DisabledReasonGuildRemoved marks a server that we disabled ourselves
+	// because the bot was removed from the guild. These servers are re-enabled
+	// automatically if the bot is ever added back.
+	DisabledReasonGuildRemoved = "guild_removed"
+
+	// DefaultBoardName is the board managed by /configure. Servers
+	// configured before boards existed had their settings moved to it
+	DefaultBoardName = "default"
+
+	// DefaultTimezone is the timezone schedules run in until a server picks its own
+	DefaultTimezone = "UTC"
+
+	// DefaultCatchUpMaxLatenessHours is how late a missed scheduled post can be
+	// and still be posted when the bot starts again
+	DefaultCatchUpMaxLatenessHours = 24
+
+	// DefaultStaleDataMaxAgeHours is how old the last hiscores we fetched for a
+	// player can be and still be shown when fetching fresh hiscores fails
+	DefaultStaleDataMaxAgeHours = 168
+)
@@ -1,32 +1,167 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/bwmarrin/discordgo"
 )
 
+// ErrChannelNotFound is returned when a channel doesn't exist (anymore) in a guild
+var ErrChannelNotFound = errors.New("channel not found")
+
+// ErrChannelAmbiguous is returned when several channels share the name we're looking for
+var ErrChannelAmbiguous = errors.New("channel name is ambiguous")
+
+// ChannelPermission is a permission the bot needs in the channels it posts to
+type ChannelPermission struct {
+	Permission int64
+	Name       string
+}
+
+// RequiredChannelPermissions are the permissions the bot needs in a channel to post
+// leaderboards there. Keep these in line with the README's required permissions
+var RequiredChannelPermissions = []ChannelPermission{
+	{Permission: discordgo.PermissionViewChannel, Name: "View Channel"},
+	{Permission: discordgo.PermissionSendMessages, Name: "Send Messages"},
+	{Permission: discordgo.PermissionEmbedLinks, Name: "Embed Links"},
+	// Needed to find the messages we posted before so they can be edited or cleaned up
+	{Permission: discordgo.PermissionReadMessageHistory, Name: "Read Message History"},
+}
+
 // GetChannel is a helper function that returns a discord channel the "hard way" meaning we don't
-// attempt to use the cache and instead query discord for all the channels and return the one that matches
-// what the user asked for
-func GetChannel(s *discordgo.Session, guildID string, channelName string) (*discordgo.Channel, error) {
-	channels, err := s.GuildChannels(guildID)
+// attempt to use the cache and instead query discord directly for the channel the user asked for
+func GetChannel(s *discordgo.Session, guildID string, channelID string) (*discordgo.Channel, error) {
+	channel, err := s.Channel(channelID)
 	if err != nil {
+		if IsDiscordErrorCode(err, discordgo.ErrCodeUnknownChannel) {
+			return nil, fmt.Errorf("%w: %s in guild %s", ErrChannelNotFound, channelID, guildID)
+		}
 		return nil, err
 	}
 
-	var channel *discordgo.Channel = &discordgo.Channel{}
+	// A channel ID from another guild is as good as missing
+	if channel.GuildID != guildID {
+		return nil, fmt.Errorf("%w: %s in guild %s", ErrChannelNotFound, channelID, guildID)
+	}
+
+	return channel, nil
+}
+
+// FindChannelByName picks the text channel with the given name out of a guild's
+// channels. Names aren't unique so more than one match is an error as well
+func FindChannelByName(channels []*discordgo.Channel, channelName string) (*discordgo.Channel, error) {
+	var matches []*discordgo.Channel
 	for _, c := range channels {
-		if c.Name == channelName {
-			channel = c
-			break
+		if c.Name == channelName && c.Type == discordgo.ChannelTypeGuildText {
+			matches = append(matches, c)
 		}
+	}
 
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%w: #%s", ErrChannelNotFound, channelName)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("%w: there are %d channels named #%s", ErrChannelAmbiguous, len(matches), channelName)
 	}
+}
 
-	if channel.Name == "" {
-		return nil, fmt.Errorf("Unable to find channel %s in guild %s", channelName, guildID)
+// IsDiscordErrorCode checks if an error returned by the Discord API is a
+// specific JSON error code e.g. discordgo.ErrCodeUnknownMember
+func IsDiscordErrorCode(err error, code int) bool {
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Message != nil {
+		return restErr.Message.Code == code
 	}
 
-	return channel, nil
+	return false
+}
+
+// IsServerAdmin checks if the member who invoked an interaction
+// is allowed to manage the server
+func IsServerAdmin(member *discordgo.Member) bool {
+	if member == nil {
+		return false
+	}
+
+	return member.Permissions&discordgo.PermissionManageGuild != 0 ||
+		member.Permissions&discordgo.PermissionAdministrator != 0
+}
+
+// MissingChannelPermissions lists the required permissions the bot doesn't have
+// in a channel once the channel's permission overwrites are applied. Like GetChannel
+// everything is queried from Discord because the state cache may be incomplete
+func MissingChannelPermissions(s *discordgo.Session, guildID string, channelID string) ([]string, error) {
+	channel, err := GetChannel(s, guildID, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	guild, err := s.Guild(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := s.GuildMember(guildID, s.State.User.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := channelPermissions(guild, channel, member)
+
+	missing := []string{}
+	for _, required := range RequiredChannelPermissions {
+		if permissions&required.Permission != required.Permission {
+			missing = append(missing, required.Name)
+		}
+	}
+
+	return missing, nil
+}
+
+// channelPermissions works out a member's effective permissions in a channel
+// https://support.discord.com/hc/en-us/articles/206141927-How-is-the-permission-hierarchy-structured-
+func channelPermissions(guild *discordgo.Guild, channel *discordgo.Channel, member *discordgo.Member) int64 {
+	if member.User.ID == guild.OwnerID {
+		return discordgo.PermissionAll
+	}
+
+	// Everybody has the @everyone role, which shares the guild's ID
+	var permissions int64
+	for _, role := range guild.Roles {
+		if role.ID == guild.ID || slices.Contains(member.Roles, role.ID) {
+			permissions |= role.Permissions
+		}
+	}
+
+	if permissions&discordgo.PermissionAdministrator != 0 {
+		return discordgo.PermissionAll
+	}
+
+	// Overwrites apply @everyone first, then the member's roles, then the member
+	var roleAllow, roleDeny int64
+	for _, overwrite := range channel.PermissionOverwrites {
+		switch {
+		case overwrite.ID == guild.ID:
+			permissions &^= overwrite.Deny
+			permissions |= overwrite.Allow
+		case overwrite.Type == discordgo.PermissionOverwriteTypeRole && slices.Contains(member.Roles, overwrite.ID):
+			roleDeny |= overwrite.Deny
+			roleAllow |= overwrite.Allow
+		}
+	}
+	permissions &^= roleDeny
+	permissions |= roleAllow
+
+	for _, overwrite := range channel.PermissionOverwrites {
+		if overwrite.Type == discordgo.PermissionOverwriteTypeMember && overwrite.ID == member.User.ID {
+			permissions &^= overwrite.Deny
+			permissions |= overwrite.Allow
+		}
+	}
+
+	return permissions
 }
@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestChannelPermissions(t *testing.T) {
+	const (
+		guildID  = "1"
+		ownerID  = "2"
+		memberID = "3"
+		roleA    = "10"
+		roleB    = "11"
+		other    = "12"
+	)
+
+	send := int64(discordgo.PermissionSendMessages)
+	view := int64(discordgo.PermissionViewChannel)
+	embed := int64(discordgo.PermissionEmbedLinks)
+
+	role := func(id string, permissions int64) *discordgo.Role {
+		return &discordgo.Role{ID: id, Permissions: permissions}
+	}
+	overwrite := func(id string, overwriteType discordgo.PermissionOverwriteType, allow int64, deny int64) *discordgo.PermissionOverwrite {
+		return &discordgo.PermissionOverwrite{ID: id, Type: overwriteType, Allow: allow, Deny: deny}
+	}
+	roleType := discordgo.PermissionOverwriteTypeRole
+	memberType := discordgo.PermissionOverwriteTypeMember
+
+	tests := []struct {
+		name       string
+		memberID   string
+		roles      []*discordgo.Role
+		overwrites []*discordgo.PermissionOverwrite
+		want       int64
+	}{
+		{
+			name:     "the owner can do everything",
+			memberID: ownerID,
+			roles:    []*discordgo.Role{role(guildID, 0)},
+			want:     discordgo.PermissionAll,
+		},
+		{
+			name:     "administrators ignore overwrites",
+			memberID: memberID,
+			roles:    []*discordgo.Role{role(guildID, 0), role(roleA, discordgo.PermissionAdministrator)},
+			overwrites: []*discordgo.PermissionOverwrite{
+				overwrite(memberID, memberType, 0, view),
+			},
+			want: discordgo.PermissionAll,
+		},
+		{
+			name:     "guild roles are combined with @everyone",
+			memberID: memberID,
+			roles:    []*discordgo.Role{role(guildID, view), role(roleA, send), role(other, embed)},
+			want:     view | send,
+		},
+		{
+			name:     "@everyone overwrite denies",
+			memberID: memberID,
+			roles:    []*discordgo.Role{role(guildID, view|send)},
+			overwrites: []*discordgo.PermissionOverwrite{
+				overwrite(guildID, roleType, 0, send),
+			},
+			want: view,
+		},
+		{
+			name:     "role overwrite allows what @everyone denies",
+			memberID: memberID,
+			roles:    []*discordgo.Role{role(guildID, view|send), role(roleA, 0)},
+			overwrites: []*discordgo.PermissionOverwrite{
+				overwrite(guildID, roleType, 0, send),
+				overwrite(roleA, roleType, send, 0),
+			},
+			want: view | send,
+		},
+		{
+			name:     "a role's allow beats another role's deny",
+			memberID: memberID,
+			roles:    []*discordgo.Role{role(guildID, view), role(roleA, 0), role(roleB, 0)},
+			overwrites: []*discordgo.PermissionOverwrite{
+				overwrite(roleA, roleType, send, 0),
+				overwrite(roleB, roleType, 0, send),
+			},
+			want: view | send,
+		},
+		{
+			name:     "overwrites of roles the member doesn't have are ignored",
+			memberID: memberID,
+			roles:    []*discordgo.Role{role(guildID, view), role(roleA, 0)},
+			overwrites: []*discordgo.PermissionOverwrite{
+				overwrite(other, roleType, send, 0),
+			},
+			want: view,
+		},
+		{
+			name:     "member overwrite denies what a role allows",
+			memberID: memberID,
+			roles:    []*discordgo.Role{role(guildID, view), role(roleA, 0)},
+			overwrites: []*discordgo.PermissionOverwrite{
+				overwrite(roleA, roleType, send|embed, 0),
+				overwrite(memberID, memberType, 0, send),
+			},
+			want: view | embed,
+		},
+		{
+			name:     "member overwrite allows what everything else denies",
+			memberID: memberID,
+			roles:    []*discordgo.Role{role(guildID, view|send), role(roleA, 0)},
+			overwrites: []*discordgo.PermissionOverwrite{
+				overwrite(memberID, memberType, send, 0),
+				overwrite(guildID, roleType, 0, send),
+				overwrite(roleA, roleType, 0, send),
+			},
+			want: view | send,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guild := &discordgo.Guild{ID: guildID, OwnerID: ownerID, Roles: tt.roles}
+			channel := &discordgo.Channel{ID: "20", GuildID: guildID, PermissionOverwrites: tt.overwrites}
+			member := &discordgo.Member{User: &discordgo.User{ID: tt.memberID}, Roles: []string{roleA, roleB}}
+
+			if got := channelPermissions(guild, channel, member); got != tt.want {
+				t.Errorf("channelPermissions() = %b, want %b", got, tt.want)
+			}
+		})
+	}
+}
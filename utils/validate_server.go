@@ -2,28 +2,62 @@ package utils
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/robfig/cron/v3"
 
 	"github.com/michohl/osrs-clan-leaderboard/jet_schemas/model"
+	"github.com/michohl/osrs-clan-leaderboard/schedule"
 )
 
-// ValidateServerConfig takes a ServersRow struct parsed
-// out from our modal survey and then inspects all of the
+// ValidateBoardConfig takes a server and board parsed out
+// from our modal survey and then inspects all of the
 // individual values to decide if the output is good or not.
 //
 // If any errors are discovered they'll be returned as an
 // "pretty" error that we can send back to the user.
-func ValidateServerConfig(s *discordgo.Session, server model.Servers) error {
+func ValidateBoardConfig(s *discordgo.Session, server model.Servers, board model.Boards) error {
 
 	discoveredErrors := ""
 
-	if !IsValidCronExpression(server.Schedule) {
+	// An empty timezone would silently mean UTC so it isn't allowed
+	timezone := server.Timezone
+	_, err := time.LoadLocation(timezone)
+	if timezone == "" || err != nil {
+		discoveredErrors = fmt.Sprintf(
+			"%s\n* Unknown timezone: %s. Use a name like America/Chicago or Europe/London",
+			discoveredErrors,
+			timezone,
+		)
+		timezone = ""
+	}
+
+	if !IsValidCronExpression(schedule.WithTimezone(board.Schedule, timezone)) {
 		discoveredErrors = fmt.Sprintf(
 			"%s\n* Invalid cron expression: %s",
 			discoveredErrors,
-			server.Schedule,
+			board.Schedule,
+		)
+	}
+
+	// Without these the scheduled posts would fail long after the admin is gone
+	missing, err := MissingChannelPermissions(s, board.ServerID, board.ChannelID)
+	switch {
+	case err != nil:
+		discoveredErrors = fmt.Sprintf(
+			"%s\n* Unable to check the bot's permissions in #%s: %s",
+			discoveredErrors,
+			board.ChannelName,
+			err,
+		)
+	case len(missing) > 0:
+		discoveredErrors = fmt.Sprintf(
+			"%s\n* The bot is missing these permissions in #%s: %s",
+			discoveredErrors,
+			board.ChannelName,
+			strings.Join(missing, ", "),
 		)
 	}
 
@@ -31,7 +65,7 @@ func ValidateServerConfig(s *discordgo.Session, server model.Servers) error {
 		return nil
 	}
 
-	return fmt.Errorf("_Server Config Issues:_%s", discoveredErrors)
+	return fmt.Errorf("_Board Config Issues:_%s", discoveredErrors)
 }
 
 // IsValidCronExpression takes a cron expression and returns whether it is